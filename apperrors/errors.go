@@ -0,0 +1,111 @@
+package apperrors
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Kind phân loại lỗi nghiệp vụ, middleware dựa vào Kind để chọn HTTP status
+type Kind int
+
+const (
+	KindInternal     Kind = iota // Lỗi không xác định, trả về 500
+	KindNotFound                 // Resource không tồn tại
+	KindForbidden                // Không có quyền thao tác
+	KindUnauthorized             // Chưa đăng nhập hoặc sai thông tin đăng nhập
+	KindConflict                 // Trùng dữ liệu (email, username, ...)
+	KindValidation               // Dữ liệu đầu vào không hợp lệ
+	KindBadRequest               // Request sai về mặt nghiệp vụ
+)
+
+// Error là lỗi nghiệp vụ có Kind và message hiển thị cho client
+// Fields chứa lỗi chi tiết theo từng field, ví dụ {"email": ["is invalid"]}.
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  map[string][]string
+}
+
+// Error implement interface error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is cho phép errors.Is(err, apperrors.ErrNotFound) khớp với mọi lỗi cùng Kind
+// Sentinel chỉ có Kind (không có Message) được coi là đại diện cho cả Kind đó.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Message == "" && t.Fields == nil && t.Kind == e.Kind
+}
+
+// Sentinel cho từng Kind, dùng với errors.Is
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrBadRequest   = &Error{Kind: KindBadRequest}
+)
+
+// NotFound tạo lỗi resource không tồn tại
+func NotFound(message string) *Error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+// Forbidden tạo lỗi không có quyền
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+// Unauthorized tạo lỗi chưa xác thực
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+// Conflict tạo lỗi trùng dữ liệu
+func Conflict(message string) *Error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+// BadRequest tạo lỗi request không hợp lệ về nghiệp vụ
+func BadRequest(message string) *Error {
+	return &Error{Kind: KindBadRequest, Message: message}
+}
+
+// Validation tạo lỗi validation với chi tiết theo từng field
+// Message là tóm tắt các lỗi, ví dụ "email has already been taken", dùng cho log.
+func Validation(fields map[string][]string) *Error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{}
+	for _, name := range names {
+		for _, message := range fields[name] {
+			parts = append(parts, name+" "+message)
+		}
+	}
+
+	return &Error{Kind: KindValidation, Message: strings.Join(parts, "; "), Fields: fields}
+}
+
+// FieldError tạo lỗi validation cho một field
+func FieldError(field, message string) *Error {
+	return Validation(map[string][]string{field: {message}})
+}
+
+// As lấy *Error từ chuỗi lỗi err, trả về nil nếu err không phải lỗi nghiệp vụ
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorIs kiểm tra errors.Is khớp theo Kind với sentinel và theo con trỏ với lỗi cụ thể
+func TestErrorIs(t *testing.T) {
+	articleNotFound := NotFound("article not found")
+	wrapped := fmt.Errorf("get article: %w", articleNotFound)
+
+	assert.True(t, errors.Is(wrapped, ErrNotFound))
+	assert.True(t, errors.Is(wrapped, articleNotFound))
+	assert.False(t, errors.Is(wrapped, ErrForbidden))
+	assert.False(t, errors.Is(wrapped, NotFound("comment not found")))
+	assert.False(t, errors.Is(errors.New("article not found"), ErrNotFound))
+}
+
+// TestAs kiểm tra lấy *Error từ lỗi được wrap
+func TestAs(t *testing.T) {
+	err := fmt.Errorf("register: %w", FieldError("email", "has already been taken"))
+
+	appErr := As(err)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, KindValidation, appErr.Kind)
+		assert.Equal(t, []string{"has already been taken"}, appErr.Fields["email"])
+	}
+	assert.Nil(t, As(errors.New("plain")))
+}
@@ -3,6 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Các giá trị hợp lệ của STORAGE_DRIVER
+const (
+	StorageMySQL  = "mysql"  // Lưu dữ liệu trong MySQL (mặc định)
+	StorageMemory = "memory" // Lưu dữ liệu trong bộ nhớ, dùng cho test và demo local
 )
 
 // Config chứa tất cả các cấu hình của ứng dụng
@@ -14,8 +23,45 @@ type Config struct {
 	DBName     string
 	JWTSecret  string
 	Port       string
+	// StorageDriver chọn backend cho repositories: "mysql" hoặc "memory"
+	StorageDriver string
+	// AutoMigrate apply database migrations khi start server
+	AutoMigrate bool
+	// PublishInterval là chu kỳ worker kiểm tra articles hẹn giờ publish
+	PublishInterval time.Duration
+	// TrashRetention là thời gian articles/comments đã xóa nằm trong thùng rác trước khi bị xóa hẳn
+	TrashRetention time.Duration
+	// PurgeInterval là chu kỳ worker xóa hẳn các dòng quá hạn trong thùng rác
+	PurgeInterval time.Duration
+	// TagCacheTTL là thời gian cache danh sách tags kèm số articles và trending tags
+	TagCacheTTL time.Duration
+	// CommentMaxDepth là số cấp reply tối đa của comments, 0 là không cho trả lời comment
+	CommentMaxDepth int
+	// CommentEditWindow là thời gian sau khi đăng mà author còn sửa được comment
+	CommentEditWindow time.Duration
+
+	// Content filter chạy trước khi lưu articles và comments; mỗi rule có action riêng:
+	// "reject" (từ chối), "hold" (giữ lại chờ moderator duyệt) hoặc "flag" (cho đăng, gửi moderators xem xét)
+	// ContentBannedWords là các từ bị cấm (không phân biệt hoa thường), rỗng là tắt rule
+	ContentBannedWords       []string
+	ContentBannedWordsAction string
+	// ContentNewAccountAge là thời gian một account được coi là mới sau khi đăng ký
+	ContentNewAccountAge time.Duration
+	// ContentNewAccountMaxLinks là số links tối đa trong một article/comment của account mới
+	ContentNewAccountMaxLinks int
+	ContentLinksAction        string
+	// ContentDuplicateWindow là khoảng thời gian user đăng lại đúng nội dung cũ bị coi là trùng lặp
+	ContentDuplicateWindow time.Duration
+	ContentDuplicateAction string
 }
 
+// Các action hợp lệ của content filter
+const (
+	ContentActionReject = "reject"
+	ContentActionHold   = "hold"
+	ContentActionFlag   = "flag"
+)
+
 // LoadConfig đọc các biến môi trường và trả về Config
 // Nếu không có biến môi trường, sử dụng giá trị mặc định
 func LoadConfig() *Config {
@@ -27,6 +73,25 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "news_db"),
 		JWTSecret:  getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
 		Port:       getEnv("PORT", "8080"),
+
+		StorageDriver: getEnv("STORAGE_DRIVER", StorageMySQL),
+		AutoMigrate:   getEnv("AUTO_MIGRATE", "false") == "true",
+
+		PublishInterval: getDuration("PUBLISH_INTERVAL", 30*time.Second),
+		TrashRetention:  getDuration("TRASH_RETENTION", 30*24*time.Hour),
+		PurgeInterval:   getDuration("PURGE_INTERVAL", time.Hour),
+		TagCacheTTL:     getDuration("TAG_CACHE_TTL", 5*time.Minute),
+
+		CommentMaxDepth:   getInt("COMMENT_MAX_DEPTH", 5),
+		CommentEditWindow: getDuration("COMMENT_EDIT_WINDOW", 15*time.Minute),
+
+		ContentBannedWords:        getList("CONTENT_BANNED_WORDS"),
+		ContentBannedWordsAction:  getChoice("CONTENT_BANNED_WORDS_ACTION", ContentActionReject, ContentActionReject, ContentActionHold, ContentActionFlag),
+		ContentNewAccountAge:      getDuration("CONTENT_NEW_ACCOUNT_AGE", 72*time.Hour),
+		ContentNewAccountMaxLinks: getInt("CONTENT_NEW_ACCOUNT_MAX_LINKS", 2),
+		ContentLinksAction:        getChoice("CONTENT_LINKS_ACTION", ContentActionHold, ContentActionReject, ContentActionHold, ContentActionFlag),
+		ContentDuplicateWindow:    getDuration("CONTENT_DUPLICATE_WINDOW", 24*time.Hour),
+		ContentDuplicateAction:    getChoice("CONTENT_DUPLICATE_ACTION", ContentActionFlag, ContentActionReject, ContentActionHold, ContentActionFlag),
 	}
 }
 
@@ -44,3 +109,46 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getDuration đọc environment variable dạng duration (ví dụ "30s", "1m")
+// Nếu không có hoặc không hợp lệ (<= 0) thì dùng defaultValue.
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getInt đọc environment variable dạng số nguyên không âm
+// Nếu không có hoặc không hợp lệ (< 0) thì dùng defaultValue.
+func getInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getList đọc environment variable dạng danh sách phân cách bằng dấu phẩy, bỏ các phần tử rỗng
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getChoice đọc environment variable phải là một trong choices
+// Nếu không có hoặc không hợp lệ thì dùng defaultValue.
+func getChoice(key, defaultValue string, choices ...string) string {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	for _, choice := range choices {
+		if value == choice {
+			return value
+		}
+	}
+	return defaultValue
+}
@@ -1,9 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"news/apperrors"
 	"news/dto"
-	"news/middlewares"
 	"news/services"
 	"strconv"
 
@@ -16,9 +17,9 @@ type ArticleController struct {
 }
 
 // NewArticleController tạo instance mới của ArticleController
-func NewArticleController() *ArticleController {
+func NewArticleController(articleService *services.ArticleService) *ArticleController {
 	return &ArticleController{
-		articleService: services.NewArticleService(),
+		articleService: articleService,
 	}
 }
 
@@ -29,13 +30,13 @@ func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
@@ -43,14 +44,14 @@ func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 
 	// Bind request body
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service
 	response, err := c.articleService.CreateArticle(userIDInt, req)
 	if err != nil {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to create article")
+		ctx.Error(err)
 		return
 	}
 
@@ -74,11 +75,7 @@ func (c *ArticleController) GetArticle(ctx *gin.Context) {
 	// Gọi service
 	response, err := c.articleService.GetArticle(slug, currentUserID)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get article")
+		ctx.Error(err)
 		return
 	}
 
@@ -87,12 +84,16 @@ func (c *ArticleController) GetArticle(ctx *gin.Context) {
 
 // ListArticles lấy danh sách articles
 // GET /api/articles
-// Query params: tag, author, favorited, limit, offset
+// Query params: tag, tagMode, excludeTag, author, favorited, createdAfter, createdBefore, sort,
+// limit, offset, cursor (xem dto.ArticleListQuery)
 func (c *ArticleController) ListArticles(ctx *gin.Context) {
 	// Lấy query params
-	tag := ctx.Query("tag")
-	author := ctx.Query("author")
-	favorited := ctx.Query("favorited")
+	var query dto.ArticleListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	cursor := ctx.Query("cursor")
 
 	// Parse limit và offset
 	limit := 20 // default
@@ -122,22 +123,59 @@ func (c *ArticleController) ListArticles(ctx *gin.Context) {
 		}
 	}
 
-	// Convert string pointers
-	var tagPtr, authorPtr, favoritedPtr *string
-	if tag != "" {
-		tagPtr = &tag
+	// Gọi service
+	response, err := c.articleService.ListArticles(query, cursor, limit, offset, currentUserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// SearchArticles tìm kiếm articles theo từ khóa
+// GET /api/articles/search
+// Query params: q (bắt buộc), các filter giống ListArticles (trừ sort), limit, offset
+func (c *ArticleController) SearchArticles(ctx *gin.Context) {
+	q := ctx.Query("q")
+	var query dto.ArticleListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Parse limit và offset
+	limit := 20 // default
+	offset := 0 // default
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			if l > 100 {
+				limit = 100 // max limit
+			} else {
+				limit = l
+			}
+		}
 	}
-	if author != "" {
-		authorPtr = &author
+
+	if offsetStr := ctx.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
 	}
-	if favorited != "" {
-		favoritedPtr = &favorited
+
+	// Lấy userID từ context nếu có
+	var currentUserID *int
+	if userID, exists := ctx.Get("userID"); exists {
+		if userIDInt, ok := userID.(int); ok {
+			currentUserID = &userIDInt
+		}
 	}
 
 	// Gọi service
-	response, err := c.articleService.ListArticles(tagPtr, authorPtr, favoritedPtr, limit, offset, currentUserID)
+	response, err := c.articleService.SearchArticles(q, query, limit, offset, currentUserID)
 	if err != nil {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to list articles")
+		ctx.Error(err)
 		return
 	}
 
@@ -146,18 +184,19 @@ func (c *ArticleController) ListArticles(ctx *gin.Context) {
 
 // FeedArticles lấy articles từ users mà currentUser đang follow
 // GET /api/articles/feed
+// Query params: limit, offset, cursor
 // Authentication: required
 func (c *ArticleController) FeedArticles(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
@@ -182,9 +221,9 @@ func (c *ArticleController) FeedArticles(ctx *gin.Context) {
 	}
 
 	// Gọi service
-	response, err := c.articleService.FeedArticles(userIDInt, limit, offset)
+	response, err := c.articleService.FeedArticles(userIDInt, ctx.Query("cursor"), limit, offset)
 	if err != nil {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get feed")
+		ctx.Error(err)
 		return
 	}
 
@@ -200,13 +239,13 @@ func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
@@ -214,22 +253,14 @@ func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
 
 	// Bind request body
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service
 	response, err := c.articleService.UpdateArticle(slug, userIDInt, req)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		if err.Error() == "permission denied" {
-			middlewares.AbortWithError(ctx, http.StatusForbidden, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to update article")
+		ctx.Error(err)
 		return
 	}
 
@@ -245,28 +276,20 @@ func (c *ArticleController) DeleteArticle(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service
 	err := c.articleService.DeleteArticle(slug, userIDInt)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		if err.Error() == "permission denied" {
-			middlewares.AbortWithError(ctx, http.StatusForbidden, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to delete article")
+		ctx.Error(err)
 		return
 	}
 
@@ -282,24 +305,20 @@ func (c *ArticleController) FavoriteArticle(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service
 	response, err := c.articleService.FavoriteArticle(slug, userIDInt)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to favorite article")
+		ctx.Error(err)
 		return
 	}
 
@@ -315,24 +334,86 @@ func (c *ArticleController) UnfavoriteArticle(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service
 	response, err := c.articleService.UnfavoriteArticle(slug, userIDInt)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to unfavorite article")
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// PublishArticle publish article (draft hoặc archived)
+// POST /api/articles/:slug/publish
+// Authentication: required (chỉ author)
+func (c *ArticleController) PublishArticle(ctx *gin.Context) {
+	c.changeStatus(ctx, c.articleService.PublishArticle)
+}
+
+// UnpublishArticle chuyển article về draft
+// DELETE /api/articles/:slug/publish
+// Authentication: required (chỉ author)
+func (c *ArticleController) UnpublishArticle(ctx *gin.Context) {
+	c.changeStatus(ctx, c.articleService.UnpublishArticle)
+}
+
+// ArchiveArticle lưu trữ article
+// POST /api/articles/:slug/archive
+// Authentication: required (chỉ author)
+func (c *ArticleController) ArchiveArticle(ctx *gin.Context) {
+	c.changeStatus(ctx, c.articleService.ArchiveArticle)
+}
+
+// ScheduleArticle hẹn giờ publish article
+// POST /api/articles/:slug/schedule
+// Body: {"article": {"publishAt": "2030-01-01T08:00:00Z"}}
+// Authentication: required (chỉ author)
+func (c *ArticleController) ScheduleArticle(ctx *gin.Context) {
+	var req dto.ScheduleArticleRequest
+
+	// Bind request body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	c.changeStatus(ctx, func(slug string, authorID int) (*dto.ArticleResponse, error) {
+		return c.articleService.ScheduleArticle(slug, authorID, req)
+	})
+}
+
+// changeStatus xử lý chung cho các endpoint đổi status của article
+func (c *ArticleController) changeStatus(ctx *gin.Context, change func(slug string, authorID int) (*dto.ArticleResponse, error)) {
+	slug := ctx.Param("slug")
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := change(slug, userIDInt)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -1,9 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"news/apperrors"
 	"news/dto"
-	"news/middlewares"
 	"news/services"
 
 	"github.com/gin-gonic/gin"
@@ -15,9 +16,9 @@ type AuthController struct {
 }
 
 // NewAuthController tạo instance mới của AuthController
-func NewAuthController() *AuthController {
+func NewAuthController(authService *services.AuthService) *AuthController {
 	return &AuthController{
-		authService: services.NewAuthService(),
+		authService: authService,
 	}
 }
 
@@ -28,19 +29,14 @@ func (c *AuthController) Register(ctx *gin.Context) {
 
 	// Bind request body vào struct
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service để đăng ký
 	response, err := c.authService.Register(req)
 	if err != nil {
-		// Kiểm tra loại lỗi
-		if err.Error() == "email already exists" || err.Error() == "username already exists" {
-			middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to register user")
+		ctx.Error(err)
 		return
 	}
 
@@ -54,18 +50,14 @@ func (c *AuthController) Login(ctx *gin.Context) {
 
 	// Bind request body vào struct
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service để đăng nhập
 	response, err := c.authService.Login(req)
 	if err != nil {
-		if err.Error() == "invalid email or password" {
-			middlewares.AbortWithError(ctx, http.StatusUnauthorized, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to login")
+		ctx.Error(err)
 		return
 	}
 
@@ -79,25 +71,21 @@ func (c *AuthController) GetCurrentUser(ctx *gin.Context) {
 	// Lấy userID từ context (đã được set bởi auth middleware)
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	// Convert userID sang int
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service để lấy thông tin user
 	response, err := c.authService.GetCurrentUser(userIDInt)
 	if err != nil {
-		if err.Error() == "user not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get user")
+		ctx.Error(err)
 		return
 	}
 
@@ -111,14 +99,14 @@ func (c *AuthController) UpdateCurrentUser(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	// Convert userID sang int
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
@@ -126,22 +114,14 @@ func (c *AuthController) UpdateCurrentUser(ctx *gin.Context) {
 
 	// Bind request body vào struct
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service để cập nhật user
 	response, err := c.authService.UpdateUser(userIDInt, req)
 	if err != nil {
-		if err.Error() == "user not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		if err.Error() == "email already exists" || err.Error() == "username already exists" {
-			middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to update user")
+		ctx.Error(err)
 		return
 	}
 
@@ -1,9 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"news/apperrors"
 	"news/dto"
-	"news/middlewares"
 	"news/services"
 	"strconv"
 
@@ -16,9 +17,9 @@ type CommentController struct {
 }
 
 // NewCommentController tạo instance mới của CommentController
-func NewCommentController() *CommentController {
+func NewCommentController(commentService *services.CommentService) *CommentController {
 	return &CommentController{
-		commentService: services.NewCommentService(),
+		commentService: commentService,
 	}
 }
 
@@ -31,13 +32,13 @@ func (c *CommentController) AddComment(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
@@ -45,30 +46,46 @@ func (c *CommentController) AddComment(ctx *gin.Context) {
 
 	// Bind request body
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		middlewares.AbortWithError(ctx, http.StatusUnprocessableEntity, err.Error())
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Gọi service
 	response, err := c.commentService.AddComment(slug, userIDInt, req)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to add comment")
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
 
-// GetComments lấy tất cả comments của article
+// GetComments lấy comments của article
 // GET /api/articles/:slug/comments
+// Query params: sort (newest, oldest, top), limit, cursor (không có limit thì trả về tất cả comments)
 // Authentication: optional
 func (c *CommentController) GetComments(ctx *gin.Context) {
 	slug := ctx.Param("slug")
 
+	// Bind query parameters
+	var query dto.CommentListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Parse limit, 0 là không phân trang
+	limit := 0
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			if l > 100 {
+				limit = 100 // max limit
+			} else {
+				limit = l
+			}
+		}
+	}
+
 	// Lấy userID từ context nếu có
 	var currentUserID *int
 	if userID, exists := ctx.Get("userID"); exists {
@@ -78,19 +95,179 @@ func (c *CommentController) GetComments(ctx *gin.Context) {
 	}
 
 	// Gọi service
-	response, err := c.commentService.GetComments(slug, currentUserID)
+	response, err := c.commentService.GetComments(slug, query, ctx.Query("cursor"), limit, currentUserID)
 	if err != nil {
-		if err.Error() == "article not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get comments")
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateComment sửa comment
+// PUT /api/articles/:slug/comments/:id
+// Authentication: required (chỉ author, trong thời gian cho phép sửa)
+func (c *CommentController) UpdateComment(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	commentIDStr := ctx.Param("id")
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+
+	// Bind request body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	response, err := c.commentService.UpdateComment(slug, commentID, userIDInt, req)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
 
+// ListCommentEdits lấy lịch sử chỉnh sửa của comment
+// GET /api/articles/:slug/comments/:id/edits
+// Authentication: required (moderator)
+func (c *CommentController) ListCommentEdits(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	commentIDStr := ctx.Param("id")
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.commentService.ListCommentEdits(slug, commentID, userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ReportComment report comment kèm lý do để moderators xem xét
+// POST /api/articles/:slug/comments/:id/report
+// Authentication: required
+func (c *CommentController) ReportComment(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	commentIDStr := ctx.Param("id")
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	var req dto.ReportCommentRequest
+
+	// Bind request body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	err = c.commentService.ReportComment(slug, commentID, userIDInt, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// HideComment ẩn comment trên article của user hiện tại
+// POST /api/articles/:slug/comments/:id/hide
+// Authentication: required (chỉ author của article)
+func (c *CommentController) HideComment(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	commentIDStr := ctx.Param("id")
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	err = c.commentService.HideComment(slug, commentID, userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
 // DeleteComment xóa comment
 // DELETE /api/articles/:slug/comments/:id
 // Authentication: required
@@ -101,35 +278,27 @@ func (c *CommentController) DeleteComment(ctx *gin.Context) {
 	// Parse comment ID
 	commentID, err := strconv.Atoi(commentIDStr)
 	if err != nil {
-		middlewares.AbortWithError(ctx, http.StatusBadRequest, "Invalid comment ID")
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
 		return
 	}
 
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service
 	err = c.commentService.DeleteComment(slug, commentID, userIDInt)
 	if err != nil {
-		if err.Error() == "article not found" || err.Error() == "comment not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		if err.Error() == "permission denied" {
-			middlewares.AbortWithError(ctx, http.StatusForbidden, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to delete comment")
+		ctx.Error(err)
 		return
 	}
 
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"news/apperrors"
+	"news/dto"
+	"news/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationController xử lý các HTTP request kiểm duyệt comments bị report và articles chờ xem xét
+type ModerationController struct {
+	moderationService *services.ModerationService
+}
+
+// NewModerationController tạo instance mới của ModerationController
+func NewModerationController(moderationService *services.ModerationService) *ModerationController {
+	return &ModerationController{
+		moderationService: moderationService,
+	}
+}
+
+// ListReportedComments lấy hàng đợi kiểm duyệt comments bị report
+// GET /api/moderation/comments?limit=20&offset=0
+// Authentication: required (moderator)
+func (c *ModerationController) ListReportedComments(ctx *gin.Context) {
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Bind query parameters
+	var query dto.ModerationQueueQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	response, err := c.moderationService.ListReportedComments(userIDInt, query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ModerateComment xử lý comment bị report (dismiss, approve, hide, delete, ban)
+// POST /api/moderation/comments/:id
+// Authentication: required (moderator)
+func (c *ModerationController) ModerateComment(ctx *gin.Context) {
+	commentIDStr := ctx.Param("id")
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	var req dto.ModerateCommentRequest
+
+	// Bind request body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	err = c.moderationService.ModerateComment(commentID, userIDInt, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// ListArticlesForReview lấy hàng đợi kiểm duyệt articles bị content filter gửi lên
+// GET /api/moderation/articles?limit=20&offset=0
+// Authentication: required (moderator)
+func (c *ModerationController) ListArticlesForReview(ctx *gin.Context) {
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Bind query parameters
+	var query dto.ModerationQueueQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	response, err := c.moderationService.ListArticlesForReview(userIDInt, query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ModerateArticle xử lý article đang chờ xem xét (approve, delete)
+// POST /api/moderation/articles/:slug
+// Authentication: required (moderator)
+func (c *ModerationController) ModerateArticle(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	var req dto.ModerateArticleRequest
+
+	// Bind request body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	err := c.moderationService.ModerateArticle(slug, userIDInt, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
@@ -1,8 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"news/middlewares"
+	"news/apperrors"
 	"news/services"
 
 	"github.com/gin-gonic/gin"
@@ -14,9 +15,9 @@ type ProfileController struct {
 }
 
 // NewProfileController tạo instance mới của ProfileController
-func NewProfileController() *ProfileController {
+func NewProfileController(profileService *services.ProfileService) *ProfileController {
 	return &ProfileController{
-		profileService: services.NewProfileService(),
+		profileService: profileService,
 	}
 }
 
@@ -37,11 +38,7 @@ func (c *ProfileController) GetProfile(ctx *gin.Context) {
 	// Gọi service để lấy profile
 	response, err := c.profileService.GetProfile(username, currentUserID)
 	if err != nil {
-		if err.Error() == "user not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get profile")
+		ctx.Error(err)
 		return
 	}
 
@@ -57,29 +54,21 @@ func (c *ProfileController) FollowUser(ctx *gin.Context) {
 	// Lấy userID từ context (đã được set bởi auth middleware)
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	// Convert userID sang int
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service để follow user
 	response, err := c.profileService.FollowUser(userIDInt, username)
 	if err != nil {
-		if err.Error() == "user not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		if err.Error() == "cannot follow yourself" {
-			middlewares.AbortWithError(ctx, http.StatusBadRequest, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to follow user")
+		ctx.Error(err)
 		return
 	}
 
@@ -95,25 +84,21 @@ func (c *ProfileController) UnfollowUser(ctx *gin.Context) {
 	// Lấy userID từ context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		middlewares.AbortWithError(ctx, http.StatusUnauthorized, "Authentication required")
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
 		return
 	}
 
 	// Convert userID sang int
 	userIDInt, ok := userID.(int)
 	if !ok {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Invalid user ID")
+		ctx.Error(errors.New("invalid user ID in context"))
 		return
 	}
 
 	// Gọi service để unfollow user
 	response, err := c.profileService.UnfollowUser(userIDInt, username)
 	if err != nil {
-		if err.Error() == "user not found" {
-			middlewares.AbortWithError(ctx, http.StatusNotFound, err.Error())
-			return
-		}
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to unfollow user")
+		ctx.Error(err)
 		return
 	}
 
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"news/apperrors"
+	"news/dto"
+	"news/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevisionController xử lý các HTTP request liên quan đến lịch sử chỉnh sửa articles
+type RevisionController struct {
+	revisionService *services.RevisionService
+}
+
+// NewRevisionController tạo instance mới của RevisionController
+func NewRevisionController(revisionService *services.RevisionService) *RevisionController {
+	return &RevisionController{
+		revisionService: revisionService,
+	}
+}
+
+// ListRevisions lấy danh sách revisions của article, mới nhất trước
+// GET /api/articles/:slug/revisions
+// Authentication: optional
+func (c *RevisionController) ListRevisions(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// Lấy userID từ context nếu có
+	var currentUserID *int
+	if userID, exists := ctx.Get("userID"); exists {
+		if userIDInt, ok := userID.(int); ok {
+			currentUserID = &userIDInt
+		}
+	}
+
+	// Gọi service
+	response, err := c.revisionService.ListRevisions(slug, currentUserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetRevision lấy toàn bộ nội dung của một revision
+// GET /api/articles/:slug/revisions/:n
+// Authentication: optional
+func (c *RevisionController) GetRevision(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// Parse revision number
+	number, err := strconv.Atoi(ctx.Param("n"))
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid revision number"))
+		return
+	}
+
+	// Lấy userID từ context nếu có
+	var currentUserID *int
+	if userID, exists := ctx.Get("userID"); exists {
+		if userIDInt, ok := userID.(int); ok {
+			currentUserID = &userIDInt
+		}
+	}
+
+	// Gọi service
+	response, err := c.revisionService.GetRevision(slug, number, currentUserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DiffRevisions so sánh từng dòng giữa hai revisions
+// GET /api/articles/:slug/revisions/diff
+// Query params: from, to (mặc định: revision mới nhất so với revision trước nó)
+// Authentication: optional
+func (c *RevisionController) DiffRevisions(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	var query dto.RevisionDiffQuery
+
+	// Bind query params
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Lấy userID từ context nếu có
+	var currentUserID *int
+	if userID, exists := ctx.Get("userID"); exists {
+		if userIDInt, ok := userID.(int); ok {
+			currentUserID = &userIDInt
+		}
+	}
+
+	// Gọi service
+	response, err := c.revisionService.DiffRevisions(slug, query, currentUserID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RestoreRevision khôi phục nội dung article về một revision
+// POST /api/articles/:slug/revisions/:n/restore
+// Authentication: required (chỉ author của article)
+func (c *RevisionController) RestoreRevision(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// Parse revision number
+	number, err := strconv.Atoi(ctx.Param("n"))
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid revision number"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.revisionService.RestoreRevision(slug, number, userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
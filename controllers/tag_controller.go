@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"news/middlewares"
+	"news/apperrors"
+	"news/dto"
 	"news/services"
 
 	"github.com/gin-gonic/gin"
@@ -14,20 +16,173 @@ type TagController struct {
 }
 
 // NewTagController tạo instance mới của TagController
-func NewTagController() *TagController {
+func NewTagController(tagService *services.TagService) *TagController {
 	return &TagController{
-		tagService: services.NewTagService(),
+		tagService: tagService,
 	}
 }
 
-// GetTags lấy tất cả tags
-// GET /api/tags
+// GetTags lấy tất cả tags kèm số articles đã publish
+// GET /api/tags?sort=popular&limit=20
 // Authentication: not required
 func (c *TagController) GetTags(ctx *gin.Context) {
+	// Bind query parameters
+	var query dto.TagListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	response, err := c.tagService.GetAllTags(query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// TrendingTags xếp hạng tags theo mức tăng số articles gần đây
+// GET /api/tags/trending?window=7d&limit=10
+// Authentication: not required
+func (c *TagController) TrendingTags(ctx *gin.Context) {
+	// Bind query parameters
+	var query dto.TrendingTagsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Gọi service
+	response, err := c.tagService.GetTrendingTags(query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListAliases lấy tất cả aliases của tags
+// GET /api/tags/aliases
+// Authentication: required (admin)
+func (c *TagController) ListAliases(ctx *gin.Context) {
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.tagService.ListAliases(userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateAlias tạo alias cho tag
+// POST /api/tags/aliases
+// Authentication: required (admin)
+func (c *TagController) CreateAlias(ctx *gin.Context) {
+	var req dto.CreateTagAliasRequest
+
+	// Bind JSON request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.tagService.CreateAlias(userIDInt, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteAlias xóa alias của tag
+// DELETE /api/tags/aliases/:alias
+// Authentication: required (admin)
+func (c *TagController) DeleteAlias(ctx *gin.Context) {
+	alias := ctx.Param("alias")
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	if err := c.tagService.DeleteAlias(userIDInt, alias); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// MergeTags gộp một tag vào tag khác
+// POST /api/tags/merge
+// Authentication: required (admin)
+func (c *TagController) MergeTags(ctx *gin.Context) {
+	var req dto.MergeTagsRequest
+
+	// Bind JSON request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
 	// Gọi service
-	response, err := c.tagService.GetAllTags()
+	response, err := c.tagService.MergeTags(userIDInt, req)
 	if err != nil {
-		middlewares.AbortWithError(ctx, http.StatusInternalServerError, "Failed to get tags")
+		ctx.Error(err)
 		return
 	}
 
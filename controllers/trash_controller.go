@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"news/apperrors"
+	"news/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashController xử lý các HTTP request liên quan đến thùng rác của user hiện tại
+type TrashController struct {
+	trashService *services.TrashService
+}
+
+// NewTrashController tạo instance mới của TrashController
+func NewTrashController(trashService *services.TrashService) *TrashController {
+	return &TrashController{
+		trashService: trashService,
+	}
+}
+
+// GetTrash lấy articles và comments đã xóa của user hiện tại
+// GET /api/user/trash
+// Authentication: required
+func (c *TrashController) GetTrash(ctx *gin.Context) {
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.trashService.GetTrash(userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RestoreArticle khôi phục article trong thùng rác
+// POST /api/user/trash/articles/:slug/restore
+// Authentication: required
+func (c *TrashController) RestoreArticle(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.trashService.RestoreArticle(slug, userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RestoreComment khôi phục comment trong thùng rác
+// POST /api/user/trash/comments/:id/restore
+// Authentication: required
+func (c *TrashController) RestoreComment(ctx *gin.Context) {
+	// Parse comment ID
+	commentID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperrors.BadRequest("Invalid comment ID"))
+		return
+	}
+
+	// Lấy userID từ context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.Error(apperrors.Unauthorized("Authentication required"))
+		return
+	}
+
+	userIDInt, ok := userID.(int)
+	if !ok {
+		ctx.Error(errors.New("invalid user ID in context"))
+		return
+	}
+
+	// Gọi service
+	response, err := c.trashService.RestoreComment(commentID, userIDInt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
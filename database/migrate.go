@@ -0,0 +1,378 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles chứa các file migration dạng NNNN_name.up.sql / NNNN_name.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration là một bước thay đổi schema có version
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// migrationHooks là các bước viết bằng Go chạy sau SQL của migration up cùng version, trước khi
+// migration được ghi nhận đã apply; hook phải idempotent vì có thể chạy lại khi migrate up bị gián đoạn.
+var migrationHooks = map[int]func(db *sql.DB) error{
+	// 0013: chuẩn hóa tags đã có bằng utils.NormalizeTag, cùng quy tắc với tags mới
+	13: normalizeTags,
+}
+
+// MigrationStatus cho biết một migration đã được apply hay chưa
+// AppliedStatements > 0 khi migration chưa apply xong: up bị lỗi sau khi đã chạy được chừng đó statements.
+type MigrationStatus struct {
+	Migration
+	AppliedAt         *time.Time
+	AppliedStatements int
+}
+
+const (
+	// migrationLockName là tên advisory lock (GET_LOCK) giữ trong lúc apply/rollback migrations
+	// để nhiều replica cùng start với AUTO_MIGRATE không chạy trùng một migration.
+	migrationLockName = "schema_migrations"
+	// migrationLockTimeout là thời gian tối đa chờ process khác chạy migrations xong
+	migrationLockTimeout = 5 * time.Minute
+)
+
+// createMigrationsTable tạo bảng schema_migrations để lưu các version đã apply
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`
+
+// createMigrationProgressTable tạo bảng lưu số statements đã chạy của migration đang dở
+// MySQL tự commit sau mỗi lệnh DDL nên migration lỗi giữa chừng không rollback được; lần chạy sau
+// đọc tiến độ để tiếp tục từ statement bị lỗi thay vì chạy lại các statements đã apply.
+const createMigrationProgressTable = `CREATE TABLE IF NOT EXISTS schema_migration_progress (
+    version INT NOT NULL,
+    direction VARCHAR(4) NOT NULL,
+    statements INT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (version, direction)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`
+
+// LoadMigrations đọc các migration được embed, sắp xếp theo version tăng dần
+// Mỗi version phải có đủ cả file up và down.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: file name must end with .up.sql or .down.sql", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s: file name must look like 0001_name.%s.sql", fileName, direction)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", fileName, versionStr)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: missing up or down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp apply tất cả migrations chưa được apply, trả về các migration vừa chạy
+// Migration lỗi giữa chừng được tiếp tục từ statement bị lỗi ở lần chạy sau.
+func MigrateUp(db *sql.DB) ([]Migration, error) {
+	var applied []Migration
+	err := withMigrationLock(db, func() error {
+		statuses, err := GetMigrationStatus(db)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			if status.AppliedAt != nil {
+				continue
+			}
+			if err := runMigration(db, status.Version, "up", status.Migration.Up); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", status.Version, status.Name, err)
+			}
+			if hook, ok := migrationHooks[status.Version]; ok {
+				if err := hook(db); err != nil {
+					return fmt.Errorf("migration %04d_%s up hook: %w", status.Version, status.Name, err)
+				}
+			}
+			if err := finishMigration(db, status.Migration, "up"); err != nil {
+				return err
+			}
+			applied = append(applied, status.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown rollback steps migrations mới nhất đã được apply, trả về các migration vừa rollback
+func MigrateDown(db *sql.DB, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+	err := withMigrationLock(db, func() error {
+		statuses, err := GetMigrationStatus(db)
+		if err != nil {
+			return err
+		}
+
+		for i := len(statuses) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			status := statuses[i]
+			if status.AppliedAt == nil {
+				continue
+			}
+			if err := runMigration(db, status.Version, "down", status.Migration.Down); err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", status.Version, status.Name, err)
+			}
+			if err := finishMigration(db, status.Migration, "down"); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, status.Migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// withMigrationLock chạy fn trong lúc giữ advisory lock migrationLockName
+// GET_LOCK gắn với session nên lock được giữ trên một connection riêng tới khi fn xong; process khác
+// chờ tối đa migrationLockTimeout rồi đọc lại trạng thái nên không chạy lại migrations vừa được apply.
+func withMigrationLock(db *sql.DB, fn func() error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, migrationLockName, int(migrationLockTimeout/time.Second)).Scan(&acquired)
+	if err != nil {
+		return err
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("timed out after %s waiting for migration lock %q", migrationLockTimeout, migrationLockName)
+	}
+	defer func() {
+		var released sql.NullInt64
+		if err := conn.QueryRowContext(ctx, `SELECT RELEASE_LOCK(?)`, migrationLockName).Scan(&released); err != nil {
+			// Không nhả được lock: bỏ connection khỏi pool để lock kết thúc cùng session
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return fn()
+}
+
+// GetMigrationStatus trả về tất cả migrations kèm thời điểm apply (nil nếu chưa apply)
+func GetMigrationStatus(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(createMigrationsTable); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createMigrationProgressTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedAt := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	progress, err := getMigrationProgress(db, "up")
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m}
+		if at, ok := appliedAt[m.Version]; ok {
+			status.AppliedAt = &at
+		} else {
+			status.AppliedStatements = progress[m.Version]
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// getMigrationProgress trả về số statements đã chạy của các migrations đang dở theo direction
+func getMigrationProgress(db *sql.DB, direction string) (map[int]int, error) {
+	rows, err := db.Query(`SELECT version, statements FROM schema_migration_progress WHERE direction = ?`, direction)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := map[int]int{}
+	for rows.Next() {
+		var version, statements int
+		if err := rows.Scan(&version, &statements); err != nil {
+			return nil, err
+		}
+		progress[version] = statements
+	}
+	return progress, rows.Err()
+}
+
+// runMigration chạy lần lượt từng statement trong script của migration version theo direction ("up"/"down")
+// MySQL tự commit sau mỗi lệnh DDL nên không bọc trong transaction; số statements đã chạy được ghi vào
+// schema_migration_progress sau mỗi statement để lần chạy sau bỏ qua chúng.
+func runMigration(db *sql.DB, version int, direction, script string) error {
+	var done int
+	err := db.QueryRow(`SELECT statements FROM schema_migration_progress WHERE version = ? AND direction = ?`, version, direction).Scan(&done)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	statements, err := pendingStatements(script, done)
+	if err != nil {
+		return err
+	}
+	for i, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("statement %d: %w", done+i+1, err)
+		}
+		_, err := db.Exec(`INSERT INTO schema_migration_progress (version, direction, statements) VALUES (?, ?, ?)
+		          ON DUPLICATE KEY UPDATE statements = VALUES(statements)`, version, direction, done+i+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingStatements trả về các statements của script chưa chạy khi đã chạy được done statements đầu
+func pendingStatements(script string, done int) ([]string, error) {
+	statements := splitStatements(script)
+	if done > len(statements) {
+		return nil, fmt.Errorf("progress records %d statements but script has only %d", done, len(statements))
+	}
+	return statements[done:], nil
+}
+
+// finishMigration ghi nhận migration đã apply (up) hoặc đã rollback (down) và xóa tiến độ của nó
+// trong cùng transaction, để tiến độ cũ không bị dùng lại khi migration chạy lần sau.
+func finishMigration(db *sql.DB, m Migration, direction string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if direction == "up" {
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.Version, m.Name)
+	} else {
+		_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM schema_migration_progress WHERE version = ?`, m.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements tách script SQL thành các statement theo dấu ";" ở cuối dòng
+// Bỏ qua các dòng comment "--" và dòng trống.
+func splitStatements(script string) []string {
+	var statements []string
+	var current []string
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSpace(strings.Join(current, "\n"))
+			statements = append(statements, strings.TrimSuffix(statement, ";"))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+
+	return statements
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadMigrations kiểm tra các migration embed được load đúng thứ tự và đủ up/down
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Up, "migration %d missing up", m.Version)
+		assert.NotEmpty(t, m.Down, "migration %d missing down", m.Version)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+// TestSplitStatements kiểm tra tách script SQL thành từng statement
+func TestSplitStatements(t *testing.T) {
+	script := `-- comment đầu file
+CREATE TABLE a (
+    id INT PRIMARY KEY
+);
+
+-- comment giữa
+DROP TABLE b;
+ALTER TABLE c ADD COLUMN d INT`
+
+	statements := splitStatements(script)
+	require.Len(t, statements, 3)
+	assert.Equal(t, "CREATE TABLE a (\n    id INT PRIMARY KEY\n)", statements[0])
+	assert.Equal(t, "DROP TABLE b", statements[1])
+	assert.Equal(t, "ALTER TABLE c ADD COLUMN d INT", statements[2])
+}
+
+// TestInitMigrationHasAllTables kiểm tra migration 0001 tạo đủ các bảng ban đầu
+func TestInitMigrationHasAllTables(t *testing.T) {
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+
+	up := splitStatements(migrations[0].Up)
+	down := splitStatements(migrations[0].Down)
+	assert.Len(t, up, 7)
+	assert.Len(t, down, 7)
+}
+
+// TestPendingStatements kiểm tra migration dở dang tiếp tục từ statement chưa chạy
+func TestPendingStatements(t *testing.T) {
+	script := "CREATE TABLE a (id INT);\nALTER TABLE a ADD COLUMN b INT;\nCREATE INDEX idx_b ON a (b);"
+
+	statements, err := pendingStatements(script, 0)
+	require.NoError(t, err)
+	assert.Len(t, statements, 3)
+
+	statements, err = pendingStatements(script, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATE INDEX idx_b ON a (b)"}, statements)
+
+	statements, err = pendingStatements(script, 3)
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+
+	// Script bị sửa ngắn đi sau khi đã chạy một phần
+	_, err = pendingStatements(script, 4)
+	assert.Error(t, err)
+}
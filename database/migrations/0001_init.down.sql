@@ -0,0 +1,9 @@
+-- 0001: xóa schema ban đầu (thứ tự ngược với foreign keys)
+
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS article_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS articles;
+DROP TABLE IF EXISTS users;
@@ -1,8 +1,5 @@
--- Database schema cho RealWorld API
-
--- Tạo database nếu chưa tồn tại
-CREATE DATABASE IF NOT EXISTS news_db;
-USE news_db;
+-- 0001: schema ban đầu cho RealWorld API
+-- Database được chọn qua DSN (DB_NAME), không dùng USE ở đây
 
 -- Bảng users: lưu thông tin người dùng
 CREATE TABLE IF NOT EXISTS users (
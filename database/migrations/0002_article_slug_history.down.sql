@@ -0,0 +1,3 @@
+-- 0002: xóa bảng lịch sử slug
+
+DROP TABLE IF EXISTS article_slug_history;
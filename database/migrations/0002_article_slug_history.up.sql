@@ -0,0 +1,11 @@
+-- 0002: lưu các slug cũ của article để redirect link cũ sau khi đổi title
+
+-- Bảng article_slug_history: mỗi slug cũ trỏ về article hiện tại
+-- slug là primary key nên một slug cũ chỉ thuộc về một article
+CREATE TABLE IF NOT EXISTS article_slug_history (
+    slug VARCHAR(255) NOT NULL PRIMARY KEY,
+    article_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    INDEX idx_article_id (article_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
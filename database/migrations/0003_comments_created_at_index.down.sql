@@ -0,0 +1,3 @@
+-- 0003: xóa index keyset pagination của comments
+
+DROP INDEX idx_article_created_at ON comments;
@@ -0,0 +1,5 @@
+-- 0003: index cho keyset pagination của comments theo (article_id, created_at, id)
+-- InnoDB tự thêm primary key id vào cuối secondary index nên không cần khai báo id.
+-- articles đã có idx_created_at (tương đương (created_at, id)) từ 0001.
+
+CREATE INDEX idx_article_created_at ON comments (article_id, created_at);
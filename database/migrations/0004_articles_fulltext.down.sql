@@ -0,0 +1,5 @@
+-- 0004: xóa FULLTEXT index tìm kiếm articles
+
+ALTER TABLE articles DROP INDEX ft_articles_title;
+
+ALTER TABLE articles DROP INDEX ft_articles_content;
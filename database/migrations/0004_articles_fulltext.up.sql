@@ -0,0 +1,6 @@
+-- 0004: FULLTEXT index cho tìm kiếm articles (GET /api/articles/search)
+-- ft_articles_title dùng để cộng điểm khi từ khóa xuất hiện trong title.
+
+ALTER TABLE articles ADD FULLTEXT INDEX ft_articles_content (title, description, body);
+
+ALTER TABLE articles ADD FULLTEXT INDEX ft_articles_title (title);
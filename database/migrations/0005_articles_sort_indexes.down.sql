@@ -0,0 +1,4 @@
+-- 0005: xóa index sắp xếp articles
+
+DROP INDEX idx_updated_at ON articles;
+DROP INDEX idx_favorites_count ON articles;
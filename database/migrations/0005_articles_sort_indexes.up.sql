@@ -0,0 +1,5 @@
+-- 0005: index cho sort=mostFavorited và sort=recentlyUpdated của danh sách articles
+-- InnoDB tự thêm primary key id vào cuối secondary index nên keyset (key, id) dùng được index.
+
+CREATE INDEX idx_favorites_count ON articles (favorites_count);
+CREATE INDEX idx_updated_at ON articles (updated_at);
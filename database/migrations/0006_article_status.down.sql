@@ -0,0 +1,7 @@
+-- 0006: xóa trạng thái của articles
+
+DROP INDEX idx_status_published_at ON articles;
+
+ALTER TABLE articles
+    DROP COLUMN published_at,
+    DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- 0006: trạng thái draft/published/archived của articles
+-- Articles đã có trước đó đều là published, published_at lấy theo created_at.
+
+ALTER TABLE articles
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' AFTER body,
+    ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER favorites_count;
+
+UPDATE articles SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX idx_status_published_at ON articles (status, published_at);
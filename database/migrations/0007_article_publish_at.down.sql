@@ -0,0 +1,7 @@
+-- 0007: xóa hẹn giờ publish, articles đang hẹn giờ trở về draft
+
+UPDATE articles SET status = 'draft' WHERE status = 'scheduled';
+
+DROP INDEX idx_status_publish_at ON articles;
+
+ALTER TABLE articles DROP COLUMN publish_at;
@@ -0,0 +1,6 @@
+-- 0007: hẹn giờ publish articles
+-- Article có status scheduled được worker publish khi publish_at <= hiện tại.
+
+ALTER TABLE articles ADD COLUMN publish_at TIMESTAMP NULL DEFAULT NULL AFTER published_at;
+
+CREATE INDEX idx_status_publish_at ON articles (status, publish_at);
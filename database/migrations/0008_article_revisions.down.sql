@@ -0,0 +1,3 @@
+-- 0008: xóa bảng lịch sử chỉnh sửa
+
+DROP TABLE IF EXISTS article_revisions;
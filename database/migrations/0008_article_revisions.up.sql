@@ -0,0 +1,21 @@
+-- 0008: lưu lịch sử chỉnh sửa của articles
+
+-- Bảng article_revisions: mỗi lần tạo/sửa article lưu một bản chụp đầy đủ title, description, body
+-- number đánh số revision trong từng article, bắt đầu từ 1
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    article_id INT NOT NULL,
+    number INT NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    body TEXT NOT NULL,
+    editor_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_article_number (article_id, number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Articles đã có được coi như revision 1 với nội dung hiện tại
+INSERT INTO article_revisions (article_id, number, title, description, body, editor_id, created_at)
+SELECT id, 1, title, description, body, author_id, updated_at FROM articles;
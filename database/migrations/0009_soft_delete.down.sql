@@ -0,0 +1,13 @@
+-- 0009: bỏ xóa mềm, các dòng đang trong thùng rác bị xóa hẳn
+
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_comments_deleted_at ON comments;
+
+ALTER TABLE comments DROP COLUMN deleted_at;
+
+DELETE FROM articles WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_articles_deleted_at ON articles;
+
+ALTER TABLE articles DROP COLUMN deleted_at;
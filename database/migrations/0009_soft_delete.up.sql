@@ -0,0 +1,10 @@
+-- 0009: xóa mềm articles và comments
+-- Dòng có deleted_at nằm trong thùng rác của author, bị xóa hẳn khi quá thời gian lưu giữ.
+
+ALTER TABLE articles ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE INDEX idx_articles_deleted_at ON articles (deleted_at);
+
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
@@ -0,0 +1,7 @@
+-- 0010: bỏ HTML đã render
+
+ALTER TABLE comments DROP COLUMN body_html;
+
+ALTER TABLE article_revisions DROP COLUMN body_html;
+
+ALTER TABLE articles DROP COLUMN body_html;
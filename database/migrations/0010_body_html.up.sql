@@ -0,0 +1,8 @@
+-- 0010: lưu HTML đã render từ Markdown của articles, revisions và comments
+-- Render một lần khi ghi để list endpoints không phải render lại; NULL với dữ liệu cũ (render khi đọc).
+
+ALTER TABLE articles ADD COLUMN body_html MEDIUMTEXT NULL AFTER body;
+
+ALTER TABLE article_revisions ADD COLUMN body_html MEDIUMTEXT NULL AFTER body;
+
+ALTER TABLE comments ADD COLUMN body_html MEDIUMTEXT NULL AFTER body;
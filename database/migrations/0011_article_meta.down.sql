@@ -0,0 +1,7 @@
+-- 0011: bỏ thông tin suy ra từ body
+
+ALTER TABLE articles
+    DROP COLUMN toc,
+    DROP COLUMN excerpt,
+    DROP COLUMN reading_time,
+    DROP COLUMN word_count;
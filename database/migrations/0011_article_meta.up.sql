@@ -0,0 +1,8 @@
+-- 0011: lưu thông tin suy ra từ body của articles: số từ, thời gian đọc, đoạn trích và mục lục
+-- Tính một lần khi tạo/sửa body; NULL với dữ liệu cũ (tính khi đọc).
+
+ALTER TABLE articles
+    ADD COLUMN word_count INT NULL AFTER body_html,
+    ADD COLUMN reading_time INT NULL AFTER word_count,
+    ADD COLUMN excerpt TEXT NULL AFTER reading_time,
+    ADD COLUMN toc JSON NULL AFTER excerpt;
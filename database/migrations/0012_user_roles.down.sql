@@ -0,0 +1,3 @@
+-- 0012: bỏ role của users
+
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,4 @@
+-- 0012: role của users, admin quản lý được dữ liệu chung (tags)
+-- Cấp quyền admin bằng lệnh: news role <username> admin
+
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' AFTER image;
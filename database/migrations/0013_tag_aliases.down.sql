@@ -0,0 +1,3 @@
+-- 0013: bỏ alias của tags (tên tags đã chuẩn hóa và các tags đã gộp không được khôi phục)
+
+DROP TABLE IF EXISTS tag_aliases;
@@ -0,0 +1,14 @@
+-- 0013: alias của tags và chuẩn hóa tên tags đã có
+
+-- Bảng tag_aliases: tên khác của một tag (ví dụ "golang" -> "go"), do admin tạo
+-- Tag được gắn bằng alias sẽ được lưu thành tag gốc.
+CREATE TABLE IF NOT EXISTS tag_aliases (
+    alias VARCHAR(100) NOT NULL PRIMARY KEY,
+    tag_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+    INDEX idx_tag_id (tag_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Tags đã có được chuẩn hóa bằng utils.NormalizeTag trong hook Go của migration này
+-- (database.normalizeTags), không làm bằng SQL để dùng đúng quy tắc với tags mới.
@@ -0,0 +1,9 @@
+-- 0014: bỏ replies, mọi comment trở thành comment gốc
+
+ALTER TABLE comments DROP FOREIGN KEY fk_comments_parent;
+
+ALTER TABLE comments DROP INDEX fk_comments_parent;
+
+ALTER TABLE comments DROP COLUMN depth;
+
+ALTER TABLE comments DROP COLUMN parent_id;
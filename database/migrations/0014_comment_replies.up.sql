@@ -0,0 +1,9 @@
+-- 0014: trả lời comments (threaded comments)
+-- depth = 0 với comment gốc, reply có depth của parent + 1. Comment có replies không bị xóa hẳn
+-- khỏi thùng rác (vẫn hiển thị "[deleted]"), ON DELETE CASCADE chỉ dùng khi article bị xóa hẳn.
+
+ALTER TABLE comments ADD COLUMN parent_id INT NULL DEFAULT NULL AFTER author_id;
+
+ALTER TABLE comments ADD COLUMN depth INT NOT NULL DEFAULT 0 AFTER parent_id;
+
+ALTER TABLE comments ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE;
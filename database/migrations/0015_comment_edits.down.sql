@@ -0,0 +1,5 @@
+-- 0015: bỏ lịch sử chỉnh sửa comments
+
+DROP TABLE IF EXISTS comment_edits;
+
+ALTER TABLE comments DROP COLUMN edited_at;
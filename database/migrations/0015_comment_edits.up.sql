@@ -0,0 +1,15 @@
+-- 0015: sửa comments và lưu lịch sử chỉnh sửa
+
+-- edited_at là thời điểm sửa gần nhất, NULL nếu comment chưa từng được sửa
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMP NULL DEFAULT NULL AFTER body_html;
+
+-- Bảng comment_edits: mỗi lần sửa lưu lại body trước khi sửa, chỉ moderators xem được
+CREATE TABLE IF NOT EXISTS comment_edits (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    comment_id INT NOT NULL,
+    body TEXT NOT NULL,
+    body_html MEDIUMTEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    INDEX idx_comment_id (comment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
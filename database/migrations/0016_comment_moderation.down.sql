@@ -0,0 +1,9 @@
+-- 0016: bỏ kiểm duyệt comments
+
+DROP TABLE IF EXISTS comment_moderation_actions;
+
+DROP TABLE IF EXISTS comment_reports;
+
+ALTER TABLE users DROP COLUMN banned_at;
+
+ALTER TABLE comments DROP COLUMN hidden_at;
@@ -0,0 +1,35 @@
+-- 0016: kiểm duyệt comments (report, ẩn comment, ban author)
+
+-- hidden_at là thời điểm comment bị ẩn bởi author của article hoặc moderator, NULL nếu đang hiển thị
+ALTER TABLE comments ADD COLUMN hidden_at TIMESTAMP NULL DEFAULT NULL AFTER edited_at;
+
+-- banned_at là thời điểm user bị moderator cấm bình luận, NULL nếu không bị cấm
+ALTER TABLE users ADD COLUMN banned_at TIMESTAMP NULL DEFAULT NULL AFTER role;
+
+-- Bảng comment_reports: reader report comment kèm lý do, mỗi reader report một comment một lần
+-- resolved_at khác NULL khi moderator đã xử lý report (report đã xử lý không còn trong hàng đợi)
+CREATE TABLE IF NOT EXISTS comment_reports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    comment_id INT NOT NULL,
+    reporter_id INT NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_comment_reporter (comment_id, reporter_id),
+    INDEX idx_resolved_at (resolved_at, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Bảng comment_moderation_actions: ai đã làm gì (dismiss, hide, delete, ban) với comment, lúc nào
+-- actor_id là moderator, hoặc author của article khi author ẩn comment trên article của mình
+CREATE TABLE IF NOT EXISTS comment_moderation_actions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    comment_id INT NOT NULL,
+    actor_id INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_comment_id (comment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
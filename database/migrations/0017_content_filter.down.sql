@@ -0,0 +1,9 @@
+-- 0017: bỏ content filter
+
+DELETE FROM comment_reports WHERE reporter_id IS NULL;
+
+ALTER TABLE comment_reports MODIFY reporter_id INT NOT NULL;
+
+UPDATE articles SET status = 'draft' WHERE status = 'pending';
+
+ALTER TABLE articles DROP COLUMN review_reason;
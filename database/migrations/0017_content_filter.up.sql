@@ -0,0 +1,8 @@
+-- 0017: content filter cho articles và comments
+
+-- review_reason là lý do content filter gửi article cho moderators xem xét, NULL nếu không cần xem xét
+-- Article bị giữ lại chờ duyệt có status 'pending', chỉ author xem được tới khi moderator duyệt.
+ALTER TABLE articles ADD COLUMN review_reason VARCHAR(500) NULL DEFAULT NULL AFTER publish_at;
+
+-- Report do content filter tạo (comment bị giữ lại hoặc bị gắn cờ) không có reporter
+ALTER TABLE comment_reports MODIFY reporter_id INT NULL;
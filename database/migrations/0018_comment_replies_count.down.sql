@@ -0,0 +1,5 @@
+-- 0018: bỏ replies_count lưu trên comments
+
+DROP INDEX idx_article_replies_count ON comments;
+
+ALTER TABLE comments DROP COLUMN replies_count;
@@ -0,0 +1,22 @@
+-- 0018: lưu số replies đang hiển thị trên từng comment
+-- replies_count là số replies trực tiếp đang hiển thị: reply chưa xóa, chưa bị ẩn, hoặc còn reply đang hiển thị
+-- bên dưới. Comment đã xóa/bị ẩn có replies_count > 0 được giữ làm placeholder trong danh sách comments.
+-- Repository cập nhật cột này khi comment được tạo, xóa, khôi phục, ẩn hoặc hiện lại.
+
+ALTER TABLE comments ADD COLUMN replies_count INT NOT NULL DEFAULT 0 AFTER depth;
+
+-- Tính replies_count cho comments đã có: visible là comments đang hiển thị cùng mọi tổ tiên của chúng
+UPDATE comments c
+INNER JOIN (
+    WITH RECURSIVE visible (id, parent_id) AS (
+        SELECT id, parent_id FROM comments WHERE deleted_at IS NULL AND hidden_at IS NULL
+        UNION
+        SELECT p.id, p.parent_id FROM comments p JOIN visible v ON p.id = v.parent_id
+    )
+    SELECT parent_id, COUNT(*) AS replies_count FROM visible WHERE parent_id IS NOT NULL GROUP BY parent_id
+) rc ON rc.parent_id = c.id
+SET c.replies_count = rc.replies_count;
+
+-- Index cho sort top (replies_count DESC, id DESC) trong danh sách comments của article
+-- InnoDB tự thêm primary key id vào cuối secondary index nên không cần khai báo id.
+CREATE INDEX idx_article_replies_count ON comments (article_id, replies_count);
@@ -0,0 +1,6 @@
+-- 0019: body của articles và revisions trở lại TEXT
+-- Lỗi nếu còn body dài hơn 65.535 bytes (strict mode); cần rút gọn các body đó trước khi rollback.
+
+ALTER TABLE article_revisions MODIFY body TEXT NOT NULL;
+
+ALTER TABLE articles MODIFY body TEXT NOT NULL;
@@ -0,0 +1,7 @@
+-- 0019: body của articles và revisions dùng MEDIUMTEXT
+-- TEXT chỉ chứa tối đa 65.535 bytes, nhỏ hơn giới hạn 100000 ký tự của API (tới 4 bytes mỗi ký tự utf8mb4);
+-- MEDIUMTEXT chứa tới 16 MB. FULLTEXT index ft_articles_content được MySQL build lại.
+
+ALTER TABLE articles MODIFY body MEDIUMTEXT NOT NULL;
+
+ALTER TABLE article_revisions MODIFY body MEDIUMTEXT NOT NULL;
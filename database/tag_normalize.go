@@ -0,0 +1,93 @@
+package database
+
+import (
+	"database/sql"
+	"news/utils"
+)
+
+// tagRow là id và tên hiện tại của một tag
+type tagRow struct {
+	ID   int
+	Name string
+}
+
+// tagNormalizationPlan là các thay đổi cần làm để mọi tag có tên chuẩn
+type tagNormalizationPlan struct {
+	renames map[int]string // tag id -> tên chuẩn
+	merges  map[int]int    // tag id bị gộp -> tag id được giữ
+}
+
+// planTagNormalization tính tên chuẩn (utils.NormalizeTag) của các tags theo thứ tự id tăng dần
+// Các tags có cùng tên chuẩn được gộp vào tag có id nhỏ nhất; tag có tên không hợp lệ được giữ nguyên.
+func planTagNormalization(tags []tagRow) tagNormalizationPlan {
+	plan := tagNormalizationPlan{renames: map[int]string{}, merges: map[int]int{}}
+	keepers := map[string]int{}
+	for _, tag := range tags {
+		canonical, err := utils.NormalizeTag(tag.Name)
+		if err != nil {
+			continue
+		}
+		if keepID, ok := keepers[canonical]; ok {
+			plan.merges[tag.ID] = keepID
+			continue
+		}
+		keepers[canonical] = tag.ID
+		if canonical != tag.Name {
+			plan.renames[tag.ID] = canonical
+		}
+	}
+	return plan
+}
+
+// normalizeTags chuẩn hóa tên mọi tags đã có bằng utils.NormalizeTag, cùng quy tắc với tags mới được gắn
+// Tags trùng tên chuẩn được gộp vào tag có id nhỏ nhất (articles và aliases chuyển sang tag đó), tag có tên
+// không hợp lệ được giữ nguyên. Chạy trong một transaction và idempotent: chạy lại không thay đổi gì.
+func normalizeTags(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, name FROM tags ORDER BY id FOR UPDATE`)
+	if err != nil {
+		return err
+	}
+	var tags []tagRow
+	for rows.Next() {
+		var tag tagRow
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			rows.Close()
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	plan := planTagNormalization(tags)
+
+	// Gộp trước khi đổi tên để tên chuẩn không còn bị tag trùng giữ (tags.name là UNIQUE)
+	for fromID, toID := range plan.merges {
+		if _, err := tx.Exec(`INSERT IGNORE INTO article_tags (article_id, tag_id)
+		                      SELECT article_id, ? FROM article_tags WHERE tag_id = ?`, toID, fromID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE tag_aliases SET tag_id = ? WHERE tag_id = ?`, toID, fromID); err != nil {
+			return err
+		}
+		// article_tags còn lại của tag bị gộp được xóa theo (ON DELETE CASCADE)
+		if _, err := tx.Exec(`DELETE FROM tags WHERE id = ?`, fromID); err != nil {
+			return err
+		}
+	}
+	for id, name := range plan.renames {
+		if _, err := tx.Exec(`UPDATE tags SET name = ? WHERE id = ?`, name, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlanTagNormalization kiểm tra tags được chuẩn hóa bằng utils.NormalizeTag và gộp vào tag có id nhỏ nhất,
+// tag có tên không hợp lệ được giữ nguyên
+func TestPlanTagNormalization(t *testing.T) {
+	plan := planTagNormalization([]tagRow{
+		{ID: 1, Name: "go"},
+		{ID: 2, Name: "Go"},
+		{ID: 3, Name: "Go\t Lang"},
+		{ID: 4, Name: "go-lang"},
+		{ID: 5, Name: "cafe\u0301"}, // NFD của "café"
+		{ID: 6, Name: "café"},
+		{ID: 7, Name: "c++"},
+		{ID: 8, Name: "bad/tag"},
+		{ID: 9, Name: "  "},
+	})
+
+	assert.Equal(t, map[int]string{3: "go-lang", 5: "café"}, plan.renames)
+	assert.Equal(t, map[int]int{2: 1, 4: 3, 6: 5}, plan.merges)
+}
+
+// TestPlanTagNormalization_Idempotent kiểm tra tags đã chuẩn hóa không cần thay đổi gì
+func TestPlanTagNormalization_Idempotent(t *testing.T) {
+	plan := planTagNormalization([]tagRow{{ID: 1, Name: "go"}, {ID: 2, Name: "go-lang"}, {ID: 3, Name: "café"}})
+
+	assert.Empty(t, plan.renames)
+	assert.Empty(t, plan.merges)
+}
@@ -0,0 +1,16 @@
+package database
+
+import "database/sql"
+
+// DBTX là tập method chung của *sql.DB và *sql.Tx
+// Repositories nhận DBTX để cùng một code chạy được cả ngoài và trong transaction.
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+var (
+	_ DBTX = (*sql.DB)(nil)
+	_ DBTX = (*sql.Tx)(nil)
+)
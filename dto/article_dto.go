@@ -1,40 +1,74 @@
 package dto
 
+import "time"
+
 // CreateArticleRequest định dạng request body cho tạo article
 // Theo RealWorld spec: {"article": {"title": "...", "description": "...", "body": "...", "tagList": [...]}}
+// description có thể để trống, excerpt trong response khi đó được trích từ body.
+// status là "published" (mặc định, như RealWorld spec) hoặc "draft".
+// publishAt (RFC3339, ở tương lai) hẹn giờ publish, article được tạo với status "scheduled".
+// body tối đa 100000 ký tự.
 type CreateArticleRequest struct {
 	Article struct {
-		Title       string   `json:"title" binding:"required"`
-		Description string   `json:"description" binding:"required"`
-		Body        string   `json:"body" binding:"required"`
-		TagList     []string `json:"tagList,omitempty"`
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description"`
+		Body        string     `json:"body" binding:"required,max=100000"`
+		TagList     []string   `json:"tagList,omitempty"`
+		Status      string     `json:"status,omitempty" binding:"omitempty,oneof=draft published"`
+		PublishAt   *time.Time `json:"publishAt,omitempty"`
+	} `json:"article" binding:"required"`
+}
+
+// ScheduleArticleRequest định dạng request body cho hẹn giờ publish article
+// {"article": {"publishAt": "2030-01-01T08:00:00Z"}}
+type ScheduleArticleRequest struct {
+	Article struct {
+		PublishAt time.Time `json:"publishAt" binding:"required"`
 	} `json:"article" binding:"required"`
 }
 
 // UpdateArticleRequest định dạng request body cho cập nhật article
 // Tất cả các field đều optional
+// tagList thay thế toàn bộ tags ([] để bỏ hết tags); addTags/removeTags thêm/bớt tags,
+// được áp dụng sau tagList nếu gửi cùng lúc.
 type UpdateArticleRequest struct {
 	Article struct {
-		Title       *string `json:"title,omitempty"`
-		Description *string `json:"description,omitempty"`
-		Body        *string `json:"body,omitempty"`
+		Title       *string   `json:"title,omitempty"`
+		Description *string   `json:"description,omitempty"`
+		Body        *string   `json:"body,omitempty" binding:"omitempty,max=100000"`
+		TagList     *[]string `json:"tagList,omitempty"`
+		AddTags     []string  `json:"addTags,omitempty"`
+		RemoveTags  []string  `json:"removeTags,omitempty"`
 	} `json:"article"`
 }
 
 // ArticleResponse định dạng response theo RealWorld spec
 // {"article": {...}}
+// bodyHtml là body render từ Markdown (CommonMark, tables, fenced code, autolinks) đã sanitize,
+// client hiển thị được ngay; client theo RealWorld spec có thể bỏ qua và dùng body.
+// wordCount, readingTime (phút), excerpt và tableOfContents được tính từ body; excerpt là
+// description nếu có, ngược lại là đoạn trích văn bản thuần đầu body.
 type ArticleResponse struct {
 	Article struct {
-		Slug           string   `json:"slug"`
-		Title          string   `json:"title"`
-		Description    string   `json:"description"`
-		Body           string   `json:"body"`
-		TagList        []string `json:"tagList"`
-		CreatedAt      string   `json:"createdAt"`
-		UpdatedAt      string   `json:"updatedAt"`
-		Favorited      bool     `json:"favorited"`
-		FavoritesCount int      `json:"favoritesCount"`
-		Author         struct {
+		Slug            string     `json:"slug"`
+		Title           string     `json:"title"`
+		Description     string     `json:"description"`
+		Body            string     `json:"body"`
+		BodyHTML        string     `json:"bodyHtml"`
+		WordCount       int        `json:"wordCount"`
+		ReadingTime     int        `json:"readingTime"`
+		Excerpt         string     `json:"excerpt"`
+		TableOfContents []TOCEntry `json:"tableOfContents"`
+		TagList         []string   `json:"tagList"`
+		Status          string     `json:"status"`
+		PublishedAt     *string    `json:"publishedAt"`
+		PublishAt       *string    `json:"publishAt"`
+		CreatedAt       string     `json:"createdAt"`
+		UpdatedAt       string     `json:"updatedAt"`
+		Favorited       bool       `json:"favorited"`
+		FavoritesCount  int        `json:"favoritesCount"`
+		CommentsCount   int        `json:"commentsCount"` // comments chưa xóa, không bị ẩn, gồm cả replies
+		Author          struct {
 			Username  string  `json:"username"`
 			Bio       *string `json:"bio"`
 			Image     *string `json:"image"`
@@ -43,9 +77,50 @@ type ArticleResponse struct {
 	} `json:"article"`
 }
 
+// TOCEntry là một mục trong mục lục của article
+// id trùng với thuộc tính id của heading trong bodyHtml, dùng làm anchor (#id).
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// ArticleListQuery là các query params lọc và sắp xếp danh sách articles
+// GET /api/articles?tag=go&tag=web&tagMode=all&excludeTag=draft&author=jake,jane&sort=mostFavorited
+// Param nhiều giá trị nhận cả dạng lặp lại (tag=a&tag=b) lẫn phân cách bằng dấu phẩy (tag=a,b).
+// createdAfter/createdBefore nhận RFC3339 hoặc YYYY-MM-DD.
+type ArticleListQuery struct {
+	Tag           []string `form:"tag"`
+	TagMode       string   `form:"tagMode"` // any (mặc định) | all
+	ExcludeTag    []string `form:"excludeTag"`
+	Author        []string `form:"author"`
+	Favorited     string   `form:"favorited"`
+	CreatedAfter  string   `form:"createdAfter"`  // bao gồm thời điểm này
+	CreatedBefore string   `form:"createdBefore"` // không bao gồm; YYYY-MM-DD thì tính cả ngày đó
+	Sort          string   `form:"sort"`          // recent (mặc định) | oldest | mostFavorited | recentlyUpdated
+}
+
 // ArticleListResponse định dạng response cho list articles
-// {"articles": [...], "articlesCount": 10}
+// {"articles": [...], "articlesCount": 10, "nextCursor": "..."}
+// nextCursor là null khi không còn trang sau
 type ArticleListResponse struct {
 	Articles      []ArticleResponse `json:"articles"`
 	ArticlesCount int               `json:"articlesCount"`
+	NextCursor    *string           `json:"nextCursor"`
+}
+
+// ArticleSearchResult là một kết quả tìm kiếm: article kèm điểm relevance và đoạn trích
+// {"article": {...}, "score": 1.5, "snippet": "... <mark>golang</mark> ..."}
+// snippet đã được HTML escape, từ khớp được bọc trong <mark></mark>
+type ArticleSearchResult struct {
+	ArticleResponse
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// ArticleSearchResponse định dạng response cho tìm kiếm articles
+// {"articles": [...], "articlesCount": 10}
+type ArticleSearchResponse struct {
+	Articles      []ArticleSearchResult `json:"articles"`
+	ArticlesCount int                   `json:"articlesCount"`
 }
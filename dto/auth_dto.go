@@ -20,12 +20,12 @@ type LoginRequest struct {
 }
 
 // UpdateUserRequest định dạng request body cho cập nhật user
-// Tất cả các field đều optional
+// Tất cả các field đều optional, nhưng nếu gửi lên thì phải hợp lệ
 type UpdateUserRequest struct {
 	User struct {
-		Email    *string `json:"email,omitempty"`
-		Username *string `json:"username,omitempty"`
-		Password *string `json:"password,omitempty"`
+		Email    *string `json:"email,omitempty" binding:"omitnil,min=1,email"`
+		Username *string `json:"username,omitempty" binding:"omitnil,min=1"`
+		Password *string `json:"password,omitempty" binding:"omitnil,min=6"`
 		Bio      *string `json:"bio,omitempty"`
 		Image    *string `json:"image,omitempty"`
 	} `json:"user"`
@@ -1,8 +1,17 @@
 package dto
 
 // CreateCommentRequest định dạng request body cho tạo comment
-// Theo RealWorld spec: {"comment": {"body": "..."}}
+// Theo RealWorld spec: {"comment": {"body": "..."}}, thêm parentId để trả lời một comment
 type CreateCommentRequest struct {
+	Comment struct {
+		Body     string `json:"body" binding:"required"`
+		ParentID *int   `json:"parentId"`
+	} `json:"comment" binding:"required"`
+}
+
+// UpdateCommentRequest định dạng request body cho sửa comment
+// {"comment": {"body": "..."}}
+type UpdateCommentRequest struct {
 	Comment struct {
 		Body string `json:"body" binding:"required"`
 	} `json:"comment" binding:"required"`
@@ -10,13 +19,22 @@ type CreateCommentRequest struct {
 
 // CommentResponse định dạng response theo RealWorld spec
 // {"comment": {...}}
+// Comment đã xóa còn replies có deleted = true, body "[deleted]" và author rỗng; comment bị ẩn
+// còn replies có hidden = true, body "[hidden]" và author rỗng; riêng author của comment bị ẩn vẫn nhận body thật.
 type CommentResponse struct {
 	Comment struct {
-		ID        int    `json:"id"`
-		Body      string `json:"body"`
-		CreatedAt string `json:"createdAt"`
-		UpdatedAt string `json:"updatedAt"`
-		Author    struct {
+		ID           int    `json:"id"`
+		ParentID     *int   `json:"parentId"` // null với comment gốc
+		Depth        int    `json:"depth"`
+		RepliesCount int    `json:"repliesCount"`
+		Deleted      bool   `json:"deleted"`
+		Hidden       bool   `json:"hidden"`
+		Edited       bool   `json:"edited"` // author đã sửa comment, updatedAt là lần sửa gần nhất
+		Body         string `json:"body"`
+		BodyHTML     string `json:"bodyHtml"` // body render từ Markdown đã sanitize
+		CreatedAt    string `json:"createdAt"`
+		UpdatedAt    string `json:"updatedAt"`
+		Author       struct {
 			Username  string  `json:"username"`
 			Bio       *string `json:"bio"`
 			Image     *string `json:"image"`
@@ -25,8 +43,38 @@ type CommentResponse struct {
 	} `json:"comment"`
 }
 
+// CommentListQuery là các query params của list comments
+// GET /api/articles/:slug/comments?sort=top&limit=20&cursor=...
+type CommentListQuery struct {
+	Sort string `form:"sort"` // newest (mặc định) | oldest | top (nhiều replies nhất)
+}
+
 // CommentListResponse định dạng response cho list comments
-// {"comments": [...]}
+// {"comments": [...], "nextCursor": "..."}
+// nextCursor là null khi không còn trang sau hoặc không phân trang
 type CommentListResponse struct {
-	Comments []CommentResponse `json:"comments"`
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor *string           `json:"nextCursor"`
+}
+
+// CommentEdit là body của comment trước một lần sửa
+type CommentEdit struct {
+	Body     string `json:"body"`
+	BodyHTML string `json:"bodyHtml"`
+	EditedAt string `json:"editedAt"` // thời điểm body này bị thay thế
+}
+
+// CommentEditListResponse định dạng response cho lịch sử chỉnh sửa comment, lần sửa mới nhất trước
+// {"edits": [...], "editsCount": 2}
+type CommentEditListResponse struct {
+	Edits      []CommentEdit `json:"edits"`
+	EditsCount int           `json:"editsCount"`
+}
+
+// ReportCommentRequest định dạng request body cho report comment
+// {"report": {"reason": "spam"}}
+type ReportCommentRequest struct {
+	Report struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	} `json:"report" binding:"required"`
 }
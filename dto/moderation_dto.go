@@ -0,0 +1,87 @@
+package dto
+
+// ModerationQueueQuery là các query params của hàng đợi kiểm duyệt comments và articles
+// GET /api/moderation/comments?limit=20&offset=0
+type ModerationQueueQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"` // mặc định 20
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+// ModerateCommentRequest định dạng request body cho xử lý comment bị report
+// {"moderation": {"action": "dismiss" | "approve" | "hide" | "delete" | "ban"}}
+type ModerateCommentRequest struct {
+	Moderation struct {
+		Action string `json:"action" binding:"required,oneof=dismiss approve hide delete ban"`
+	} `json:"moderation" binding:"required"`
+}
+
+// ModerateArticleRequest định dạng request body cho xử lý article đang chờ xem xét
+// {"moderation": {"action": "approve" | "delete"}}
+type ModerateArticleRequest struct {
+	Moderation struct {
+		Action string `json:"action" binding:"required,oneof=approve delete"`
+	} `json:"moderation" binding:"required"`
+}
+
+// CommentReport là một report chưa xử lý trong hàng đợi kiểm duyệt
+type CommentReport struct {
+	Reporter  *string `json:"reporter"` // username của reader đã report, null với report do content filter tạo
+	Reason    string  `json:"reason"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+// CommentModerationAction là một hành động kiểm duyệt đã thực hiện với comment
+type CommentModerationAction struct {
+	Action    string `json:"action"`
+	Actor     string `json:"actor"` // username của moderator hoặc author của article
+	CreatedAt string `json:"createdAt"`
+}
+
+// ReportedComment là một comment trong hàng đợi kiểm duyệt
+// reports là các reports chưa xử lý (sớm nhất trước), actions là lịch sử kiểm duyệt (mới nhất trước).
+type ReportedComment struct {
+	ID              int    `json:"id"`
+	ArticleSlug     string `json:"articleSlug"`
+	Body            string `json:"body"`
+	BodyHTML        string `json:"bodyHtml"`
+	Hidden          bool   `json:"hidden"`
+	CreatedAt       string `json:"createdAt"`
+	FirstReportedAt string `json:"firstReportedAt"`
+	ReportsCount    int    `json:"reportsCount"`
+	Author          struct {
+		Username string `json:"username"`
+		Banned   bool   `json:"banned"`
+	} `json:"author"`
+	Reports []CommentReport           `json:"reports"`
+	Actions []CommentModerationAction `json:"actions"`
+}
+
+// ReportedCommentListResponse định dạng response cho hàng đợi kiểm duyệt comments
+// {"comments": [...], "commentsCount": 3}
+type ReportedCommentListResponse struct {
+	Comments      []ReportedComment `json:"comments"`
+	CommentsCount int               `json:"commentsCount"`
+}
+
+// ReviewArticle là một article trong hàng đợi kiểm duyệt
+// reviewReason là lý do content filter gửi article lên, status pending là article đang bị giữ lại.
+type ReviewArticle struct {
+	Slug         string `json:"slug"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Body         string `json:"body"`
+	Status       string `json:"status"`
+	ReviewReason string `json:"reviewReason"`
+	UpdatedAt    string `json:"updatedAt"`
+	Author       struct {
+		Username string `json:"username"`
+		Banned   bool   `json:"banned"`
+	} `json:"author"`
+}
+
+// ReviewArticleListResponse định dạng response cho hàng đợi kiểm duyệt articles
+// {"articles": [...], "articlesCount": 3}
+type ReviewArticleListResponse struct {
+	Articles      []ReviewArticle `json:"articles"`
+	ArticlesCount int             `json:"articlesCount"`
+}
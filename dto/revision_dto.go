@@ -0,0 +1,60 @@
+package dto
+
+// RevisionSummary là thông tin chung của một revision, dùng trong danh sách
+type RevisionSummary struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	CreatedAt string `json:"createdAt"`
+	Editor    struct {
+		Username string  `json:"username"`
+		Bio      *string `json:"bio"`
+		Image    *string `json:"image"`
+	} `json:"editor"`
+}
+
+// RevisionListResponse định dạng response cho list revisions, mới nhất trước
+// {"revisions": [...], "revisionsCount": 3}
+type RevisionListResponse struct {
+	Revisions      []RevisionSummary `json:"revisions"`
+	RevisionsCount int               `json:"revisionsCount"`
+}
+
+// RevisionResponse định dạng response cho một revision kèm toàn bộ nội dung
+// {"revision": {"number": 2, "title": "...", "description": "...", "body": "...", ...}}
+type RevisionResponse struct {
+	Revision struct {
+		RevisionSummary
+		Description string `json:"description"`
+		Body        string `json:"body"`
+		BodyHTML    string `json:"bodyHtml"`
+	} `json:"revision"`
+}
+
+// RevisionDiffQuery là các query params của diff giữa hai revisions
+// GET /api/articles/:slug/revisions/diff?from=1&to=3
+// to mặc định là revision mới nhất, from mặc định là revision ngay trước to.
+type RevisionDiffQuery struct {
+	From int `form:"from" binding:"omitempty,min=1"`
+	To   int `form:"to" binding:"omitempty,min=1"`
+}
+
+// DiffLine là một dòng trong diff: op là equal, insert hoặc delete
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiffResponse định dạng response cho diff từng dòng giữa hai revisions
+// {"diff": {"from": 1, "to": 3, "title": [...], "description": [...], "body": [...], "additions": 2, "deletions": 1}}
+// additions/deletions đếm số dòng insert/delete trên cả ba field.
+type RevisionDiffResponse struct {
+	Diff struct {
+		From        int        `json:"from"`
+		To          int        `json:"to"`
+		Title       []DiffLine `json:"title"`
+		Description []DiffLine `json:"description"`
+		Body        []DiffLine `json:"body"`
+		Additions   int        `json:"additions"`
+		Deletions   int        `json:"deletions"`
+	} `json:"diff"`
+}
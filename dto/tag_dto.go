@@ -1,7 +1,91 @@
 package dto
 
+// TagListQuery là các query params của danh sách tags
+// GET /api/tags?sort=popular&limit=20
+type TagListQuery struct {
+	Sort  string `form:"sort"`                                    // name (mặc định) | popular
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"` // mặc định: tất cả với sort=name, 20 với sort=popular
+}
+
 // TagListResponse định dạng response cho list tags
 // Theo RealWorld spec: {"tags": ["tag1", "tag2", ...]}
+// tagCounts cùng thứ tự với tags, kèm số articles đã publish của từng tag.
 type TagListResponse struct {
-	Tags []string `json:"tags"`
+	Tags      []string   `json:"tags"`
+	TagCounts []TagCount `json:"tagCounts"`
+}
+
+// TagCount là tag kèm số articles đã publish
+type TagCount struct {
+	Name          string `json:"name"`
+	ArticlesCount int    `json:"articlesCount"`
+}
+
+// TrendingTagsQuery là các query params của danh sách tags trending
+// GET /api/tags/trending?window=7d&limit=10
+// window nhận dạng "7d" (ngày) hoặc duration của Go ("12h"), từ 1h tới 90d.
+type TrendingTagsQuery struct {
+	Window string `form:"window"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50"` // mặc định 10
+}
+
+// TrendingTag là một tag trending
+// articlesCount là số articles publish trong window gần nhất, previousCount trong window liền trước,
+// growth = articlesCount - previousCount.
+type TrendingTag struct {
+	Name          string `json:"name"`
+	ArticlesCount int    `json:"articlesCount"`
+	PreviousCount int    `json:"previousCount"`
+	Growth        int    `json:"growth"`
+}
+
+// TrendingTagsResponse định dạng response cho list tags trending
+// {"window": "7d", "tags": [...]}
+type TrendingTagsResponse struct {
+	Window string        `json:"window"`
+	Tags   []TrendingTag `json:"tags"`
+}
+
+// CreateTagAliasRequest định dạng request body cho tạo alias của tag
+// {"alias": {"alias": "golang", "tag": "go"}}
+type CreateTagAliasRequest struct {
+	Alias struct {
+		Alias string `json:"alias" binding:"required"`
+		Tag   string `json:"tag" binding:"required"`
+	} `json:"alias" binding:"required"`
+}
+
+// TagAlias là một alias trong response
+type TagAlias struct {
+	Alias     string `json:"alias"`
+	Tag       string `json:"tag"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// TagAliasResponse định dạng response cho một alias
+// {"alias": {...}}
+type TagAliasResponse struct {
+	Alias TagAlias `json:"alias"`
+}
+
+// TagAliasListResponse định dạng response cho list aliases
+// {"aliases": [...]}
+type TagAliasListResponse struct {
+	Aliases []TagAlias `json:"aliases"`
+}
+
+// MergeTagsRequest định dạng request body cho gộp tag from vào tag to
+// {"merge": {"from": "golang", "to": "go"}}
+type MergeTagsRequest struct {
+	Merge struct {
+		From string `json:"from" binding:"required"`
+		To   string `json:"to" binding:"required"`
+	} `json:"merge" binding:"required"`
+}
+
+// MergeTagsResponse định dạng response sau khi gộp tags
+// {"tag": "go", "aliases": ["golang"]} với aliases là các alias hiện tại của tag gốc
+type MergeTagsResponse struct {
+	Tag     string   `json:"tag"`
+	Aliases []string `json:"aliases"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+// TrashArticle là một article trong thùng rác
+// expiresAt là thời điểm article bị xóa hẳn, sau đó không khôi phục được nữa
+type TrashArticle struct {
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DeletedAt   string `json:"deletedAt"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+// TrashComment là một comment trong thùng rác kèm slug của article chứa nó
+type TrashComment struct {
+	ID          int    `json:"id"`
+	Body        string `json:"body"`
+	ArticleSlug string `json:"articleSlug"`
+	DeletedAt   string `json:"deletedAt"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+// TrashResponse định dạng response cho thùng rác của user, mới xóa trước
+// {"articles": [...], "comments": [...]}
+type TrashResponse struct {
+	Articles []TrashArticle `json:"articles"`
+	Comments []TrashComment `json:"comments"`
+}
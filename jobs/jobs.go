@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"news/config"
+	"news/repositories"
+	"news/services"
+	"sync"
+)
+
+const (
+	// publishBatchSize là số articles tối đa publish trong một transaction
+	publishBatchSize = 100
+	// purgeBatchSize là số dòng tối đa xóa hẳn khỏi thùng rác trong một lượt
+	purgeBatchSize = 500
+)
+
+// Start khởi chạy các background jobs trên repos
+// Jobs dừng khi ctx bị hủy; caller gọi Wait() trên WaitGroup trả về để chờ lượt đang chạy xong.
+func Start(ctx context.Context, repos *repositories.Repositories, cfg *config.Config) *sync.WaitGroup {
+	articleService := services.NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, nil)
+	commentService := services.NewCommentService(repos.UnitOfWork, repos.Comment, repos.Article, repos.User, repos.Follow, cfg.CommentMaxDepth, cfg.CommentEditWindow, nil)
+	trashService := services.NewTrashService(repos.UnitOfWork, articleService, commentService, repos.Article, repos.Comment, cfg.TrashRetention)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		Run(ctx, "publish-scheduled", cfg.PublishInterval, PublishScheduled(articleService, publishBatchSize))
+	}()
+	go func() {
+		defer wg.Done()
+		Run(ctx, "purge-trash", cfg.PurgeInterval, PurgeTrash(trashService, purgeBatchSize))
+	}()
+	return &wg
+}
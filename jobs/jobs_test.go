@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun kiểm tra task chạy ngay khi start, lặp theo interval, lỗi không dừng job và Run return khi ctx bị hủy
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int64
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, "test", 5*time.Millisecond, func(ctx context.Context) error {
+			if atomic.AddInt64(&calls, 1) >= 3 {
+				cancel()
+			}
+			return errors.New("temporary failure")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after context was cancelled")
+	}
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+}
+
+// fakePublisher giả lập còn remaining articles tới hạn
+type fakePublisher struct {
+	remaining int
+	calls     int
+}
+
+func (p *fakePublisher) PublishDueArticles(now time.Time, limit int) (int, error) {
+	p.calls++
+	published := p.remaining
+	if published > limit {
+		published = limit
+	}
+	p.remaining -= published
+	return published, nil
+}
+
+// TestPublishScheduled kiểm tra task publish theo lô cho tới khi hết articles tới hạn
+func TestPublishScheduled(t *testing.T) {
+	publisher := &fakePublisher{remaining: 25}
+	assert.NoError(t, PublishScheduled(publisher, 10)(context.Background()))
+	assert.Equal(t, 0, publisher.remaining)
+	assert.Equal(t, 3, publisher.calls)
+
+	// Lô vừa đủ batchSize thì chạy thêm một lượt để chắc chắn đã hết
+	publisher = &fakePublisher{remaining: 10}
+	assert.NoError(t, PublishScheduled(publisher, 10)(context.Background()))
+	assert.Equal(t, 2, publisher.calls)
+}
+
+// fakePurger giả lập còn remaining dòng quá hạn trong thùng rác
+type fakePurger struct {
+	remaining int
+	calls     int
+}
+
+func (p *fakePurger) PurgeTrash(now time.Time, limit int) (int, error) {
+	p.calls++
+	purged := p.remaining
+	if purged > limit {
+		purged = limit
+	}
+	p.remaining -= purged
+	return purged, nil
+}
+
+// TestPurgeTrash kiểm tra task xóa thùng rác theo lô cho tới khi hết dòng quá hạn
+func TestPurgeTrash(t *testing.T) {
+	purger := &fakePurger{remaining: 12}
+	assert.NoError(t, PurgeTrash(purger, 5)(context.Background()))
+	assert.Equal(t, 0, purger.remaining)
+	assert.Equal(t, 3, purger.calls)
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DuePublisher publish các articles đã tới giờ hẹn (services.ArticleService)
+type DuePublisher interface {
+	PublishDueArticles(now time.Time, limit int) (int, error)
+}
+
+// PublishScheduled trả về Task publish các articles scheduled có publishAt <= hiện tại
+// Mỗi transaction publish tối đa batchSize articles; lặp lại cho tới khi hết articles tới hạn
+// hoặc ctx bị hủy. Trạng thái nằm trong database nên articles quá hạn lúc server tắt sẽ được
+// publish ở lượt đầu tiên sau khi start lại.
+func PublishScheduled(publisher DuePublisher, batchSize int) Task {
+	return func(ctx context.Context) error {
+		for ctx.Err() == nil {
+			published, err := publisher.PublishDueArticles(time.Now(), batchSize)
+			if err != nil {
+				return err
+			}
+			if published > 0 {
+				log.Printf("Published %d scheduled article(s)", published)
+			}
+			if published < batchSize {
+				return nil
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TrashPurger xóa hẳn các dòng quá hạn trong thùng rác (services.TrashService)
+type TrashPurger interface {
+	PurgeTrash(now time.Time, limit int) (int, error)
+}
+
+// PurgeTrash trả về Task xóa hẳn articles/comments đã nằm trong thùng rác quá thời gian lưu giữ
+// Mỗi lần gọi xóa tối đa batchSize dòng; lặp lại cho tới khi hết dòng quá hạn hoặc ctx bị hủy.
+func PurgeTrash(purger TrashPurger, batchSize int) Task {
+	return func(ctx context.Context) error {
+		for ctx.Err() == nil {
+			purged, err := purger.PurgeTrash(time.Now(), batchSize)
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				log.Printf("Purged %d expired trash item(s)", purged)
+			}
+			if purged < batchSize {
+				return nil
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Task là một lượt chạy của job định kỳ
+type Task func(ctx context.Context) error
+
+// Run chạy task ngay khi start rồi lặp lại sau mỗi interval cho tới khi ctx bị hủy
+// Lỗi của một lượt chỉ được log, lượt sau vẫn chạy. Run chờ lượt đang chạy xong rồi mới return
+// nên caller có thể dùng sync.WaitGroup để shutdown sạch.
+func Run(ctx context.Context, name string, interval time.Duration, task Task) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Job %s started (interval %s)", name, interval)
+	for {
+		if err := task(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Job %s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Job %s stopped", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,78 +1,104 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
 	"news/config"
-	"news/controllers"
 	"news/database"
-	"news/middlewares"
+	"news/jobs"
+	"news/repositories"
+	"news/routes"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout là thời gian tối đa chờ các request đang xử lý khi tắt server
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// Load config từ environment variables
 	cfg := config.LoadConfig()
 
-	// Khởi tạo database connection
-	err := database.InitDB()
+	// Subcommand quản lý schema: news migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Subcommand cấp quyền: news role <username> user|admin
+	if len(os.Args) > 1 && os.Args[1] == "role" {
+		if err := runRoleCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Subcommand bỏ cấm bình luận: news unban <username>
+	if len(os.Args) > 1 && os.Args[1] == "unban" {
+		if err := runUnbanCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// -migrate (hoặc AUTO_MIGRATE=true) apply migrations trước khi start server
+	autoMigrate := flag.Bool("migrate", cfg.AutoMigrate, "apply pending database migrations on startup")
+	flag.Parse()
+
+	// Khởi tạo repositories theo STORAGE_DRIVER (mysql hoặc memory)
+	repos, err := repositories.NewRepositories(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		log.Fatal("Failed to initialize storage:", err)
 	}
 	defer database.CloseDB()
 
-	// Tạo Gin router
+	if *autoMigrate && cfg.StorageDriver == config.StorageMySQL {
+		applied, err := database.MigrateUp(database.DB)
+		if err != nil {
+			log.Fatal("Failed to apply migrations:", err)
+		}
+		for _, m := range applied {
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
+	}
+
+	// Tạo Gin router và đăng ký routes
 	router := gin.Default()
+	routes.Setup(router, repos, cfg)
 
-	// Middleware xử lý lỗi
-	router.Use(middlewares.ErrorHandler())
-
-	// Middleware xác thực (không bắt buộc, để controller quyết định)
-	router.Use(middlewares.AuthMiddleware())
-
-	// Khởi tạo controllers
-	authController := controllers.NewAuthController()
-	profileController := controllers.NewProfileController()
-	articleController := controllers.NewArticleController()
-	commentController := controllers.NewCommentController()
-	tagController := controllers.NewTagController()
-
-	// API routes
-	api := router.Group("/api")
-	{
-		// Authentication routes
-		api.POST("/users", authController.Register)
-		api.POST("/users/login", authController.Login)
-		api.GET("/user", middlewares.RequireAuth(), authController.GetCurrentUser)
-		api.PUT("/user", middlewares.RequireAuth(), authController.UpdateCurrentUser)
-
-		// Profile routes
-		api.GET("/profiles/:username", profileController.GetProfile)
-		api.POST("/profiles/:username/follow", middlewares.RequireAuth(), profileController.FollowUser)
-		api.DELETE("/profiles/:username/follow", middlewares.RequireAuth(), profileController.UnfollowUser)
-
-		// Article routes
-		api.GET("/articles", articleController.ListArticles)
-		api.GET("/articles/feed", middlewares.RequireAuth(), articleController.FeedArticles)
-		api.GET("/articles/:slug", articleController.GetArticle)
-		api.POST("/articles", middlewares.RequireAuth(), articleController.CreateArticle)
-		api.PUT("/articles/:slug", middlewares.RequireAuth(), articleController.UpdateArticle)
-		api.DELETE("/articles/:slug", middlewares.RequireAuth(), articleController.DeleteArticle)
-		api.POST("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.FavoriteArticle)
-		api.DELETE("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.UnfavoriteArticle)
-
-		// Comment routes
-		api.POST("/articles/:slug/comments", middlewares.RequireAuth(), commentController.AddComment)
-		api.GET("/articles/:slug/comments", commentController.GetComments)
-		api.DELETE("/articles/:slug/comments/:id", middlewares.RequireAuth(), commentController.DeleteComment)
-
-		// Tag routes
-		api.GET("/tags", tagController.GetTags)
-	}
+	// ctx bị hủy khi nhận SIGINT/SIGTERM, dùng để dừng server và background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs (publish articles hẹn giờ, dọn thùng rác)
+	workers := jobs.Start(ctx, repos, cfg)
 
 	// Chạy server
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: router}
+	go func() {
+		log.Printf("Server starting on port %s (storage: %s)", cfg.Port, cfg.StorageDriver)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Chờ tín hiệu tắt rồi dừng nhận request mới, chờ request và jobs đang chạy xong
+	<-ctx.Done()
+	log.Println("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server shutdown:", err)
 	}
+	workers.Wait()
 }
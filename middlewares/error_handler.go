@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"net/http"
+	"news/apperrors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,42 +13,85 @@ type ErrorResponse struct {
 }
 
 // ErrorHandler middleware xử lý lỗi và trả về format JSON thống nhất
+// Controllers chỉ cần gọi ctx.Error(err) và return; đây là nơi duy nhất quyết định HTTP status.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		// Kiểm tra xem có lỗi nào không
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
-
-			// Xử lý các loại lỗi khác nhau
-			switch err.Type {
-			case gin.ErrorTypeBind:
-				// Lỗi validation từ binding
-				c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-					Errors: map[string][]string{
-						"body": {err.Error()},
-					},
-				})
-			case gin.ErrorTypePublic:
-				// Lỗi public (đã được xử lý)
-				c.JSON(http.StatusBadRequest, ErrorResponse{
-					Errors: map[string][]string{
-						"body": {err.Error()},
-					},
-				})
-			default:
-				// Lỗi internal server
-				c.JSON(http.StatusInternalServerError, ErrorResponse{
-					Errors: map[string][]string{
-						"body": {"Internal server error"},
-					},
-				})
+		// Kiểm tra xem có lỗi nào không (và response chưa được ghi)
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last()
+
+		// Lỗi nghiệp vụ từ services
+		if appErr := apperrors.As(err.Err); appErr != nil {
+			c.JSON(StatusCode(appErr.Kind), newErrorResponse(appErr))
+			return
+		}
+
+		// Xử lý các loại lỗi khác nhau
+		switch err.Type {
+		case gin.ErrorTypeBind:
+			// Lỗi validation từ binding: trả về theo từng field nếu xác định được field
+			if fields := ValidationErrors(err.Err); len(fields) > 0 {
+				c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Errors: fields})
+				return
 			}
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Errors: map[string][]string{
+					"body": {err.Error()},
+				},
+			})
+		case gin.ErrorTypePublic:
+			// Lỗi public (đã được xử lý)
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Errors: map[string][]string{
+					"body": {err.Error()},
+				},
+			})
+		default:
+			// Lỗi internal server
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Errors: map[string][]string{
+					"body": {"Internal server error"},
+				},
+			})
 		}
 	}
 }
 
+// StatusCode map Kind của lỗi nghiệp vụ sang HTTP status code
+func StatusCode(kind apperrors.Kind) int {
+	switch kind {
+	case apperrors.KindNotFound:
+		return http.StatusNotFound
+	case apperrors.KindForbidden:
+		return http.StatusForbidden
+	case apperrors.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.KindConflict, apperrors.KindValidation:
+		// RealWorld spec dùng 422 cho cả lỗi validation và dữ liệu đã tồn tại
+		return http.StatusUnprocessableEntity
+	case apperrors.KindBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// newErrorResponse build ErrorResponse từ lỗi nghiệp vụ
+// Lỗi có Fields trả về theo từng field, còn lại đặt message dưới key "body"
+func newErrorResponse(appErr *apperrors.Error) ErrorResponse {
+	if appErr.Kind == apperrors.KindInternal {
+		return ErrorResponse{Errors: map[string][]string{"body": {"Internal server error"}}}
+	}
+	if len(appErr.Fields) > 0 {
+		return ErrorResponse{Errors: appErr.Fields}
+	}
+	return ErrorResponse{Errors: map[string][]string{"body": {appErr.Message}}}
+}
+
 // AbortWithError trả về error response và dừng request
 func AbortWithError(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, ErrorResponse{
@@ -1,8 +1,11 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"news/apperrors"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -57,3 +60,41 @@ func TestAbortWithError(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "errors")
 }
 
+
+// TestErrorHandler_AppErrors kiểm tra map lỗi nghiệp vụ sang HTTP status và body
+func TestErrorHandler_AppErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"not found", apperrors.NotFound("article not found"), http.StatusNotFound, `{"errors":{"body":["article not found"]}}`},
+		{"forbidden", apperrors.Forbidden("permission denied"), http.StatusForbidden, `{"errors":{"body":["permission denied"]}}`},
+		{"unauthorized", apperrors.Unauthorized("invalid email or password"), http.StatusUnauthorized, `{"errors":{"body":["invalid email or password"]}}`},
+		{"conflict", apperrors.Conflict("email already exists"), http.StatusUnprocessableEntity, `{"errors":{"body":["email already exists"]}}`},
+		{"bad request", apperrors.BadRequest("cannot follow yourself"), http.StatusBadRequest, `{"errors":{"body":["cannot follow yourself"]}}`},
+		{"validation fields", apperrors.FieldError("title", "can't be blank"), http.StatusUnprocessableEntity, `{"errors":{"title":["can't be blank"]}}`},
+		{"wrapped", fmt.Errorf("update: %w", apperrors.NotFound("article not found")), http.StatusNotFound, `{"errors":{"body":["article not found"]}}`},
+		{"unknown", errors.New("db is down"), http.StatusInternalServerError, `{"errors":{"body":["Internal server error"]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.GET("/test", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SlugResolver trả về slug hiện tại nếu slug là slug cũ của một article đã đổi title
+type SlugResolver func(slug string) (canonicalSlug string, moved bool, err error)
+
+// RedirectMovedSlug redirect 301 các GET request dùng slug cũ sang URL với slug hiện tại
+// Response có header Location và body {"canonicalSlug": "..."} để client cập nhật link.
+// Request ghi (POST/PUT/DELETE) không bị redirect vì nhiều client đổi method khi theo redirect;
+// services tự resolve slug cũ cho các request này.
+func RedirectMovedSlug(resolve SlugResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		slug := c.Param("slug")
+		canonicalSlug, moved, err := resolve(slug)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if !moved {
+			c.Next()
+			return
+		}
+
+		location := canonicalPath(c, canonicalSlug)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+
+		c.Header("Location", location)
+		c.AbortWithStatusJSON(http.StatusMovedPermanently, gin.H{
+			"canonicalSlug": canonicalSlug,
+		})
+	}
+}
+
+// canonicalPath dựng lại path của route hiện tại với slug mới
+// Ví dụ route "/api/articles/:slug/comments" -> "/api/articles/new-slug/comments"
+func canonicalPath(c *gin.Context, canonicalSlug string) string {
+	segments := strings.Split(c.FullPath(), "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := strings.TrimPrefix(segment, ":")
+		value := c.Param(name)
+		if name == "slug" {
+			value = canonicalSlug
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return strings.Join(segments, "/")
+}
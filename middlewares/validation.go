@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Dùng tên trong json tag (email, tagList, ...) thay vì tên field Go (Email, TagList)
+	// để lỗi validation trả về đúng key mà client gửi lên.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName lấy tên field từ json tag, bỏ qua các option như omitempty
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// ValidationErrors chuyển lỗi binding thành map field -> messages theo RealWorld spec
+// Ví dụ: {"email": ["is invalid"], "username": ["can't be blank"]}
+// Trả về nil nếu err không phải lỗi validation hoặc lỗi kiểu dữ liệu của một field.
+func ValidationErrors(err error) map[string][]string {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := map[string][]string{}
+		for _, fe := range validationErrs {
+			fields[fe.Field()] = append(fields[fe.Field()], validationMessage(fe))
+		}
+		return fields
+	}
+
+	// Sai kiểu dữ liệu, ví dụ gửi số cho field string
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		parts := strings.Split(typeErr.Field, ".")
+		return map[string][]string{parts[len(parts)-1]: {"is invalid"}}
+	}
+
+	return nil
+}
+
+// validationMessage trả về message cho một lỗi validator theo văn phong RealWorld
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "can't be blank"
+	case "email":
+		return "is invalid"
+	case "min":
+		// min=1 trên field optional (con trỏ) nghĩa là nếu gửi lên thì không được để trống
+		if fe.Kind() == reflect.String && fe.Param() == "1" {
+			return "can't be blank"
+		}
+		if fe.Kind() == reflect.String {
+			return "is too short (minimum is " + fe.Param() + " characters)"
+		}
+		return "must be greater than or equal to " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.String {
+			return "is too long (maximum is " + fe.Param() + " characters)"
+		}
+		return "must be less than or equal to " + fe.Param()
+	default:
+		return "is invalid"
+	}
+}
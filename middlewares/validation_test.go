@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"news/dto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupBindRouter tạo router bind body vào req và trả lỗi qua ErrorHandler
+func setupBindRouter(newReq func() interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/test", func(c *gin.Context) {
+		req := newReq()
+		if err := c.ShouldBindJSON(req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// TestValidationErrors_FieldMessages kiểm tra lỗi validator được trả về theo từng field
+func TestValidationErrors_FieldMessages(t *testing.T) {
+	router := setupBindRouter(func() interface{} { return &dto.RegisterRequest{} })
+
+	tests := []struct {
+		name     string
+		body     string
+		wantBody string
+	}{
+		{
+			name:     "blank username and invalid email",
+			body:     `{"user": {"email": "not-an-email", "password": "password123"}}`,
+			wantBody: `{"errors": {"username": ["can't be blank"], "email": ["is invalid"]}}`,
+		},
+		{
+			name:     "short password",
+			body:     `{"user": {"username": "john", "email": "john@example.com", "password": "123"}}`,
+			wantBody: `{"errors": {"password": ["is too short (minimum is 6 characters)"]}}`,
+		},
+		{
+			name:     "wrong type",
+			body:     `{"user": {"username": 123, "email": "john@example.com", "password": "password123"}}`,
+			wantBody: `{"errors": {"username": ["is invalid"]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+// TestValidationErrors_OptionalFields kiểm tra field optional chỉ validate khi được gửi lên
+func TestValidationErrors_OptionalFields(t *testing.T) {
+	router := setupBindRouter(func() interface{} { return &dto.UpdateUserRequest{} })
+
+	req := httptest.NewRequest("POST", "/test", strings.NewReader(`{"user": {"bio": "hello"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("POST", "/test", strings.NewReader(`{"user": {"email": "", "username": ""}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"errors": {"email": ["can't be blank"], "username": ["can't be blank"]}}`, w.Body.String())
+}
+
+// TestValidationErrors_MalformedJSON kiểm tra JSON sai cú pháp vẫn trả về dưới key "body"
+func TestValidationErrors_MalformedJSON(t *testing.T) {
+	router := setupBindRouter(func() interface{} { return &dto.RegisterRequest{} })
+
+	req := httptest.NewRequest("POST", "/test", strings.NewReader(`{"user": `))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"body"`)
+}
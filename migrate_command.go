@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"news/database"
+	"strconv"
+)
+
+// migrateUsage hướng dẫn sử dụng subcommand migrate
+const migrateUsage = "usage: news migrate up | down [steps] | status"
+
+// runMigrateCommand xử lý subcommand "migrate up|down|status"
+// Luôn chạy trên MySQL, không phụ thuộc STORAGE_DRIVER.
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if err := database.InitDB(); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.CloseDB()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(database.DB)
+		for _, m := range applied {
+			log.Printf("applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("schema is up to date")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return errors.New(migrateUsage)
+			}
+			steps = n
+		}
+		rolledBack, err := database.MigrateDown(database.DB, steps)
+		for _, m := range rolledBack {
+			log.Printf("rolled back %04d_%s", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := database.GetMigrationStatus(database.DB)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			} else if s.AppliedStatements > 0 {
+				state = fmt.Sprintf("partially applied (%d statements), run migrate up to resume", s.AppliedStatements)
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -2,17 +2,70 @@ package models
 
 import "time"
 
+// Trạng thái của article
+// Chỉ article published mới hiện với mọi người; draft, scheduled, pending và archived chỉ author xem được.
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusScheduled = "scheduled" // chờ worker publish khi tới PublishAt
+	ArticleStatusPending   = "pending"   // bị content filter giữ lại, chờ moderator duyệt
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+// Hành động kiểm duyệt article đang chờ moderators xem xét
+const (
+	ArticleActionApprove = "approve"
+	ArticleActionDelete  = "delete"
+)
+
 // Article model đại diện cho bảng articles trong database
 type Article struct {
-	ID             int       `json:"id"`
-	Slug           string    `json:"slug"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	Body           string    `json:"body"`
-	AuthorID       int       `json:"author_id"`
-	FavoritesCount int       `json:"favorites_count"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             int          `json:"id"`
+	Slug           string       `json:"slug"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	Body           string       `json:"body"`
+	BodyHTML       string       `json:"body_html"` // body đã render Markdown sang HTML an toàn, rỗng với dữ liệu cũ
+	Meta           *ArticleMeta `json:"meta"`      // thông tin suy ra từ body, nil với dữ liệu cũ
+	Status         string       `json:"status"`
+	AuthorID       int          `json:"author_id"`
+	FavoritesCount int          `json:"favorites_count"`
+	PublishedAt    *time.Time   `json:"published_at"`  // lần publish đầu tiên, nil nếu chưa từng publish
+	PublishAt      *time.Time   `json:"publish_at"`    // thời điểm hẹn publish, chỉ có khi status scheduled
+	ReviewReason   *string      `json:"review_reason"` // lý do content filter gửi article cho moderators, nil nếu không cần xem xét
+	DeletedAt      *time.Time   `json:"deleted_at"`    // khác nil khi article nằm trong thùng rác
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// ArticleMeta là các thông tin suy ra từ body của article, tính lại mỗi khi body thay đổi
+type ArticleMeta struct {
+	WordCount       int        `json:"word_count"`
+	ReadingTime     int        `json:"reading_time"` // phút
+	Excerpt         string     `json:"excerpt"`      // đoạn trích văn bản thuần từ body
+	TableOfContents []TOCEntry `json:"toc"`
+}
+
+// TOCEntry là một mục trong mục lục của article, tương ứng với một heading của body
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"` // id của heading trong body_html
+}
+
+// IsPublished kiểm tra article có hiện công khai không
+func (a *Article) IsPublished() bool {
+	return a.Status == ArticleStatusPublished
+}
+
+// IsPending kiểm tra article có đang bị giữ lại chờ moderator duyệt không
+func (a *Article) IsPending() bool {
+	return a.Status == ArticleStatusPending
+}
+
+// IsDeleted kiểm tra article có đang nằm trong thùng rác không
+func (a *Article) IsDeleted() bool {
+	return a.DeletedAt != nil
 }
 
 // ArticleWithAuthor chứa thông tin article kèm thông tin author
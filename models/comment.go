@@ -4,12 +4,47 @@ import "time"
 
 // Comment model đại diện cho bảng comments trong database
 type Comment struct {
+	ID        int        `json:"id"`
+	ArticleID int        `json:"article_id"`
+	AuthorID  int        `json:"author_id"`
+	ParentID  *int       `json:"parent_id"` // comment được trả lời, nil với comment gốc
+	Depth     int        `json:"depth"`     // 0 với comment gốc, reply có depth của parent + 1
+	Body      string     `json:"body"`
+	BodyHTML  string     `json:"body_html"` // body đã render Markdown sang HTML an toàn, rỗng với dữ liệu cũ
+	EditedAt  *time.Time `json:"edited_at"` // lần sửa gần nhất, nil nếu chưa từng sửa
+	HiddenAt  *time.Time `json:"hidden_at"` // khác nil khi comment bị ẩn bởi author của article hoặc moderator
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"` // khác nil khi comment nằm trong thùng rác
+	// RepliesCount là số replies trực tiếp đang hiển thị (chưa xóa, chưa bị ẩn hoặc còn reply đang hiển thị)
+	RepliesCount int `json:"replies_count"`
+}
+
+// IsEdited trả về true nếu comment đã được author sửa sau khi đăng
+func (c *Comment) IsEdited() bool {
+	return c.EditedAt != nil
+}
+
+// IsDeleted trả về true nếu comment nằm trong thùng rác
+// Comment đã xóa còn replies vẫn được list như placeholder "[deleted]".
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsHidden trả về true nếu comment bị ẩn
+// Comment bị ẩn còn replies được list như placeholder "[hidden]".
+func (c *Comment) IsHidden() bool {
+	return c.HiddenAt != nil
+}
+
+// CommentEdit là body của comment trước một lần sửa
+// CreatedAt là thời điểm sửa (body này bị thay thế).
+type CommentEdit struct {
 	ID        int       `json:"id"`
-	ArticleID int       `json:"article_id"`
-	AuthorID  int       `json:"author_id"`
+	CommentID int       `json:"comment_id"`
 	Body      string    `json:"body"`
+	BodyHTML  string    `json:"body_html"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // CommentWithAuthor chứa thông tin comment kèm thông tin author
@@ -21,3 +56,47 @@ type CommentWithAuthor struct {
 		Image    *string `json:"image"`
 	} `json:"author"`
 }
+
+// Hành động kiểm duyệt comment
+// Dismiss bỏ qua các reports, approve bỏ ẩn comment (comment bị ẩn hoặc bị content filter giữ lại),
+// hide ẩn comment, delete ẩn và chuyển comment vào thùng rác, ban cấm author bình luận và ẩn comment.
+// Author của article chỉ dùng được hide.
+const (
+	CommentActionDismiss = "dismiss"
+	CommentActionApprove = "approve"
+	CommentActionHide    = "hide"
+	CommentActionDelete  = "delete"
+	CommentActionBan     = "ban"
+)
+
+// IsValidCommentAction kiểm tra action có phải một hành động kiểm duyệt comment không
+func IsValidCommentAction(action string) bool {
+	return action == CommentActionDismiss || action == CommentActionApprove || action == CommentActionHide || action == CommentActionDelete || action == CommentActionBan
+}
+
+// CommentReport là một lần reader report comment, hoặc content filter gửi comment cho moderators
+type CommentReport struct {
+	ID         int        `json:"id"`
+	CommentID  int        `json:"comment_id"`
+	ReporterID *int       `json:"reporter_id"` // nil với report do content filter tạo
+	Reason     string     `json:"reason"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"` // khác nil khi moderator đã xử lý report
+}
+
+// CommentModerationAction ghi lại một hành động kiểm duyệt: ActorID đã làm Action với comment lúc CreatedAt
+type CommentModerationAction struct {
+	ID        int       `json:"id"`
+	CommentID int       `json:"comment_id"`
+	ActorID   int       `json:"actor_id"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReportedComment là comment còn reports chưa xử lý trong hàng đợi kiểm duyệt
+type ReportedComment struct {
+	Comment
+	ArticleSlug     string    `json:"article_slug"`
+	ReportsCount    int       `json:"reports_count"`     // số reports chưa xử lý
+	FirstReportedAt time.Time `json:"first_reported_at"` // report chưa xử lý sớm nhất
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ArticleRevision là bản chụp nội dung của article sau một lần tạo hoặc sửa
+// Number đánh số revision trong từng article, bắt đầu từ 1 (revision lớn nhất là nội dung hiện tại).
+type ArticleRevision struct {
+	ID          int       `json:"id"`
+	ArticleID   int       `json:"article_id"`
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	BodyHTML    string    `json:"body_html"`
+	EditorID    int       `json:"editor_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -1,7 +1,31 @@
 package models
 
+import "time"
+
 // Tag model đại diện cho bảng tags trong database
 type Tag struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
+
+// TagAlias model đại diện cho bảng tag_aliases: tên khác của một tag
+// Tag được gắn bằng alias sẽ được lưu thành tag gốc.
+type TagAlias struct {
+	Alias     string    `json:"alias"`
+	TagID     int       `json:"tag_id"`
+	TagName   string    `json:"tag_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagCount là tag kèm số articles đã publish đang gắn tag đó
+type TagCount struct {
+	Name          string `json:"name"`
+	ArticlesCount int    `json:"articles_count"`
+}
+
+// TrendingTag là tag kèm số articles publish trong khoảng thời gian gần nhất và khoảng liền trước
+type TrendingTag struct {
+	Name          string `json:"name"`
+	CurrentCount  int    `json:"current_count"`
+	PreviousCount int    `json:"previous_count"`
+}
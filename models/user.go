@@ -2,14 +2,45 @@ package models
 
 import "time"
 
+// Role của user
+// Admin quản lý được dữ liệu chung như tags (alias, gộp tags).
+// Moderator (và admin) xem được lịch sử chỉnh sửa của comments và kiểm duyệt comments bị report.
+const (
+	UserRoleUser      = "user"
+	UserRoleModerator = "moderator"
+	UserRoleAdmin     = "admin"
+)
+
 // User model đại diện cho bảng users trong database
 type User struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`     // Không trả về trong JSON response
-	Bio          *string   `json:"bio"`   // Có thể null
-	Image        *string   `json:"image"` // Có thể null
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`     // Không trả về trong JSON response
+	Bio          *string    `json:"bio"`   // Có thể null
+	Image        *string    `json:"image"` // Có thể null
+	Role         string     `json:"role"`
+	BannedAt     *time.Time `json:"banned_at"` // khác nil khi user bị moderator cấm bình luận
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsAdmin kiểm tra user có quyền admin không
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsModerator kiểm tra user có quyền moderator không, admin cũng là moderator
+func (u *User) IsModerator() bool {
+	return u.Role == UserRoleModerator || u.IsAdmin()
+}
+
+// IsBanned kiểm tra user có bị cấm bình luận không
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// IsValidRole kiểm tra role có phải một trong các role của user không
+func IsValidRole(role string) bool {
+	return role == UserRoleUser || role == UserRoleModerator || role == UserRoleAdmin
 }
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"news/models"
+	"strings"
+	"time"
+)
+
+// ArticleSort là thứ tự sắp xếp danh sách articles
+type ArticleSort string
+
+const (
+	// SortRecent sắp xếp mới publish trước (mặc định)
+	SortRecent ArticleSort = "recent"
+	// SortOldest sắp xếp publish sớm nhất trước
+	SortOldest ArticleSort = "oldest"
+	// SortMostFavorited sắp xếp nhiều favorite nhất trước
+	SortMostFavorited ArticleSort = "mostFavorited"
+	// SortRecentlyUpdated sắp xếp mới cập nhật trước
+	SortRecentlyUpdated ArticleSort = "recentlyUpdated"
+)
+
+// TagMatchMode quyết định article phải có một hay tất cả các tags trong filter
+type TagMatchMode string
+
+const (
+	// TagMatchAny: article có ít nhất một trong các tags (mặc định)
+	TagMatchAny TagMatchMode = "any"
+	// TagMatchAll: article có đủ tất cả các tags
+	TagMatchAll TagMatchMode = "all"
+)
+
+// ArticleFilter là điều kiện lọc và sắp xếp danh sách articles
+// Field rỗng/nil nghĩa là không lọc theo field đó.
+type ArticleFilter struct {
+	Tags          []string
+	TagMode       TagMatchMode
+	ExcludeTags   []string
+	Authors       []string // usernames
+	Favorited     string   // username đã favorite
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          ArticleSort
+
+	// followedBy chỉ lấy articles của users mà user này follow (dùng cho Feed)
+	followedBy int
+}
+
+// IsValid kiểm tra sort có được hỗ trợ không, rỗng là hợp lệ (dùng SortRecent)
+func (s ArticleSort) IsValid() bool {
+	_, ok := articleSorts[s]
+	return s == "" || ok
+}
+
+// IsValid kiểm tra tag mode có được hỗ trợ không, rỗng là hợp lệ (dùng TagMatchAny)
+func (m TagMatchMode) IsValid() bool {
+	return m == "" || m == TagMatchAny || m == TagMatchAll
+}
+
+// articleSortSpec mô tả một thứ tự sắp xếp: cột SQL, chiều và cách lấy key cho cursor
+type articleSortSpec struct {
+	column string
+	desc   bool
+	// key lấy giá trị cột sắp xếp của article để so sánh và tạo cursor
+	key func(article *models.Article) int64
+	// keyArg chuyển Cursor.Key thành tham số SQL cùng kiểu với cột
+	keyArg func(cursor *Cursor) interface{}
+}
+
+// articleSorts là các thứ tự sắp xếp được hỗ trợ; id luôn là tie-breaker để thứ tự ổn định
+var articleSorts = map[ArticleSort]articleSortSpec{
+	SortRecent: {
+		column: "a.published_at",
+		desc:   true,
+		key:    publishedKey,
+		keyArg: func(c *Cursor) interface{} { return c.Time() },
+	},
+	SortOldest: {
+		column: "a.published_at",
+		desc:   false,
+		key:    publishedKey,
+		keyArg: func(c *Cursor) interface{} { return c.Time() },
+	},
+	SortMostFavorited: {
+		column: "a.favorites_count",
+		desc:   true,
+		key:    func(a *models.Article) int64 { return int64(a.FavoritesCount) },
+		keyArg: func(c *Cursor) interface{} { return c.Key },
+	},
+	SortRecentlyUpdated: {
+		column: "a.updated_at",
+		desc:   true,
+		key:    func(a *models.Article) int64 { return a.UpdatedAt.UnixNano() },
+		keyArg: func(c *Cursor) interface{} { return c.Time() },
+	},
+}
+
+// publishedKey là key sắp xếp theo published_at; danh sách chỉ gồm articles đã publish nên
+// PublishedAt luôn có giá trị, nil được coi là created_at cho an toàn
+func publishedKey(a *models.Article) int64 {
+	if a.PublishedAt == nil {
+		return a.CreatedAt.UnixNano()
+	}
+	return a.PublishedAt.UnixNano()
+}
+
+// sortSpec trả về spec của sort, mặc định SortRecent
+func (s ArticleSort) sortSpec() articleSortSpec {
+	if spec, ok := articleSorts[s]; ok {
+		return spec
+	}
+	return articleSorts[SortRecent]
+}
+
+// CursorFor tạo cursor trỏ tới article trong danh sách sắp xếp theo s
+func (s ArticleSort) CursorFor(article *models.Article) Cursor {
+	return Cursor{Key: s.sortSpec().key(article), ID: article.ID}
+}
+
+// less so sánh thứ tự hai articles theo sort
+func (spec articleSortSpec) less(a, b *models.Article) bool {
+	ka, kb := spec.key(a), spec.key(b)
+	if ka != kb {
+		if spec.desc {
+			return ka > kb
+		}
+		return ka < kb
+	}
+	if spec.desc {
+		return a.ID > b.ID
+	}
+	return a.ID < b.ID
+}
+
+// follows kiểm tra article có đứng sau cursor theo sort không
+func (spec articleSortSpec) follows(article *models.Article, cursor *Cursor) bool {
+	if spec.desc {
+		return cursor.isBefore(spec.key(article), article.ID)
+	}
+	return cursor.isAfter(spec.key(article), article.ID)
+}
+
+// articleQuery build phần FROM/WHERE/ORDER BY cho các query trên bảng articles (alias a)
+// Dùng chung cho List, Count, Feed, FeedCount, Search, SearchCount để logic filter chỉ ở một chỗ.
+// Các filter dùng subquery thay vì JOIN nên không sinh dòng trùng, không cần DISTINCT.
+type articleQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// newArticleQuery tạo query với các điều kiện từ filter
+// Danh sách chỉ gồm articles đã publish và không nằm trong thùng rác; draft và archived chỉ author xem được qua slug.
+func newArticleQuery(filter ArticleFilter) *articleQuery {
+	q := &articleQuery{}
+	q.where("a.status = ?", models.ArticleStatusPublished)
+	q.where("a.deleted_at IS NULL")
+
+	// Filter by tags
+	if len(filter.Tags) > 0 {
+		tagQuery := `SELECT at.article_id FROM article_tags at
+		             INNER JOIN tags t ON at.tag_id = t.id
+		             WHERE t.name IN (` + inPlaceholders(len(filter.Tags)) + `)`
+		args := stringArgs(filter.Tags)
+		if filter.TagMode == TagMatchAll {
+			tagQuery += " GROUP BY at.article_id HAVING COUNT(DISTINCT t.id) = ?"
+			args = append(args, len(uniqueStrings(filter.Tags)))
+		}
+		q.where("a.id IN ("+tagQuery+")", args...)
+	}
+
+	// Loại trừ tags
+	if len(filter.ExcludeTags) > 0 {
+		q.where(`a.id NOT IN (SELECT at.article_id FROM article_tags at
+		         INNER JOIN tags t ON at.tag_id = t.id
+		         WHERE t.name IN (`+inPlaceholders(len(filter.ExcludeTags))+`))`, stringArgs(filter.ExcludeTags)...)
+	}
+
+	// Filter by authors
+	if len(filter.Authors) > 0 {
+		q.where(`a.author_id IN (SELECT u.id FROM users u
+		         WHERE u.username IN (`+inPlaceholders(len(filter.Authors))+`))`, stringArgs(filter.Authors)...)
+	}
+
+	// Filter by favorited
+	if filter.Favorited != "" {
+		q.where(`a.id IN (SELECT f.article_id FROM favorites f
+		         INNER JOIN users u ON f.user_id = u.id
+		         WHERE u.username = ?)`, filter.Favorited)
+	}
+
+	// Khoảng thời gian tạo
+	if filter.CreatedAfter != nil {
+		q.where("a.created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		q.where("a.created_at < ?", *filter.CreatedBefore)
+	}
+
+	// Feed: articles của users đang follow
+	if filter.followedBy != 0 {
+		q.where("a.author_id IN (SELECT f.following_id FROM follows f WHERE f.follower_id = ?)", filter.followedBy)
+	}
+
+	return q
+}
+
+// where thêm một điều kiện AND
+func (q *articleQuery) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// after thêm điều kiện keyset pagination theo sort
+func (q *articleQuery) after(cursor *Cursor, sort ArticleSort) {
+	if cursor == nil {
+		return
+	}
+	spec := sort.sortSpec()
+	op := ">"
+	if spec.desc {
+		op = "<"
+	}
+	key := spec.keyArg(cursor)
+	q.where("("+spec.column+" "+op+" ? OR ("+spec.column+" = ? AND a.id "+op+" ?))", key, key, cursor.ID)
+}
+
+// whereClause trả về " WHERE ..." hoặc chuỗi rỗng nếu không có điều kiện
+func (q *articleQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// orderBy trả về " ORDER BY ..." theo sort
+func orderBy(sort ArticleSort) string {
+	spec := sort.sortSpec()
+	direction := " ASC"
+	if spec.desc {
+		direction = " DESC"
+	}
+	return " ORDER BY " + spec.column + direction + ", a.id" + direction
+}
+
+// uniqueStrings bỏ các phần tử trùng, giữ thứ tự
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"news/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestArticleQuery kiểm tra SQL và args sinh ra từ ArticleFilter
+func TestArticleQuery(t *testing.T) {
+	q := newArticleQuery(ArticleFilter{})
+	assert.Equal(t, " WHERE a.status = ? AND a.deleted_at IS NULL", q.whereClause())
+	assert.Equal(t, []interface{}{"published"}, q.args)
+	assert.Equal(t, " ORDER BY a.published_at DESC, a.id DESC", orderBy(""))
+
+	q = newArticleQuery(ArticleFilter{
+		Tags:    []string{"go", "web", "go"},
+		TagMode: TagMatchAll,
+		Authors: []string{"jake"},
+	})
+	assert.Contains(t, q.whereClause(), "WHERE t.name IN (?, ?, ?) GROUP BY at.article_id HAVING COUNT(DISTINCT t.id) = ?")
+	assert.Contains(t, q.whereClause(), "AND a.author_id IN (SELECT u.id FROM users u")
+	// Tag trùng chỉ được đếm một lần
+	assert.Equal(t, []interface{}{"published", "go", "web", "go", 2, "jake"}, q.args)
+
+	q = newArticleQuery(ArticleFilter{Sort: SortMostFavorited})
+	q.after(&Cursor{Key: 5, ID: 10}, SortMostFavorited)
+	assert.Equal(t, " WHERE a.status = ? AND a.deleted_at IS NULL AND (a.favorites_count < ? OR (a.favorites_count = ? AND a.id < ?))", q.whereClause())
+	assert.Equal(t, []interface{}{"published", int64(5), int64(5), 10}, q.args)
+
+	q = newArticleQuery(ArticleFilter{})
+	q.after(&Cursor{Key: 0, ID: 3}, SortOldest)
+	assert.Equal(t, " WHERE a.status = ? AND a.deleted_at IS NULL AND (a.published_at > ? OR (a.published_at = ? AND a.id > ?))", q.whereClause())
+	assert.Equal(t, " ORDER BY a.published_at ASC, a.id ASC", orderBy(SortOldest))
+}
+
+// TestArticleSort_CursorFor kiểm tra cursor lấy đúng key theo sort
+func TestArticleSort_CursorFor(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	published := created.Add(time.Minute)
+	article := &models.Article{ID: 7, FavoritesCount: 3, PublishedAt: &published, CreatedAt: created, UpdatedAt: created.Add(time.Hour)}
+
+	assert.Equal(t, Cursor{Key: 3, ID: 7}, SortMostFavorited.CursorFor(article))
+	assert.True(t, SortRecent.CursorFor(article).Time().Equal(published))
+	assert.True(t, SortRecentlyUpdated.CursorFor(article).Time().Equal(created.Add(time.Hour)))
+	assert.False(t, ArticleSort("popular").IsValid())
+	assert.True(t, ArticleSort("").IsValid())
+}
@@ -2,27 +2,82 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"news/database"
 	"news/models"
-	"strings"
 	"time"
 )
 
-// ArticleRepository chứa các method để làm việc với bảng articles
-type ArticleRepository struct{}
+// ArticleSearchResult là một article khớp từ khóa tìm kiếm kèm điểm relevance
+// Score chỉ dùng để so sánh trong cùng một lần tìm kiếm, thang điểm khác nhau giữa các backend.
+type ArticleSearchResult struct {
+	Article *models.Article
+	Score   float64
+}
+
+// ArticleRepository định nghĩa các thao tác dữ liệu trên bảng articles
+// Có 2 implementation: MySQL (mysqlArticleRepository) và in-memory (memoryArticleRepository)
+// Delete là xóa mềm (deleted_at); mọi query đọc bỏ qua articles đã xóa trừ các method *Deleted*.
+type ArticleRepository interface {
+	Create(slug, title, description, body, bodyHTML string, authorID int, status string) (*models.Article, error)
+	GetByID(id int) (*models.Article, error)
+	GetBySlug(slug string) (*models.Article, error)
+	List(filter ArticleFilter, after *Cursor, limit, offset int) ([]*models.Article, error)
+	Count(filter ArticleFilter) (int, error)
+	Feed(currentUserID int, after *Cursor, limit, offset int) ([]*models.Article, error)
+	FeedCount(currentUserID int) (int, error)
+	Update(articleID int, slug, title, description, body, bodyHTML *string) (*models.Article, error)
+	SetMeta(articleID int, meta models.ArticleMeta) error
+	SetStatus(articleID int, status string, publishedAt *time.Time) (*models.Article, error)
+	Schedule(articleID int, publishAt time.Time) (*models.Article, error)
+	Hold(articleID int) error
+	PublishDue(now time.Time, limit int) ([]int, error)
+	Delete(articleID int) error
+	Restore(articleID int) error
+	GetDeletedBySlug(slug string) (*models.Article, error)
+	ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Article, error)
+	PurgeDeleted(deletedBefore time.Time, limit int) (int, error)
+	IsSlugExists(slug string) (bool, error)
+	AddSlugHistory(articleID int, slug string) error
+	DeleteSlugHistory(slug string) error
+	GetBySlugHistory(slug string) (*models.Article, error)
+	Favorite(userID, articleID int) error
+	Unfavorite(userID, articleID int) error
+	IsFavorited(userID, articleID int) (bool, error)
+	GetTagsByArticleID(articleID int) ([]string, error)
+	GetTagsByArticleIDs(articleIDs []int) (map[int][]string, error)
+	GetFavoritedArticleIDs(userID int, articleIDs []int) (map[int]bool, error)
+	GetCommentsCounts(articleIDs []int) (map[int]int, error)
+	HasRecentBody(authorID int, body string, since time.Time, excludeArticleID int) (bool, error)
+	SetReviewReason(articleID int, reason *string) error
+	ListForReview(limit, offset int) ([]*models.Article, error)
+	CountForReview() (int, error)
+	Search(query string, filter ArticleFilter, limit, offset int) ([]*ArticleSearchResult, error)
+	SearchCount(query string, filter ArticleFilter) (int, error)
+}
+
+// mysqlArticleRepository implement ArticleRepository bằng MySQL
+type mysqlArticleRepository struct {
+	db database.DBTX
+}
 
-// NewArticleRepository tạo instance mới của ArticleRepository
-func NewArticleRepository() *ArticleRepository {
-	return &ArticleRepository{}
+// NewArticleRepository tạo ArticleRepository dùng MySQL connection hoặc transaction db
+func NewArticleRepository(db database.DBTX) ArticleRepository {
+	return &mysqlArticleRepository{db: db}
 }
 
 // Create tạo article mới trong database
-func (r *ArticleRepository) Create(slug, title, description, body string, authorID int) (*models.Article, error) {
-	query := `INSERT INTO articles (slug, title, description, body, author_id, favorites_count, created_at, updated_at) 
-	          VALUES (?, ?, ?, ?, ?, 0, ?, ?)`
+// Article tạo với status published thì published_at là thời điểm tạo.
+func (r *mysqlArticleRepository) Create(slug, title, description, body, bodyHTML string, authorID int, status string) (*models.Article, error) {
+	query := `INSERT INTO articles (slug, title, description, body, body_html, status, author_id, favorites_count, published_at, created_at, updated_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?)`
 
 	now := time.Now()
-	result, err := database.DB.Exec(query, slug, title, description, body, authorID, now, now)
+	var publishedAt *time.Time
+	if status == models.ArticleStatusPublished {
+		publishedAt = &now
+	}
+	result, err := r.db.Exec(query, slug, title, description, body, bodyHTML, status, authorID, publishedAt, now, now)
 	if err != nil {
 		return nil, err
 	}
@@ -38,22 +93,10 @@ func (r *ArticleRepository) Create(slug, title, description, body string, author
 }
 
 // GetByID lấy article theo ID
-func (r *ArticleRepository) GetByID(id int) (*models.Article, error) {
-	query := `SELECT id, slug, title, description, body, author_id, favorites_count, created_at, updated_at 
-	          FROM articles WHERE id = ?`
+func (r *mysqlArticleRepository) GetByID(id int) (*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a WHERE a.id = ? AND a.deleted_at IS NULL`
 
-	article := &models.Article{}
-	err := database.DB.QueryRow(query, id).Scan(
-		&article.ID,
-		&article.Slug,
-		&article.Title,
-		&article.Description,
-		&article.Body,
-		&article.AuthorID,
-		&article.FavoritesCount,
-		&article.CreatedAt,
-		&article.UpdatedAt,
-	)
+	article, err := scanArticle(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -65,22 +108,10 @@ func (r *ArticleRepository) GetByID(id int) (*models.Article, error) {
 }
 
 // GetBySlug lấy article theo slug
-func (r *ArticleRepository) GetBySlug(slug string) (*models.Article, error) {
-	query := `SELECT id, slug, title, description, body, author_id, favorites_count, created_at, updated_at 
-	          FROM articles WHERE slug = ?`
+func (r *mysqlArticleRepository) GetBySlug(slug string) (*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a WHERE a.slug = ? AND a.deleted_at IS NULL`
 
-	article := &models.Article{}
-	err := database.DB.QueryRow(query, slug).Scan(
-		&article.ID,
-		&article.Slug,
-		&article.Title,
-		&article.Description,
-		&article.Body,
-		&article.AuthorID,
-		&article.FavoritesCount,
-		&article.CreatedAt,
-		&article.UpdatedAt,
-	)
+	article, err := scanArticle(r.db.QueryRow(query, slug))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -91,54 +122,64 @@ func (r *ArticleRepository) GetBySlug(slug string) (*models.Article, error) {
 	return article, nil
 }
 
-// List lấy danh sách articles với filters và pagination
-// Filters: tag, author, favorited
-func (r *ArticleRepository) List(tag, author, favorited *string, limit, offset int) ([]*models.Article, error) {
-	// Build query với filters
-	query := `SELECT DISTINCT a.id, a.slug, a.title, a.description, a.body, a.author_id, 
-	          a.favorites_count, a.created_at, a.updated_at 
-	          FROM articles a`
-
-	joins := []string{}
-	conditions := []string{}
-	args := []interface{}{}
+// articleColumns là các cột của bảng articles (alias a) theo thứ tự scanArticle đọc
+const articleColumns = `a.id, a.slug, a.title, a.description, a.body, COALESCE(a.body_html, ''), 
+	          a.word_count, a.reading_time, a.excerpt, a.toc, a.status, a.author_id, a.favorites_count, a.published_at, a.publish_at, a.review_reason, a.deleted_at, a.created_at, a.updated_at`
 
-	// Filter by tag
-	if tag != nil && *tag != "" {
-		joins = append(joins, "INNER JOIN article_tags at ON a.id = at.article_id")
-		joins = append(joins, "INNER JOIN tags t ON at.tag_id = t.id")
-		conditions = append(conditions, "t.name = ?")
-		args = append(args, *tag)
-	}
+// rowScanner là *sql.Row hoặc *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	// Filter by author
-	if author != nil && *author != "" {
-		joins = append(joins, "INNER JOIN users u ON a.author_id = u.id")
-		conditions = append(conditions, "u.username = ?")
-		args = append(args, *author)
+// scanArticle đọc một dòng articleColumns, extra là các cột thêm sau articleColumns
+func scanArticle(row rowScanner, extra ...interface{}) (*models.Article, error) {
+	article := &models.Article{}
+	var wordCount, readingTime sql.NullInt64
+	var excerpt, toc sql.NullString
+	dest := []interface{}{
+		&article.ID,
+		&article.Slug,
+		&article.Title,
+		&article.Description,
+		&article.Body,
+		&article.BodyHTML,
+		&wordCount,
+		&readingTime,
+		&excerpt,
+		&toc,
+		&article.Status,
+		&article.AuthorID,
+		&article.FavoritesCount,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.ReviewReason,
+		&article.DeletedAt,
+		&article.CreatedAt,
+		&article.UpdatedAt,
 	}
-
-	// Filter by favorited
-	if favorited != nil && *favorited != "" {
-		joins = append(joins, "INNER JOIN favorites f ON a.id = f.article_id")
-		joins = append(joins, "INNER JOIN users u2 ON f.user_id = u2.id")
-		conditions = append(conditions, "u2.username = ?")
-		args = append(args, *favorited)
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
 	}
 
-	// Combine query
-	if len(joins) > 0 {
-		query += " " + strings.Join(joins, " ")
-	}
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	// Dữ liệu trước migration 0011 chưa có meta
+	if wordCount.Valid {
+		article.Meta = &models.ArticleMeta{
+			WordCount:   int(wordCount.Int64),
+			ReadingTime: int(readingTime.Int64),
+			Excerpt:     excerpt.String,
+		}
+		if toc.Valid {
+			if err := json.Unmarshal([]byte(toc.String), &article.Meta.TableOfContents); err != nil {
+				return nil, err
+			}
+		}
 	}
+	return article, nil
+}
 
-	// Order by newest first và pagination
-	query += " ORDER BY a.created_at DESC LIMIT ? OFFSET ?"
-	args = append(args, limit, offset)
-
-	rows, err := database.DB.Query(query, args...)
+// queryArticles chạy query trả về các dòng articleColumns
+func (r *mysqlArticleRepository) queryArticles(query string, args ...interface{}) ([]*models.Article, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,131 +187,51 @@ func (r *ArticleRepository) List(tag, author, favorited *string, limit, offset i
 
 	var articles []*models.Article
 	for rows.Next() {
-		article := &models.Article{}
-		err := rows.Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.FavoritesCount,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-		)
+		article, err := scanArticle(rows)
 		if err != nil {
 			return nil, err
 		}
 		articles = append(articles, article)
 	}
 
-	return articles, nil
+	return articles, rows.Err()
 }
 
-// Count đếm tổng số articles với filters
-func (r *ArticleRepository) Count(tag, author, favorited *string) (int, error) {
-	query := `SELECT COUNT(DISTINCT a.id) FROM articles a`
-
-	joins := []string{}
-	conditions := []string{}
-	args := []interface{}{}
-
-	// Filter by tag
-	if tag != nil && *tag != "" {
-		joins = append(joins, "INNER JOIN article_tags at ON a.id = at.article_id")
-		joins = append(joins, "INNER JOIN tags t ON at.tag_id = t.id")
-		conditions = append(conditions, "t.name = ?")
-		args = append(args, *tag)
-	}
-
-	// Filter by author
-	if author != nil && *author != "" {
-		joins = append(joins, "INNER JOIN users u ON a.author_id = u.id")
-		conditions = append(conditions, "u.username = ?")
-		args = append(args, *author)
-	}
+// List lấy danh sách articles theo filter và pagination, sắp xếp theo filter.Sort
+// Nếu after khác nil thì chỉ lấy các articles đứng sau cursor (keyset pagination),
+// khi đó nên truyền offset = 0.
+func (r *mysqlArticleRepository) List(filter ArticleFilter, after *Cursor, limit, offset int) ([]*models.Article, error) {
+	q := newArticleQuery(filter)
+	q.after(after, filter.Sort)
 
-	// Filter by favorited
-	if favorited != nil && *favorited != "" {
-		joins = append(joins, "INNER JOIN favorites f ON a.id = f.article_id")
-		joins = append(joins, "INNER JOIN users u2 ON f.user_id = u2.id")
-		conditions = append(conditions, "u2.username = ?")
-		args = append(args, *favorited)
-	}
+	query := `SELECT ` + articleColumns + ` FROM articles a` + q.whereClause() + orderBy(filter.Sort) + ` LIMIT ? OFFSET ?`
+	return r.queryArticles(query, append(q.args, limit, offset)...)
+}
 
-	if len(joins) > 0 {
-		query += " " + strings.Join(joins, " ")
-	}
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+// Count đếm tổng số articles theo filter
+func (r *mysqlArticleRepository) Count(filter ArticleFilter) (int, error) {
+	q := newArticleQuery(filter)
 
 	var count int
-	err := database.DB.QueryRow(query, args...).Scan(&count)
-	if err != nil {
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM articles a`+q.whereClause(), q.args...).Scan(&count); err != nil {
 		return 0, err
 	}
-
 	return count, nil
 }
 
-// Feed lấy articles từ các users mà currentUser đang follow
-func (r *ArticleRepository) Feed(currentUserID int, limit, offset int) ([]*models.Article, error) {
-	query := `SELECT a.id, a.slug, a.title, a.description, a.body, a.author_id, 
-	          a.favorites_count, a.created_at, a.updated_at 
-	          FROM articles a
-	          INNER JOIN follows f ON a.author_id = f.following_id
-	          WHERE f.follower_id = ?
-	          ORDER BY a.created_at DESC
-	          LIMIT ? OFFSET ?`
-
-	rows, err := database.DB.Query(query, currentUserID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var articles []*models.Article
-	for rows.Next() {
-		article := &models.Article{}
-		err := rows.Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.AuthorID,
-			&article.FavoritesCount,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		articles = append(articles, article)
-	}
-
-	return articles, nil
+// Feed lấy articles từ các users mà currentUser đang follow, mới nhất trước
+// Nếu after khác nil thì chỉ lấy các articles đứng sau cursor
+func (r *mysqlArticleRepository) Feed(currentUserID int, after *Cursor, limit, offset int) ([]*models.Article, error) {
+	return r.List(ArticleFilter{followedBy: currentUserID}, after, limit, offset)
 }
 
 // FeedCount đếm tổng số articles trong feed
-func (r *ArticleRepository) FeedCount(currentUserID int) (int, error) {
-	query := `SELECT COUNT(*) 
-	          FROM articles a
-	          INNER JOIN follows f ON a.author_id = f.following_id
-	          WHERE f.follower_id = ?`
-
-	var count int
-	err := database.DB.QueryRow(query, currentUserID).Scan(&count)
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
+func (r *mysqlArticleRepository) FeedCount(currentUserID int) (int, error) {
+	return r.Count(ArticleFilter{followedBy: currentUserID})
 }
 
 // Update cập nhật article
-func (r *ArticleRepository) Update(articleID int, slug, title, description, body *string) (*models.Article, error) {
+func (r *mysqlArticleRepository) Update(articleID int, slug, title, description, body, bodyHTML *string) (*models.Article, error) {
 	// Build query động
 	query := "UPDATE articles SET updated_at = ?"
 	args := []interface{}{time.Now()}
@@ -291,11 +252,15 @@ func (r *ArticleRepository) Update(articleID int, slug, title, description, body
 		query += ", body = ?"
 		args = append(args, *body)
 	}
+	if bodyHTML != nil {
+		query += ", body_html = ?"
+		args = append(args, *bodyHTML)
+	}
 
 	query += " WHERE id = ?"
 	args = append(args, articleID)
 
-	_, err := database.DB.Exec(query, args...)
+	_, err := r.db.Exec(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -303,43 +268,209 @@ func (r *ArticleRepository) Update(articleID int, slug, title, description, body
 	return r.GetByID(articleID)
 }
 
-// Delete xóa article
-func (r *ArticleRepository) Delete(articleID int) error {
-	query := `DELETE FROM articles WHERE id = ?`
-	_, err := database.DB.Exec(query, articleID)
+// SetMeta lưu thông tin suy ra từ body của article
+func (r *mysqlArticleRepository) SetMeta(articleID int, meta models.ArticleMeta) error {
+	toc, err := json.Marshal(meta.TableOfContents)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE articles SET word_count = ?, reading_time = ?, excerpt = ?, toc = ? WHERE id = ?`
+	_, err = r.db.Exec(query, meta.WordCount, meta.ReadingTime, meta.Excerpt, string(toc), articleID)
+	return err
+}
+
+// SetStatus đổi status và published_at của article, trả về nil nếu article không tồn tại
+// Lịch hẹn publish (publish_at) bị hủy.
+func (r *mysqlArticleRepository) SetStatus(articleID int, status string, publishedAt *time.Time) (*models.Article, error) {
+	query := `UPDATE articles SET status = ?, published_at = ?, publish_at = NULL, updated_at = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, status, publishedAt, time.Now(), articleID); err != nil {
+		return nil, err
+	}
+	return r.GetByID(articleID)
+}
+
+// Schedule hẹn giờ publish article: status scheduled và publish_at, trả về nil nếu article không tồn tại
+func (r *mysqlArticleRepository) Schedule(articleID int, publishAt time.Time) (*models.Article, error) {
+	query := `UPDATE articles SET status = ?, publish_at = ?, updated_at = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, models.ArticleStatusScheduled, publishAt, time.Now(), articleID); err != nil {
+		return nil, err
+	}
+	return r.GetByID(articleID)
+}
+
+// Hold chuyển article sang pending chờ moderators duyệt
+// published_at và lịch hẹn publish_at được giữ nguyên để dùng lại khi article được duyệt.
+func (r *mysqlArticleRepository) Hold(articleID int) error {
+	query := `UPDATE articles SET status = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, models.ArticleStatusPending, time.Now(), articleID)
+	return err
+}
+
+// PublishDue publish tối đa limit articles scheduled có publish_at <= now, trả về ID các articles đã publish
+// published_at là publish_at nếu article chưa từng publish.
+// Phải gọi trong UnitOfWork: các dòng được khóa bằng FOR UPDATE SKIP LOCKED nên nhiều
+// replica chạy cùng lúc sẽ lấy các articles khác nhau thay vì chờ nhau hoặc publish trùng.
+func (r *mysqlArticleRepository) PublishDue(now time.Time, limit int) ([]int, error) {
+	query := `SELECT a.id FROM articles a
+	          WHERE a.status = ? AND a.publish_at <= ? AND a.deleted_at IS NULL
+	          ORDER BY a.publish_at, a.id
+	          LIMIT ?
+	          FOR UPDATE SKIP LOCKED`
+
+	rows, err := r.db.Query(query, models.ArticleStatusScheduled, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	update := `UPDATE articles 
+	           SET status = ?, published_at = COALESCE(published_at, publish_at), publish_at = NULL, updated_at = ? 
+	           WHERE id IN (` + inPlaceholders(len(ids)) + `)`
+	args := append([]interface{}{models.ArticleStatusPublished, now}, intArgs(ids)...)
+	if _, err := r.db.Exec(update, args...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete chuyển article vào thùng rác (xóa mềm)
+// Tags, favorites, comments và lịch sử slug được giữ nguyên để có thể khôi phục; slug vẫn bị chiếm
+// cho tới khi article bị xóa hẳn bởi PurgeDeleted.
+func (r *mysqlArticleRepository) Delete(articleID int) error {
+	query := `UPDATE articles SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, time.Now(), articleID)
+	return err
+}
+
+// Restore đưa article ra khỏi thùng rác
+func (r *mysqlArticleRepository) Restore(articleID int) error {
+	query := `UPDATE articles SET deleted_at = NULL WHERE id = ?`
+	_, err := r.db.Exec(query, articleID)
 	return err
 }
 
+// GetDeletedBySlug lấy article trong thùng rác theo slug, trả về nil nếu không có
+func (r *mysqlArticleRepository) GetDeletedBySlug(slug string) (*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a WHERE a.slug = ? AND a.deleted_at IS NOT NULL`
+
+	article, err := scanArticle(r.db.QueryRow(query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// ListDeleted lấy các articles của author bị xóa sau deletedAfter, mới xóa trước
+func (r *mysqlArticleRepository) ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a
+	          WHERE a.author_id = ? AND a.deleted_at > ?
+	          ORDER BY a.deleted_at DESC, a.id DESC`
+	return r.queryArticles(query, authorID, deletedAfter)
+}
+
+// PurgeDeleted xóa hẳn tối đa limit articles bị xóa trước deletedBefore, trả về số articles đã xóa
+// Tags, favorites, comments, lịch sử slug và revisions bị xóa theo (ON DELETE CASCADE).
+func (r *mysqlArticleRepository) PurgeDeleted(deletedBefore time.Time, limit int) (int, error) {
+	query := `DELETE FROM articles WHERE deleted_at IS NOT NULL AND deleted_at <= ? ORDER BY deleted_at LIMIT ?`
+	result, err := r.db.Exec(query, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
 // IsSlugExists kiểm tra xem slug đã tồn tại chưa
-func (r *ArticleRepository) IsSlugExists(slug string) (bool, error) {
-	query := `SELECT COUNT(*) FROM articles WHERE slug = ?`
+// Slug cũ trong article_slug_history cũng tính là đã tồn tại để link cũ không trỏ sang article khác
+func (r *mysqlArticleRepository) IsSlugExists(slug string) (bool, error) {
+	query := `SELECT (SELECT COUNT(*) FROM articles WHERE slug = ?) + 
+	          (SELECT COUNT(*) FROM article_slug_history WHERE slug = ?)`
 	var count int
-	err := database.DB.QueryRow(query, slug).Scan(&count)
+	err := r.db.QueryRow(query, slug, slug).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+// AddSlugHistory lưu slug cũ của article vào article_slug_history
+// Nếu slug đã có trong lịch sử thì chuyển sang trỏ về articleID
+func (r *mysqlArticleRepository) AddSlugHistory(articleID int, slug string) error {
+	query := `INSERT INTO article_slug_history (slug, article_id, created_at) VALUES (?, ?, ?) 
+	          ON DUPLICATE KEY UPDATE article_id = VALUES(article_id), created_at = VALUES(created_at)`
+	_, err := r.db.Exec(query, slug, articleID, time.Now())
+	return err
+}
+
+// DeleteSlugHistory xóa slug khỏi lịch sử, dùng khi article dùng lại slug cũ của chính nó
+func (r *mysqlArticleRepository) DeleteSlugHistory(slug string) error {
+	query := `DELETE FROM article_slug_history WHERE slug = ?`
+	_, err := r.db.Exec(query, slug)
+	return err
+}
+
+// GetBySlugHistory lấy article hiện tại từ một slug cũ, trả về nil nếu slug không có trong lịch sử
+func (r *mysqlArticleRepository) GetBySlugHistory(slug string) (*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` 
+	          FROM article_slug_history h
+	          INNER JOIN articles a ON h.article_id = a.id
+	          WHERE h.slug = ? AND a.deleted_at IS NULL`
+
+	article, err := scanArticle(r.db.QueryRow(query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return article, nil
+}
+
 // Favorite thêm article vào favorites của user
-func (r *ArticleRepository) Favorite(userID, articleID int) error {
+// Nên gọi trong UnitOfWork để insert favorites và tăng favorites_count cùng commit
+func (r *mysqlArticleRepository) Favorite(userID, articleID int) error {
 	query := `INSERT INTO favorites (user_id, article_id) VALUES (?, ?)`
-	_, err := database.DB.Exec(query, userID, articleID)
+	_, err := r.db.Exec(query, userID, articleID)
 	if err != nil {
-		// Nếu đã favorite rồi thì không báo lỗi
-		return nil
+		// Nếu đã favorite rồi thì không báo lỗi, các lỗi khác phải trả về
+		if IsDuplicateEntry(err) {
+			return nil
+		}
+		return err
 	}
 
 	// Tăng favorites_count
 	updateQuery := `UPDATE articles SET favorites_count = favorites_count + 1 WHERE id = ?`
-	_, err = database.DB.Exec(updateQuery, articleID)
+	_, err = r.db.Exec(updateQuery, articleID)
 	return err
 }
 
 // Unfavorite xóa article khỏi favorites của user
-func (r *ArticleRepository) Unfavorite(userID, articleID int) error {
+// Nên gọi trong UnitOfWork để xóa favorites và giảm favorites_count cùng commit
+func (r *mysqlArticleRepository) Unfavorite(userID, articleID int) error {
 	query := `DELETE FROM favorites WHERE user_id = ? AND article_id = ?`
-	result, err := database.DB.Exec(query, userID, articleID)
+	result, err := r.db.Exec(query, userID, articleID)
 	if err != nil {
 		return err
 	}
@@ -353,7 +484,7 @@ func (r *ArticleRepository) Unfavorite(userID, articleID int) error {
 	// Nếu có xóa thì giảm favorites_count
 	if rowsAffected > 0 {
 		updateQuery := `UPDATE articles SET favorites_count = favorites_count - 1 WHERE id = ?`
-		_, err = database.DB.Exec(updateQuery, articleID)
+		_, err = r.db.Exec(updateQuery, articleID)
 		return err
 	}
 
@@ -361,10 +492,10 @@ func (r *ArticleRepository) Unfavorite(userID, articleID int) error {
 }
 
 // IsFavorited kiểm tra xem user đã favorite article chưa
-func (r *ArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
+func (r *mysqlArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
 	query := `SELECT COUNT(*) FROM favorites WHERE user_id = ? AND article_id = ?`
 	var count int
-	err := database.DB.QueryRow(query, userID, articleID).Scan(&count)
+	err := r.db.QueryRow(query, userID, articleID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -372,14 +503,14 @@ func (r *ArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
 }
 
 // GetTagsByArticleID lấy danh sách tags của article
-func (r *ArticleRepository) GetTagsByArticleID(articleID int) ([]string, error) {
+func (r *mysqlArticleRepository) GetTagsByArticleID(articleID int) ([]string, error) {
 	query := `SELECT t.name 
 	          FROM tags t
 	          INNER JOIN article_tags at ON t.id = at.tag_id
 	          WHERE at.article_id = ?
 	          ORDER BY t.name`
 
-	rows, err := database.DB.Query(query, articleID)
+	rows, err := r.db.Query(query, articleID)
 	if err != nil {
 		return nil, err
 	}
@@ -398,8 +529,177 @@ func (r *ArticleRepository) GetTagsByArticleID(articleID int) ([]string, error)
 	return tags, nil
 }
 
-// GetAuthorByID lấy thông tin author của article
-func (r *ArticleRepository) GetAuthorByID(authorID int) (*models.User, error) {
-	userRepo := NewUserRepository()
-	return userRepo.GetByID(authorID)
+// GetTagsByArticleIDs lấy tags của nhiều articles trong một query
+// Trả về map article_id -> tags (sắp xếp theo tên); article không có tag thì không có trong map
+func (r *mysqlArticleRepository) GetTagsByArticleIDs(articleIDs []int) (map[int][]string, error) {
+	tagsByArticle := map[int][]string{}
+	if len(articleIDs) == 0 {
+		return tagsByArticle, nil
+	}
+
+	query := `SELECT at.article_id, t.name 
+	          FROM tags t
+	          INNER JOIN article_tags at ON t.id = at.tag_id
+	          WHERE at.article_id IN (` + inPlaceholders(len(articleIDs)) + `)
+	          ORDER BY at.article_id, t.name`
+
+	rows, err := r.db.Query(query, intArgs(articleIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int
+		var tag string
+		if err := rows.Scan(&articleID, &tag); err != nil {
+			return nil, err
+		}
+		tagsByArticle[articleID] = append(tagsByArticle[articleID], tag)
+	}
+
+	return tagsByArticle, rows.Err()
+}
+
+// GetFavoritedArticleIDs kiểm tra user đã favorite những article nào trong articleIDs
+// Trả về set các article_id đã được favorite
+func (r *mysqlArticleRepository) GetFavoritedArticleIDs(userID int, articleIDs []int) (map[int]bool, error) {
+	favorited := map[int]bool{}
+	if len(articleIDs) == 0 {
+		return favorited, nil
+	}
+
+	query := `SELECT article_id FROM favorites 
+	          WHERE user_id = ? AND article_id IN (` + inPlaceholders(len(articleIDs)) + `)`
+	args := append([]interface{}{userID}, intArgs(articleIDs)...)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int
+		if err := rows.Scan(&articleID); err != nil {
+			return nil, err
+		}
+		favorited[articleID] = true
+	}
+
+	return favorited, rows.Err()
+}
+
+// GetCommentsCounts đếm comments chưa xóa, không bị ẩn của từng article trong articleIDs
+// Article không có comment nào không có trong map.
+func (r *mysqlArticleRepository) GetCommentsCounts(articleIDs []int) (map[int]int, error) {
+	counts := map[int]int{}
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `SELECT article_id, COUNT(*) FROM comments 
+	          WHERE article_id IN (` + inPlaceholders(len(articleIDs)) + `) AND deleted_at IS NULL AND hidden_at IS NULL
+	          GROUP BY article_id`
+
+	rows, err := r.db.Query(query, intArgs(articleIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID, count int
+		if err := rows.Scan(&articleID, &count); err != nil {
+			return nil, err
+		}
+		counts[articleID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// HasRecentBody kiểm tra author đã lưu article chưa xóa có đúng body này từ since trở lại đây chưa
+// excludeArticleID là article đang sửa (0 khi tạo mới), không tính là trùng với chính nó.
+func (r *mysqlArticleRepository) HasRecentBody(authorID int, body string, since time.Time, excludeArticleID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM articles 
+	          WHERE author_id = ? AND body = ? AND updated_at >= ? AND id <> ? AND deleted_at IS NULL)`
+	var exists bool
+	err := r.db.QueryRow(query, authorID, body, since, excludeArticleID).Scan(&exists)
+	return exists, err
+}
+
+// SetReviewReason ghi lý do cần moderators xem xét article, reason nil là đã xem xét xong
+func (r *mysqlArticleRepository) SetReviewReason(articleID int, reason *string) error {
+	query := `UPDATE articles SET review_reason = ? WHERE id = ?`
+	_, err := r.db.Exec(query, reason, articleID)
+	return err
+}
+
+// ListForReview lấy các articles chưa xóa đang chờ moderators xem xét, sửa sớm nhất trước
+func (r *mysqlArticleRepository) ListForReview(limit, offset int) ([]*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a
+	          WHERE a.review_reason IS NOT NULL AND a.deleted_at IS NULL
+	          ORDER BY a.updated_at ASC, a.id ASC
+	          LIMIT ? OFFSET ?`
+	return r.queryArticles(query, limit, offset)
+}
+
+// CountForReview đếm số articles chưa xóa đang chờ moderators xem xét
+func (r *mysqlArticleRepository) CountForReview() (int, error) {
+	query := `SELECT COUNT(*) FROM articles WHERE review_reason IS NOT NULL AND deleted_at IS NULL`
+	var count int
+	err := r.db.QueryRow(query).Scan(&count)
+	return count, err
+}
+
+// searchMatch là biểu thức FULLTEXT trên title, description, body (index ft_articles_content)
+const searchMatch = `MATCH(a.title, a.description, a.body) AGAINST (? IN NATURAL LANGUAGE MODE)`
+
+// searchQuery build điều kiện cho Search/SearchCount: filter và chỉ lấy articles khớp từ khóa
+func searchQuery(query string, filter ArticleFilter) *articleQuery {
+	q := newArticleQuery(filter)
+	q.where(searchMatch, query)
+	return q
+}
+
+// Search tìm articles theo title, description, body bằng FULLTEXT index
+// Kết quả sắp xếp theo relevance giảm dần (filter.Sort không áp dụng); khớp ở title được nhân đôi điểm.
+func (r *mysqlArticleRepository) Search(query string, filter ArticleFilter, limit, offset int) ([]*ArticleSearchResult, error) {
+	q := searchQuery(query, filter)
+
+	sqlQuery := `SELECT ` + articleColumns + `, 
+	             MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE) * 2 + ` + searchMatch + ` AS score 
+	             FROM articles a` + q.whereClause() + ` ORDER BY score DESC, a.created_at DESC, a.id DESC LIMIT ? OFFSET ?`
+	args := append([]interface{}{query, query}, q.args...)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*ArticleSearchResult
+	for rows.Next() {
+		result := &ArticleSearchResult{}
+		result.Article, err = scanArticle(rows, &result.Score)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// SearchCount đếm tổng số articles khớp từ khóa và filter
+func (r *mysqlArticleRepository) SearchCount(query string, filter ArticleFilter) (int, error) {
+	q := searchQuery(query, filter)
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM articles a`+q.whereClause(), q.args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
 }
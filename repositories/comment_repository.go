@@ -7,67 +7,258 @@ import (
 	"time"
 )
 
-// CommentRepository chứa các method để làm việc với bảng comments
-type CommentRepository struct{}
+// CommentRepository định nghĩa các thao tác dữ liệu trên bảng comments và các bảng kiểm duyệt comments
+// Delete là xóa mềm (deleted_at); mọi query đọc bỏ qua comments đã xóa trừ các method *Deleted*
+// và GetByArticleID (comment đã xóa còn replies được giữ làm placeholder). Comment bị ẩn (hidden_at)
+// vẫn đọc được theo ID nhưng GetByArticleID coi như đã xóa.
+// Create, Delete, Restore, Hide và Unhide cập nhật replies_count của các tổ tiên bằng nhiều statements,
+// caller nên gọi trong UnitOfWork để chúng được ghi cùng nhau.
+type CommentRepository interface {
+	Create(articleID, authorID int, parent *models.Comment, body, bodyHTML string) (*models.Comment, error)
+	GetByID(id int) (*models.Comment, error)
+	GetByArticleID(articleID int, order CommentSort, after *Cursor, limit int) ([]*models.Comment, error)
+	Update(commentID int, body, bodyHTML string, editedAt time.Time) error
+	CreateEdit(commentID int, body, bodyHTML string, editedAt time.Time) error
+	ListEdits(commentID int) ([]*models.CommentEdit, error)
+	Delete(commentID int) error
+	Restore(commentID int) error
+	GetDeletedByID(id int) (*models.Comment, error)
+	ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Comment, error)
+	PurgeDeleted(deletedBefore time.Time, limit int) (int, error)
+	Hide(commentID int, hiddenAt time.Time) error
+	Unhide(commentID int) error
+	HasRecentBody(authorID int, body string, since time.Time, excludeCommentID int) (bool, error)
+	CreateReport(commentID int, reporterID *int, reason string) error
+	ListReported(limit, offset int) ([]*models.ReportedComment, error)
+	CountReported() (int, error)
+	GetOpenReports(commentIDs []int) (map[int][]*models.CommentReport, error)
+	ResolveReports(commentID int, resolvedAt time.Time) error
+	CreateAction(commentID, actorID int, action string, createdAt time.Time) error
+	GetActions(commentIDs []int) (map[int][]*models.CommentModerationAction, error)
+}
+
+// CommentSort là thứ tự sắp xếp danh sách comments của một article
+type CommentSort string
+
+const (
+	// CommentSortNewest sắp xếp mới đăng trước (mặc định)
+	CommentSortNewest CommentSort = "newest"
+	// CommentSortOldest sắp xếp đăng sớm nhất trước
+	CommentSortOldest CommentSort = "oldest"
+	// CommentSortTop sắp xếp nhiều replies trực tiếp nhất trước, cùng số replies thì mới đăng trước
+	CommentSortTop CommentSort = "top"
+)
 
-// NewCommentRepository tạo instance mới của CommentRepository
-func NewCommentRepository() *CommentRepository {
-	return &CommentRepository{}
+// commentSortSpec mô tả một thứ tự sắp xếp comments: cột SQL, chiều và cách lấy key cho cursor
+type commentSortSpec struct {
+	column string
+	desc   bool
+	// key lấy giá trị cột sắp xếp của comment để so sánh và tạo cursor
+	key func(comment *models.Comment) int64
+	// keyArg chuyển Cursor.Key thành tham số SQL cùng kiểu với cột
+	keyArg func(cursor *Cursor) interface{}
 }
 
-// Create tạo comment mới trong database
-func (r *CommentRepository) Create(articleID, authorID int, body string) (*models.Comment, error) {
-	query := `INSERT INTO comments (article_id, author_id, body, created_at, updated_at) 
-	          VALUES (?, ?, ?, ?, ?)`
+// commentSorts là các thứ tự sắp xếp được hỗ trợ; id luôn là tie-breaker để thứ tự ổn định
+var commentSorts = map[CommentSort]commentSortSpec{
+	CommentSortNewest: {
+		column: "created_at",
+		desc:   true,
+		key:    func(c *models.Comment) int64 { return c.CreatedAt.UnixNano() },
+		keyArg: func(c *Cursor) interface{} { return c.Time() },
+	},
+	CommentSortOldest: {
+		column: "created_at",
+		desc:   false,
+		key:    func(c *models.Comment) int64 { return c.CreatedAt.UnixNano() },
+		keyArg: func(c *Cursor) interface{} { return c.Time() },
+	},
+	CommentSortTop: {
+		column: "replies_count",
+		desc:   true,
+		key:    func(c *models.Comment) int64 { return int64(c.RepliesCount) },
+		keyArg: func(c *Cursor) interface{} { return c.Key },
+	},
+}
 
-	now := time.Now()
-	result, err := database.DB.Exec(query, articleID, authorID, body, now, now)
-	if err != nil {
-		return nil, err
+// IsValid kiểm tra sort có được hỗ trợ không, rỗng là hợp lệ (dùng CommentSortNewest)
+func (s CommentSort) IsValid() bool {
+	_, ok := commentSorts[s]
+	return s == "" || ok
+}
+
+// sortSpec trả về spec của sort, mặc định CommentSortNewest
+func (s CommentSort) sortSpec() commentSortSpec {
+	if spec, ok := commentSorts[s]; ok {
+		return spec
 	}
+	return commentSorts[CommentSortNewest]
+}
 
-	// Lấy ID vừa tạo
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, err
+// CursorFor tạo cursor trỏ tới comment trong danh sách sắp xếp theo s
+func (s CommentSort) CursorFor(comment *models.Comment) Cursor {
+	return Cursor{Key: s.sortSpec().key(comment), ID: comment.ID}
+}
+
+// less so sánh thứ tự hai comments theo sort
+func (spec commentSortSpec) less(a, b *models.Comment) bool {
+	ka, kb := spec.key(a), spec.key(b)
+	if ka != kb {
+		if spec.desc {
+			return ka > kb
+		}
+		return ka < kb
 	}
+	if spec.desc {
+		return a.ID > b.ID
+	}
+	return a.ID < b.ID
+}
 
-	// Lấy comment vừa tạo
-	return r.GetByID(int(id))
+// follows kiểm tra comment có đứng sau cursor theo sort không
+func (spec commentSortSpec) follows(comment *models.Comment, cursor *Cursor) bool {
+	if spec.desc {
+		return cursor.isBefore(spec.key(comment), comment.ID)
+	}
+	return cursor.isAfter(spec.key(comment), comment.ID)
 }
 
-// GetByID lấy comment theo ID
-func (r *CommentRepository) GetByID(id int) (*models.Comment, error) {
-	query := `SELECT id, article_id, author_id, body, created_at, updated_at 
-	          FROM comments WHERE id = ?`
+// mysqlCommentRepository implement CommentRepository bằng MySQL
+type mysqlCommentRepository struct {
+	db database.DBTX
+}
+
+// NewCommentRepository tạo CommentRepository dùng MySQL connection hoặc transaction db
+func NewCommentRepository(db database.DBTX) CommentRepository {
+	return &mysqlCommentRepository{db: db}
+}
+
+// commentColumns là các cột của bảng comments theo thứ tự scanComment đọc
+const commentColumns = `id, article_id, author_id, parent_id, depth, replies_count, body, COALESCE(body_html, ''), edited_at, hidden_at, deleted_at, created_at, updated_at`
 
+// scanComment đọc một dòng commentColumns, extra là các cột thêm sau commentColumns
+func scanComment(row rowScanner, extra ...interface{}) (*models.Comment, error) {
 	comment := &models.Comment{}
-	err := database.DB.QueryRow(query, id).Scan(
+	dest := []interface{}{
 		&comment.ID,
 		&comment.ArticleID,
 		&comment.AuthorID,
+		&comment.ParentID,
+		&comment.Depth,
+		&comment.RepliesCount,
 		&comment.Body,
+		&comment.BodyHTML,
+		&comment.EditedAt,
+		&comment.HiddenAt,
+		&comment.DeletedAt,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
-	)
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// queryComments chạy query trả về các dòng commentColumns
+func (r *mysqlCommentRepository) queryComments(query string, args ...interface{}) ([]*models.Comment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// queryComment chạy query trả về một dòng commentColumns, trả về nil nếu không có
+func (r *mysqlCommentRepository) queryComment(query string, args ...interface{}) (*models.Comment, error) {
+	comment, err := scanComment(r.db.QueryRow(query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-
 	return comment, nil
 }
 
-// GetByArticleID lấy tất cả comments của một article
-func (r *CommentRepository) GetByArticleID(articleID int) ([]*models.Comment, error) {
-	query := `SELECT id, article_id, author_id, body, created_at, updated_at 
-	          FROM comments 
-	          WHERE article_id = ? 
-	          ORDER BY created_at DESC`
+// Create tạo comment mới trong database, parent khác nil thì comment là reply của parent
+func (r *mysqlCommentRepository) Create(articleID, authorID int, parent *models.Comment, body, bodyHTML string) (*models.Comment, error) {
+	query := `INSERT INTO comments (article_id, author_id, parent_id, depth, body, body_html, created_at, updated_at) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var parentID *int
+	depth := 0
+	if parent != nil {
+		parentID = &parent.ID
+		depth = parent.Depth + 1
+	}
+
+	now := time.Now()
+	result, err := r.db.Exec(query, articleID, authorID, parentID, depth, body, bodyHTML, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy ID vừa tạo
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// Comment mới đang hiển thị: parent có thêm một reply
+	if err := r.propagateListed(int(id), false); err != nil {
+		return nil, err
+	}
+
+	// Lấy comment vừa tạo
+	return r.GetByID(int(id))
+}
+
+// GetByID lấy comment theo ID
+func (r *mysqlCommentRepository) GetByID(id int) (*models.Comment, error) {
+	return r.queryComment(`SELECT `+commentColumns+` FROM comments WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// GetByArticleID lấy comments của một article kèm số replies, sắp xếp theo order
+// Comment đã xóa hoặc bị ẩn vẫn được lấy nếu còn reply đang hiển thị ở bất kỳ cấp nào bên dưới
+// (placeholder giữ thread). after khác nil thì chỉ lấy comments đứng sau cursor; limit <= 0 là lấy tất cả
+func (r *mysqlCommentRepository) GetByArticleID(articleID int, order CommentSort, after *Cursor, limit int) ([]*models.Comment, error) {
+	// replies_count là số replies trực tiếp đang hiển thị, được giữ đúng khi ghi (propagateListed) nên
+	// comment có replies_count > 0 chính là placeholder cần giữ; sort top dùng idx_article_replies_count
+	query := `SELECT ` + commentColumns + ` FROM comments
+	          WHERE article_id = ? AND ((deleted_at IS NULL AND hidden_at IS NULL) OR replies_count > 0)`
+	args := []interface{}{articleID}
+
+	// Keyset pagination
+	spec := order.sortSpec()
+	op, direction := ">", " ASC"
+	if spec.desc {
+		op, direction = "<", " DESC"
+	}
+	if after != nil {
+		key := spec.keyArg(after)
+		query += " AND (" + spec.column + " " + op + " ? OR (" + spec.column + " = ? AND id " + op + " ?))"
+		args = append(args, key, key, after.ID)
+	}
+
+	query += " ORDER BY " + spec.column + direction + ", id" + direction
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
 
-	rows, err := database.DB.Query(query, articleID)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,33 +266,295 @@ func (r *CommentRepository) GetByArticleID(articleID int) ([]*models.Comment, er
 
 	var comments []*models.Comment
 	for rows.Next() {
-		comment := &models.Comment{}
-		err := rows.Scan(
-			&comment.ID,
-			&comment.ArticleID,
-			&comment.AuthorID,
-			&comment.Body,
-			&comment.CreatedAt,
-			&comment.UpdatedAt,
-		)
+		comment, err := scanComment(rows)
 		if err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
 	}
+	return comments, rows.Err()
+}
+
+// Update sửa body của comment và đánh dấu đã sửa lúc editedAt
+func (r *mysqlCommentRepository) Update(commentID int, body, bodyHTML string, editedAt time.Time) error {
+	query := `UPDATE comments SET body = ?, body_html = ?, edited_at = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, body, bodyHTML, editedAt, editedAt, commentID)
+	return err
+}
+
+// CreateEdit lưu body trước khi sửa của comment vào lịch sử chỉnh sửa
+func (r *mysqlCommentRepository) CreateEdit(commentID int, body, bodyHTML string, editedAt time.Time) error {
+	query := `INSERT INTO comment_edits (comment_id, body, body_html, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.Exec(query, commentID, body, bodyHTML, editedAt)
+	return err
+}
+
+// ListEdits lấy lịch sử chỉnh sửa của comment, lần sửa mới nhất trước
+func (r *mysqlCommentRepository) ListEdits(commentID int) ([]*models.CommentEdit, error) {
+	query := `SELECT id, comment_id, body, COALESCE(body_html, ''), created_at 
+	          FROM comment_edits 
+	          WHERE comment_id = ? 
+	          ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*models.CommentEdit
+	for rows.Next() {
+		edit := &models.CommentEdit{}
+		if err := rows.Scan(&edit.ID, &edit.CommentID, &edit.Body, &edit.BodyHTML, &edit.CreatedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// Delete chuyển comment vào thùng rác (xóa mềm)
+func (r *mysqlCommentRepository) Delete(commentID int) error {
+	query := `UPDATE comments SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	return r.updateListed(commentID, query, time.Now(), commentID)
+}
+
+// Restore đưa comment ra khỏi thùng rác
+func (r *mysqlCommentRepository) Restore(commentID int) error {
+	query := `UPDATE comments SET deleted_at = NULL WHERE id = ?`
+	return r.updateListed(commentID, query, commentID)
+}
+
+// GetDeletedByID lấy comment trong thùng rác theo ID, trả về nil nếu không có
+func (r *mysqlCommentRepository) GetDeletedByID(id int) (*models.Comment, error) {
+	return r.queryComment(`SELECT `+commentColumns+` FROM comments WHERE id = ? AND deleted_at IS NOT NULL`, id)
+}
+
+// ListDeleted lấy các comments của author bị xóa sau deletedAfter, mới xóa trước
+func (r *mysqlCommentRepository) ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments
+	          WHERE author_id = ? AND deleted_at > ?
+	          ORDER BY deleted_at DESC, id DESC`
+	return r.queryComments(query, authorID, deletedAfter)
+}
+
+// PurgeDeleted xóa hẳn tối đa limit comments bị xóa trước deletedBefore, trả về số comments đã xóa
+// Comment còn replies (kể cả replies trong thùng rác) được giữ lại tới khi mọi replies bị xóa hẳn.
+func (r *mysqlCommentRepository) PurgeDeleted(deletedBefore time.Time, limit int) (int, error) {
+	// Derived table (DISTINCT để không bị merge) vì MySQL không cho DELETE đọc chính bảng đó trong subquery
+	query := `DELETE FROM comments 
+	          WHERE deleted_at IS NOT NULL AND deleted_at <= ?
+	            AND id NOT IN (SELECT parent_id FROM (SELECT DISTINCT parent_id FROM comments WHERE parent_id IS NOT NULL) AS parents)
+	          ORDER BY deleted_at LIMIT ?`
+	result, err := r.db.Exec(query, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
+// Hide ẩn comment khỏi danh sách comments của article từ hiddenAt, comment đã bị ẩn thì giữ nguyên
+func (r *mysqlCommentRepository) Hide(commentID int, hiddenAt time.Time) error {
+	query := `UPDATE comments SET hidden_at = ? WHERE id = ? AND hidden_at IS NULL`
+	return r.updateListed(commentID, query, hiddenAt, commentID)
+}
+
+// Unhide hiển thị lại comment bị ẩn
+func (r *mysqlCommentRepository) Unhide(commentID int) error {
+	query := `UPDATE comments SET hidden_at = NULL WHERE id = ?`
+	return r.updateListed(commentID, query, commentID)
+}
+
+// listedState đọc parent_id của comment và comment có nằm trong danh sách comments của article không:
+// chưa xóa và chưa bị ẩn, hoặc còn reply đang hiển thị. Comment không tồn tại coi như không nằm trong danh sách.
+// Dòng được khóa (FOR UPDATE) tới hết transaction để replies_count không bị ghi đè khi cập nhật đồng thời.
+func (r *mysqlCommentRepository) listedState(commentID int) (*int, bool, error) {
+	query := `SELECT parent_id, (deleted_at IS NULL AND hidden_at IS NULL) OR replies_count > 0
+	          FROM comments WHERE id = ? FOR UPDATE`
+	var parentID sql.NullInt64
+	var listed bool
+	if err := r.db.QueryRow(query, commentID).Scan(&parentID, &listed); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if !parentID.Valid {
+		return nil, listed, nil
+	}
+	id := int(parentID.Int64)
+	return &id, listed, nil
+}
+
+// updateListed chạy query thay đổi deleted_at/hidden_at của comment rồi cập nhật replies_count của các tổ tiên
+func (r *mysqlCommentRepository) updateListed(commentID int, query string, args ...interface{}) error {
+	_, wasListed, err := r.listedState(commentID)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return err
+	}
+	return r.propagateListed(commentID, wasListed)
+}
+
+// propagateListed cập nhật replies_count của parent khi comment vào hoặc ra khỏi danh sách (wasListed là
+// trạng thái trước khi ghi), rồi lặp lại với parent nếu trạng thái của parent cũng đổi theo
+func (r *mysqlCommentRepository) propagateListed(commentID int, wasListed bool) error {
+	for {
+		parentID, listed, err := r.listedState(commentID)
+		if err != nil {
+			return err
+		}
+		if listed == wasListed || parentID == nil {
+			return nil
+		}
+		_, parentWasListed, err := r.listedState(*parentID)
+		if err != nil {
+			return err
+		}
+		delta := 1
+		if !listed {
+			delta = -1
+		}
+		if _, err := r.db.Exec(`UPDATE comments SET replies_count = replies_count + ? WHERE id = ?`, delta, *parentID); err != nil {
+			return err
+		}
+		commentID, wasListed = *parentID, parentWasListed
+	}
+}
+
+// HasRecentBody kiểm tra author đã đăng comment chưa xóa có đúng body này từ since trở lại đây chưa
+// excludeCommentID là comment đang sửa (0 khi tạo mới), không tính là trùng với chính nó.
+func (r *mysqlCommentRepository) HasRecentBody(authorID int, body string, since time.Time, excludeCommentID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM comments 
+	          WHERE author_id = ? AND body = ? AND created_at >= ? AND id <> ? AND deleted_at IS NULL)`
+	var exists bool
+	err := r.db.QueryRow(query, authorID, body, since, excludeCommentID).Scan(&exists)
+	return exists, err
+}
+
+// CreateReport lưu report của reporterID về comment, reporter đã report comment này rồi thì bỏ qua
+// reporterID nil là report do content filter tạo.
+func (r *mysqlCommentRepository) CreateReport(commentID int, reporterID *int, reason string) error {
+	query := `INSERT INTO comment_reports (comment_id, reporter_id, reason, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.Exec(query, commentID, reporterID, reason, time.Now())
+	if err != nil && IsDuplicateEntry(err) {
+		return nil
+	}
+	return err
+}
+
+// reportedFrom là phần FROM của hàng đợi kiểm duyệt: comments chưa xóa trên articles chưa xóa
+// còn reports chưa xử lý, kèm số reports và thời điểm report sớm nhất
+const reportedFrom = `FROM comments c
+	          INNER JOIN articles a ON a.id = c.article_id AND a.deleted_at IS NULL
+	          INNER JOIN (SELECT comment_id, COUNT(*) AS reports_count, MIN(created_at) AS first_reported_at
+	                      FROM comment_reports WHERE resolved_at IS NULL GROUP BY comment_id) r ON r.comment_id = c.id
+	          WHERE c.deleted_at IS NULL`
+
+// ListReported lấy các comments còn reports chưa xử lý, report sớm nhất trước
+func (r *mysqlCommentRepository) ListReported(limit, offset int) ([]*models.ReportedComment, error) {
+	query := `SELECT ` + commentColumns + `, article_slug, reports_count, first_reported_at
+	          FROM (SELECT c.*, a.slug AS article_slug, r.reports_count, r.first_reported_at ` + reportedFrom + `) reported
+	          ORDER BY first_reported_at ASC, id ASC
+	          LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reported []*models.ReportedComment
+	for rows.Next() {
+		item := &models.ReportedComment{}
+		comment, err := scanComment(rows, &item.ArticleSlug, &item.ReportsCount, &item.FirstReportedAt)
+		if err != nil {
+			return nil, err
+		}
+		item.Comment = *comment
+		reported = append(reported, item)
+	}
+	return reported, rows.Err()
+}
+
+// CountReported đếm số comments trong hàng đợi kiểm duyệt
+func (r *mysqlCommentRepository) CountReported() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) ` + reportedFrom).Scan(&count)
+	return count, err
+}
+
+// GetOpenReports lấy reports chưa xử lý của các comments, report sớm nhất trước
+// Comment không có report nào không có trong map.
+func (r *mysqlCommentRepository) GetOpenReports(commentIDs []int) (map[int][]*models.CommentReport, error) {
+	reports := map[int][]*models.CommentReport{}
+	if len(commentIDs) == 0 {
+		return reports, nil
+	}
+
+	query := `SELECT id, comment_id, reporter_id, reason, created_at, resolved_at 
+	          FROM comment_reports 
+	          WHERE comment_id IN (` + inPlaceholders(len(commentIDs)) + `) AND resolved_at IS NULL 
+	          ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, intArgs(commentIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		report := &models.CommentReport{}
+		if err := rows.Scan(&report.ID, &report.CommentID, &report.ReporterID, &report.Reason, &report.CreatedAt, &report.ResolvedAt); err != nil {
+			return nil, err
+		}
+		reports[report.CommentID] = append(reports[report.CommentID], report)
+	}
+	return reports, rows.Err()
+}
 
-	return comments, nil
+// ResolveReports đánh dấu mọi reports chưa xử lý của comment là đã xử lý lúc resolvedAt
+func (r *mysqlCommentRepository) ResolveReports(commentID int, resolvedAt time.Time) error {
+	query := `UPDATE comment_reports SET resolved_at = ? WHERE comment_id = ? AND resolved_at IS NULL`
+	_, err := r.db.Exec(query, resolvedAt, commentID)
+	return err
 }
 
-// Delete xóa comment
-func (r *CommentRepository) Delete(commentID int) error {
-	query := `DELETE FROM comments WHERE id = ?`
-	_, err := database.DB.Exec(query, commentID)
+// CreateAction ghi lại hành động kiểm duyệt action của actorID với comment lúc createdAt
+func (r *mysqlCommentRepository) CreateAction(commentID, actorID int, action string, createdAt time.Time) error {
+	query := `INSERT INTO comment_moderation_actions (comment_id, actor_id, action, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.Exec(query, commentID, actorID, action, createdAt)
 	return err
 }
 
-// GetAuthorByID lấy thông tin author của comment
-func (r *CommentRepository) GetAuthorByID(authorID int) (*models.User, error) {
-	userRepo := NewUserRepository()
-	return userRepo.GetByID(authorID)
+// GetActions lấy lịch sử kiểm duyệt của các comments, hành động mới nhất trước
+// Comment chưa từng bị kiểm duyệt không có trong map.
+func (r *mysqlCommentRepository) GetActions(commentIDs []int) (map[int][]*models.CommentModerationAction, error) {
+	actions := map[int][]*models.CommentModerationAction{}
+	if len(commentIDs) == 0 {
+		return actions, nil
+	}
+
+	query := `SELECT id, comment_id, actor_id, action, created_at 
+	          FROM comment_moderation_actions 
+	          WHERE comment_id IN (` + inPlaceholders(len(commentIDs)) + `) 
+	          ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, intArgs(commentIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		action := &models.CommentModerationAction{}
+		if err := rows.Scan(&action.ID, &action.CommentID, &action.ActorID, &action.Action, &action.CreatedAt); err != nil {
+			return nil, err
+		}
+		actions[action.CommentID] = append(actions[action.CommentID], action)
+	}
+	return actions, rows.Err()
 }
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor được trả về khi cursor client gửi lên không decode được
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor là vị trí trong danh sách đã sắp xếp theo (cột sắp xếp, id)
+// Key là giá trị cột sắp xếp của bản ghi cuối trang: UnixNano với created_at/updated_at,
+// hoặc chính giá trị với favorites_count. Trang tiếp theo lấy các bản ghi đứng sau Cursor
+// theo thứ tự đó (keyset pagination), nên không bị trùng/sót khi có bản ghi mới được thêm vào
+// trong lúc client đang cuộn.
+type Cursor struct {
+	Key int64
+	ID  int
+}
+
+// TimeCursor tạo cursor cho danh sách sắp xếp theo một cột thời gian
+func TimeCursor(t time.Time, id int) Cursor {
+	return Cursor{Key: t.UnixNano(), ID: id}
+}
+
+// Time trả về Key dưới dạng thời gian
+func (c Cursor) Time() time.Time {
+	return time.Unix(0, c.Key)
+}
+
+// Encode mã hóa cursor thành chuỗi opaque để trả cho client
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Key, 10) + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor giải mã chuỗi cursor do Encode tạo ra
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	keyStr, idStr, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+	key, err := strconv.ParseInt(keyStr, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Key: key, ID: id}, nil
+}
+
+// isBefore kiểm tra bản ghi (key, id) có đứng sau cursor trong danh sách giảm dần không
+// Danh sách tăng dần thì dùng isAfter.
+func (c *Cursor) isBefore(key int64, id int) bool {
+	if key != c.Key {
+		return key < c.Key
+	}
+	return id < c.ID
+}
+
+// isAfter kiểm tra bản ghi (key, id) có đứng sau cursor trong danh sách tăng dần không
+func (c *Cursor) isAfter(key int64, id int) bool {
+	if key != c.Key {
+		return key > c.Key
+	}
+	return id > c.ID
+}
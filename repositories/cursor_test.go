@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCursor_EncodeDecode kiểm tra cursor encode rồi decode lại được giá trị ban đầu
+func TestCursor_EncodeDecode(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.UTC)
+	cursor := TimeCursor(createdAt, 42)
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(decoded.Time()))
+	assert.Equal(t, 42, decoded.ID)
+
+	for _, invalid := range []string{"", "not base64!", "bm9jb2xvbg", "MTIzOmFiYw"} {
+		_, err := DecodeCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, "cursor %q", invalid)
+	}
+}
@@ -5,34 +5,44 @@ import (
 	"news/database"
 )
 
-// FollowRepository chứa các method để làm việc với bảng follows
-type FollowRepository struct{}
+// FollowRepository định nghĩa các thao tác dữ liệu trên bảng follows
+type FollowRepository interface {
+	Follow(followerID, followingID int) error
+	Unfollow(followerID, followingID int) error
+	IsFollowing(followerID, followingID int) (bool, error)
+	GetFollowingIDs(followerID int, followingIDs []int) (map[int]bool, error)
+}
+
+// mysqlFollowRepository implement FollowRepository bằng MySQL
+type mysqlFollowRepository struct {
+	db database.DBTX
+}
 
-// NewFollowRepository tạo instance mới của FollowRepository
-func NewFollowRepository() *FollowRepository {
-	return &FollowRepository{}
+// NewFollowRepository tạo FollowRepository dùng MySQL connection hoặc transaction db
+func NewFollowRepository(db database.DBTX) FollowRepository {
+	return &mysqlFollowRepository{db: db}
 }
 
 // Follow tạo relationship follow giữa follower và following
 // follower_id follow following_id
-func (r *FollowRepository) Follow(followerID, followingID int) error {
+func (r *mysqlFollowRepository) Follow(followerID, followingID int) error {
 	query := `INSERT INTO follows (follower_id, following_id) VALUES (?, ?)`
-	_, err := database.DB.Exec(query, followerID, followingID)
+	_, err := r.db.Exec(query, followerID, followingID)
 	return err
 }
 
 // Unfollow xóa relationship follow
-func (r *FollowRepository) Unfollow(followerID, followingID int) error {
+func (r *mysqlFollowRepository) Unfollow(followerID, followingID int) error {
 	query := `DELETE FROM follows WHERE follower_id = ? AND following_id = ?`
-	_, err := database.DB.Exec(query, followerID, followingID)
+	_, err := r.db.Exec(query, followerID, followingID)
 	return err
 }
 
 // IsFollowing kiểm tra xem follower có đang follow following không
-func (r *FollowRepository) IsFollowing(followerID, followingID int) (bool, error) {
+func (r *mysqlFollowRepository) IsFollowing(followerID, followingID int) (bool, error) {
 	query := `SELECT COUNT(*) FROM follows WHERE follower_id = ? AND following_id = ?`
 	var count int
-	err := database.DB.QueryRow(query, followerID, followingID).Scan(&count)
+	err := r.db.QueryRow(query, followerID, followingID).Scan(&count)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
@@ -41,3 +51,32 @@ func (r *FollowRepository) IsFollowing(followerID, followingID int) (bool, error
 	}
 	return count > 0, nil
 }
+
+// GetFollowingIDs kiểm tra follower đang follow những user nào trong followingIDs
+// Trả về set các following_id đang được follow
+func (r *mysqlFollowRepository) GetFollowingIDs(followerID int, followingIDs []int) (map[int]bool, error) {
+	following := map[int]bool{}
+	if len(followingIDs) == 0 {
+		return following, nil
+	}
+
+	query := `SELECT following_id FROM follows 
+	          WHERE follower_id = ? AND following_id IN (` + inPlaceholders(len(followingIDs)) + `)`
+	args := append([]interface{}{followerID}, intArgs(followingIDs)...)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var followingID int
+		if err := rows.Scan(&followingID); err != nil {
+			return nil, err
+		}
+		following[followingID] = true
+	}
+
+	return following, rows.Err()
+}
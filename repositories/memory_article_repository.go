@@ -0,0 +1,674 @@
+package repositories
+
+import (
+	"news/models"
+	"sort"
+	"time"
+)
+
+// memoryArticleRepository implement ArticleRepository bằng MemoryStore
+type memoryArticleRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryArticleRepository tạo ArticleRepository lưu dữ liệu trong store
+func NewMemoryArticleRepository(store *MemoryStore) ArticleRepository {
+	return &memoryArticleRepository{store: store}
+}
+
+// Create tạo article mới, article published có published_at là thời điểm tạo
+func (r *memoryArticleRepository) Create(slug, title, description, body, bodyHTML string, authorID int, status string) (*models.Article, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, article := range r.store.articles {
+		if article.Slug == slug {
+			return nil, ErrDuplicateEntry
+		}
+	}
+
+	now := time.Now()
+	article := &models.Article{
+		ID:          r.store.nextArticleID,
+		Slug:        slug,
+		Title:       title,
+		Description: description,
+		Body:        body,
+		BodyHTML:    bodyHTML,
+		Status:      status,
+		AuthorID:    authorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if status == models.ArticleStatusPublished {
+		article.PublishedAt = &now
+	}
+	r.store.nextArticleID++
+	r.store.articles[article.ID] = article
+
+	return copyArticle(article), nil
+}
+
+// GetByID lấy article theo ID, trả về nil nếu không tồn tại hoặc đã bị xóa
+func (r *memoryArticleRepository) GetByID(id int) (*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	article, ok := r.store.articles[id]
+	if !ok || article.IsDeleted() {
+		return nil, nil
+	}
+	return copyArticle(article), nil
+}
+
+// GetBySlug lấy article theo slug, trả về nil nếu không tồn tại hoặc đã bị xóa
+func (r *memoryArticleRepository) GetBySlug(slug string) (*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, article := range r.store.articles {
+		if article.Slug == slug && !article.IsDeleted() {
+			return copyArticle(article), nil
+		}
+	}
+	return nil, nil
+}
+
+// List lấy danh sách articles theo filter và pagination, sắp xếp theo filter.Sort
+func (r *memoryArticleRepository) List(filter ArticleFilter, after *Cursor, limit, offset int) ([]*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return paginate(afterCursor(r.filter(filter), after, filter.Sort), limit, offset), nil
+}
+
+// Count đếm tổng số articles theo filter
+func (r *memoryArticleRepository) Count(filter ArticleFilter) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.filter(filter)), nil
+}
+
+// Feed lấy articles từ các users mà currentUser đang follow, mới nhất trước
+func (r *memoryArticleRepository) Feed(currentUserID int, after *Cursor, limit, offset int) ([]*models.Article, error) {
+	return r.List(ArticleFilter{followedBy: currentUserID}, after, limit, offset)
+}
+
+// FeedCount đếm tổng số articles trong feed
+func (r *memoryArticleRepository) FeedCount(currentUserID int) (int, error) {
+	return r.Count(ArticleFilter{followedBy: currentUserID})
+}
+
+// Update cập nhật các field khác nil của article
+func (r *memoryArticleRepository) Update(articleID int, slug, title, description, body, bodyHTML *string) (*models.Article, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	article, ok := r.store.articles[articleID]
+	if !ok {
+		return nil, nil
+	}
+
+	if slug != nil {
+		for _, other := range r.store.articles {
+			if other.ID != articleID && other.Slug == *slug {
+				return nil, ErrDuplicateEntry
+			}
+		}
+		article.Slug = *slug
+	}
+	if title != nil {
+		article.Title = *title
+	}
+	if description != nil {
+		article.Description = *description
+	}
+	if body != nil {
+		article.Body = *body
+	}
+	if bodyHTML != nil {
+		article.BodyHTML = *bodyHTML
+	}
+	article.UpdatedAt = time.Now()
+
+	return copyArticle(article), nil
+}
+
+// SetMeta lưu thông tin suy ra từ body của article
+func (r *memoryArticleRepository) SetMeta(articleID int, meta models.ArticleMeta) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if article, ok := r.store.articles[articleID]; ok {
+		article.Meta = copyArticleMeta(&meta)
+	}
+	return nil
+}
+
+// SetStatus đổi status và published_at của article, trả về nil nếu article không tồn tại
+func (r *memoryArticleRepository) SetStatus(articleID int, status string, publishedAt *time.Time) (*models.Article, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	article, ok := r.store.articles[articleID]
+	if !ok {
+		return nil, nil
+	}
+	article.Status = status
+	article.PublishedAt = publishedAt
+	article.PublishAt = nil
+	article.UpdatedAt = time.Now()
+
+	return copyArticle(article), nil
+}
+
+// Schedule hẹn giờ publish article, trả về nil nếu article không tồn tại
+func (r *memoryArticleRepository) Schedule(articleID int, publishAt time.Time) (*models.Article, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	article, ok := r.store.articles[articleID]
+	if !ok {
+		return nil, nil
+	}
+	article.Status = models.ArticleStatusScheduled
+	article.PublishAt = &publishAt
+	article.UpdatedAt = time.Now()
+
+	return copyArticle(article), nil
+}
+
+// Hold chuyển article sang pending chờ moderators duyệt, giữ nguyên published_at và publish_at
+func (r *memoryArticleRepository) Hold(articleID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if article, ok := r.store.articles[articleID]; ok {
+		article.Status = models.ArticleStatusPending
+		article.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// PublishDue publish tối đa limit articles scheduled có publish_at <= now, trả về ID các articles đã publish
+func (r *memoryArticleRepository) PublishDue(now time.Time, limit int) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*models.Article
+	for _, article := range r.store.articles {
+		if article.Status == models.ArticleStatusScheduled && article.PublishAt != nil && !article.PublishAt.After(now) && !article.IsDeleted() {
+			due = append(due, article)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].PublishAt.Equal(*due[j].PublishAt) {
+			return due[i].PublishAt.Before(*due[j].PublishAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var ids []int
+	for _, article := range due {
+		article.Status = models.ArticleStatusPublished
+		if article.PublishedAt == nil {
+			article.PublishedAt = article.PublishAt
+		}
+		article.PublishAt = nil
+		article.UpdatedAt = now
+		ids = append(ids, article.ID)
+	}
+	return ids, nil
+}
+
+// Delete chuyển article vào thùng rác (xóa mềm), dữ liệu liên quan được giữ nguyên
+func (r *memoryArticleRepository) Delete(articleID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if article, ok := r.store.articles[articleID]; ok && !article.IsDeleted() {
+		now := time.Now()
+		article.DeletedAt = &now
+	}
+	return nil
+}
+
+// Restore đưa article ra khỏi thùng rác
+func (r *memoryArticleRepository) Restore(articleID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if article, ok := r.store.articles[articleID]; ok {
+		article.DeletedAt = nil
+	}
+	return nil
+}
+
+// GetDeletedBySlug lấy article trong thùng rác theo slug, trả về nil nếu không có
+func (r *memoryArticleRepository) GetDeletedBySlug(slug string) (*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, article := range r.store.articles {
+		if article.Slug == slug && article.IsDeleted() {
+			return copyArticle(article), nil
+		}
+	}
+	return nil, nil
+}
+
+// ListDeleted lấy các articles của author bị xóa sau deletedAfter, mới xóa trước
+func (r *memoryArticleRepository) ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var articles []*models.Article
+	for _, article := range r.store.articles {
+		if article.AuthorID == authorID && article.IsDeleted() && article.DeletedAt.After(deletedAfter) {
+			articles = append(articles, copyArticle(article))
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		if !articles[i].DeletedAt.Equal(*articles[j].DeletedAt) {
+			return articles[i].DeletedAt.After(*articles[j].DeletedAt)
+		}
+		return articles[i].ID > articles[j].ID
+	})
+	return articles, nil
+}
+
+// PurgeDeleted xóa hẳn tối đa limit articles bị xóa trước deletedBefore, trả về số articles đã xóa
+func (r *memoryArticleRepository) PurgeDeleted(deletedBefore time.Time, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []*models.Article
+	for _, article := range r.store.articles {
+		if article.IsDeleted() && !article.DeletedAt.After(deletedBefore) {
+			expired = append(expired, article)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Before(*expired[j].DeletedAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	for _, article := range expired {
+		r.purge(article.ID)
+	}
+	return len(expired), nil
+}
+
+// purge xóa hẳn article cùng comments, favorites, article_tags, lịch sử slug và revisions
+// (giống ON DELETE CASCADE). Caller phải giữ lock.
+func (r *memoryArticleRepository) purge(articleID int) {
+	delete(r.store.articles, articleID)
+	delete(r.store.articleTags, articleID)
+	for id, comment := range r.store.comments {
+		if comment.ArticleID == articleID {
+			r.store.deleteComment(id)
+		}
+	}
+	for key := range r.store.favorites {
+		if key.articleID == articleID {
+			delete(r.store.favorites, key)
+		}
+	}
+	for slug, id := range r.store.slugHistory {
+		if id == articleID {
+			delete(r.store.slugHistory, slug)
+		}
+	}
+	for id, revision := range r.store.revisions {
+		if revision.ArticleID == articleID {
+			delete(r.store.revisions, id)
+		}
+	}
+}
+
+// IsSlugExists kiểm tra xem slug đã tồn tại chưa, kể cả slug cũ trong lịch sử
+func (r *memoryArticleRepository) IsSlugExists(slug string) (bool, error) {
+	article, err := r.GetBySlug(slug)
+	if err != nil || article != nil {
+		return article != nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	_, ok := r.store.slugHistory[slug]
+	return ok, nil
+}
+
+// AddSlugHistory lưu slug cũ của article, ghi đè nếu slug đã có trong lịch sử
+func (r *memoryArticleRepository) AddSlugHistory(articleID int, slug string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.slugHistory[slug] = articleID
+	return nil
+}
+
+// DeleteSlugHistory xóa slug khỏi lịch sử
+func (r *memoryArticleRepository) DeleteSlugHistory(slug string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.slugHistory, slug)
+	return nil
+}
+
+// GetBySlugHistory lấy article hiện tại từ một slug cũ
+func (r *memoryArticleRepository) GetBySlugHistory(slug string) (*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	articleID, ok := r.store.slugHistory[slug]
+	if !ok {
+		return nil, nil
+	}
+	article, ok := r.store.articles[articleID]
+	if !ok || article.IsDeleted() {
+		return nil, nil
+	}
+	return copyArticle(article), nil
+}
+
+// Favorite thêm article vào favorites của user, bỏ qua nếu đã favorite
+func (r *memoryArticleRepository) Favorite(userID, articleID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: userID, articleID: articleID}
+	if _, ok := r.store.favorites[key]; ok {
+		return nil
+	}
+	r.store.favorites[key] = time.Now()
+	if article, ok := r.store.articles[articleID]; ok {
+		article.FavoritesCount++
+	}
+	return nil
+}
+
+// Unfavorite xóa article khỏi favorites của user
+func (r *memoryArticleRepository) Unfavorite(userID, articleID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: userID, articleID: articleID}
+	if _, ok := r.store.favorites[key]; !ok {
+		return nil
+	}
+	delete(r.store.favorites, key)
+	if article, ok := r.store.articles[articleID]; ok {
+		article.FavoritesCount--
+	}
+	return nil
+}
+
+// IsFavorited kiểm tra xem user đã favorite article chưa
+func (r *memoryArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.favorites[favoriteKey{userID: userID, articleID: articleID}]
+	return ok, nil
+}
+
+// GetTagsByArticleID lấy danh sách tags của article, sắp xếp theo tên
+func (r *memoryArticleRepository) GetTagsByArticleID(articleID int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tags []string
+	for tagID := range r.store.articleTags[articleID] {
+		if tag, ok := r.store.tags[tagID]; ok {
+			tags = append(tags, tag.Name)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// GetTagsByArticleIDs lấy tags của nhiều articles, trả về map article_id -> tags
+func (r *memoryArticleRepository) GetTagsByArticleIDs(articleIDs []int) (map[int][]string, error) {
+	tagsByArticle := map[int][]string{}
+	for _, articleID := range articleIDs {
+		tags, err := r.GetTagsByArticleID(articleID)
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) > 0 {
+			tagsByArticle[articleID] = tags
+		}
+	}
+	return tagsByArticle, nil
+}
+
+// GetFavoritedArticleIDs trả về set các article trong articleIDs mà user đã favorite
+func (r *memoryArticleRepository) GetFavoritedArticleIDs(userID int, articleIDs []int) (map[int]bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	favorited := map[int]bool{}
+	for _, articleID := range articleIDs {
+		if _, ok := r.store.favorites[favoriteKey{userID: userID, articleID: articleID}]; ok {
+			favorited[articleID] = true
+		}
+	}
+	return favorited, nil
+}
+
+// GetCommentsCounts đếm comments chưa xóa, không bị ẩn của từng article trong articleIDs
+func (r *memoryArticleRepository) GetCommentsCounts(articleIDs []int) (map[int]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		wanted[articleID] = true
+	}
+	counts := map[int]int{}
+	for _, comment := range r.store.comments {
+		if wanted[comment.ArticleID] && comment.DeletedAt == nil && comment.HiddenAt == nil {
+			counts[comment.ArticleID]++
+		}
+	}
+	return counts, nil
+}
+
+// HasRecentBody kiểm tra author đã lưu article chưa xóa có đúng body này từ since trở lại đây chưa
+// excludeArticleID là article đang sửa (0 khi tạo mới), không tính là trùng với chính nó.
+func (r *memoryArticleRepository) HasRecentBody(authorID int, body string, since time.Time, excludeArticleID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, article := range r.store.articles {
+		if article.AuthorID == authorID && article.Body == body && !article.UpdatedAt.Before(since) &&
+			article.ID != excludeArticleID && article.DeletedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetReviewReason ghi lý do cần moderators xem xét article, reason nil là đã xem xét xong
+func (r *memoryArticleRepository) SetReviewReason(articleID int, reason *string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if article, ok := r.store.articles[articleID]; ok {
+		if reason != nil {
+			value := *reason
+			article.ReviewReason = &value
+		} else {
+			article.ReviewReason = nil
+		}
+	}
+	return nil
+}
+
+// forReview trả về các articles chưa xóa đang chờ moderators xem xét, sửa sớm nhất trước. Caller phải giữ lock.
+func (r *memoryArticleRepository) forReview() []*models.Article {
+	var articles []*models.Article
+	for _, article := range r.store.articles {
+		if article.ReviewReason != nil && article.DeletedAt == nil {
+			articles = append(articles, article)
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		if !articles[i].UpdatedAt.Equal(articles[j].UpdatedAt) {
+			return articles[i].UpdatedAt.Before(articles[j].UpdatedAt)
+		}
+		return articles[i].ID < articles[j].ID
+	})
+	return articles
+}
+
+// ListForReview lấy các articles chưa xóa đang chờ moderators xem xét, sửa sớm nhất trước
+func (r *memoryArticleRepository) ListForReview(limit, offset int) ([]*models.Article, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	articles := r.forReview()
+	if offset >= len(articles) {
+		return nil, nil
+	}
+	articles = articles[offset:]
+	if len(articles) > limit {
+		articles = articles[:limit]
+	}
+	result := make([]*models.Article, 0, len(articles))
+	for _, article := range articles {
+		result = append(result, copyArticle(article))
+	}
+	return result, nil
+}
+
+// CountForReview đếm số articles chưa xóa đang chờ moderators xem xét
+func (r *memoryArticleRepository) CountForReview() (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.forReview()), nil
+}
+
+// filter trả về các articles đã publish thỏa filter, sắp xếp theo filter.Sort. Caller phải giữ lock.
+// Cùng quy tắc với newArticleQuery của MySQL backend.
+func (r *memoryArticleRepository) filter(filter ArticleFilter) []*models.Article {
+	tagIDs := r.store.tagIDsByName(filter.Tags)
+	if len(filter.Tags) > 0 {
+		// Mode all cần đủ mọi tag nên chỉ cần một tag không tồn tại là không có kết quả
+		if len(tagIDs) == 0 || (filter.TagMode == TagMatchAll && len(tagIDs) < len(uniqueStrings(filter.Tags))) {
+			return nil
+		}
+	}
+	excludeTagIDs := r.store.tagIDsByName(filter.ExcludeTags)
+
+	authorIDs := map[int]bool{}
+	for _, username := range filter.Authors {
+		if user := r.store.userByUsername(username); user != nil {
+			authorIDs[user.ID] = true
+		}
+	}
+	if len(filter.Authors) > 0 && len(authorIDs) == 0 {
+		return nil
+	}
+
+	var favoritedUserID int
+	if filter.Favorited != "" {
+		user := r.store.userByUsername(filter.Favorited)
+		if user == nil {
+			return nil
+		}
+		favoritedUserID = user.ID
+	}
+
+	var result []*models.Article
+	for _, article := range r.store.articles {
+		if !article.IsPublished() || article.IsDeleted() {
+			continue
+		}
+		articleTags := r.store.articleTags[article.ID]
+		if len(tagIDs) > 0 && !hasTags(articleTags, tagIDs, filter.TagMode == TagMatchAll) {
+			continue
+		}
+		if len(excludeTagIDs) > 0 && hasTags(articleTags, excludeTagIDs, false) {
+			continue
+		}
+		if len(authorIDs) > 0 && !authorIDs[article.AuthorID] {
+			continue
+		}
+		if favoritedUserID != 0 {
+			if _, ok := r.store.favorites[favoriteKey{userID: favoritedUserID, articleID: article.ID}]; !ok {
+				continue
+			}
+		}
+		if filter.CreatedAfter != nil && article.CreatedAt.Before(*filter.CreatedAfter) {
+			continue
+		}
+		if filter.CreatedBefore != nil && !article.CreatedAt.Before(*filter.CreatedBefore) {
+			continue
+		}
+		if filter.followedBy != 0 {
+			if _, ok := r.store.follows[followKey{followerID: filter.followedBy, followingID: article.AuthorID}]; !ok {
+				continue
+			}
+		}
+		result = append(result, article)
+	}
+
+	spec := filter.Sort.sortSpec()
+	sort.Slice(result, func(i, j int) bool {
+		return spec.less(result[i], result[j])
+	})
+	return result
+}
+
+// hasTags kiểm tra article có một (all = false) hoặc tất cả (all = true) tags trong tagIDs
+func hasTags(articleTags map[int]bool, tagIDs []int, all bool) bool {
+	for _, tagID := range tagIDs {
+		if all && !articleTags[tagID] {
+			return false
+		}
+		if !all && articleTags[tagID] {
+			return true
+		}
+	}
+	return all
+}
+
+// afterCursor bỏ các articles đứng trước hoặc tại cursor, articles phải đã sắp xếp theo order
+func afterCursor(articles []*models.Article, after *Cursor, order ArticleSort) []*models.Article {
+	if after == nil {
+		return articles
+	}
+	spec := order.sortSpec()
+	for i, article := range articles {
+		if spec.follows(article, after) {
+			return articles[i:]
+		}
+	}
+	return nil
+}
+
+// paginate áp dụng LIMIT/OFFSET và copy kết quả
+func paginate(articles []*models.Article, limit, offset int) []*models.Article {
+	if offset >= len(articles) {
+		return nil
+	}
+	end := offset + limit
+	if end > len(articles) {
+		end = len(articles)
+	}
+
+	result := make([]*models.Article, 0, end-offset)
+	for _, article := range articles[offset:end] {
+		result = append(result, copyArticle(article))
+	}
+	return result
+}
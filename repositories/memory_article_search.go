@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"math"
+	"news/models"
+	"news/utils"
+	"sort"
+)
+
+// searchFieldWeights là trọng số của từng field khi tính relevance trong in-memory search
+// Title được ưu tiên giống MySQL backend (MATCH(title) * 2 + MATCH(title, description, body)).
+var searchFieldWeights = struct {
+	title, description, body float64
+}{title: 3, description: 1, body: 1}
+
+// Search tìm articles theo title, description, body
+// Text được tách từ và bỏ dấu bằng utils.Tokenize; điểm là tổng TF-IDF có trọng số theo field.
+func (r *memoryArticleRepository) Search(query string, filter ArticleFilter, limit, offset int) ([]*ArticleSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	results := r.search(query, filter)
+	if offset >= len(results) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	page := make([]*ArticleSearchResult, 0, end-offset)
+	for _, result := range results[offset:end] {
+		page = append(page, &ArticleSearchResult{Article: copyArticle(result.Article), Score: result.Score})
+	}
+	return page, nil
+}
+
+// SearchCount đếm tổng số articles khớp từ khóa và filter
+func (r *memoryArticleRepository) SearchCount(query string, filter ArticleFilter) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.search(query, filter)), nil
+}
+
+// search chấm điểm các articles thỏa filter và trả về các article có điểm > 0,
+// sắp xếp theo điểm giảm dần rồi mới nhất trước. Caller phải giữ lock.
+func (r *memoryArticleRepository) search(query string, filter ArticleFilter) []*ArticleSearchResult {
+	terms := uniqueTerms(utils.Tokenize(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type termFrequencies struct {
+		article                  *models.Article
+		title, description, body map[string]int
+	}
+
+	// Đếm tần suất từ trong từng field và số article chứa mỗi term (document frequency)
+	filter.Sort = SortRecent
+	candidates := r.filter(filter)
+	docs := make([]termFrequencies, 0, len(candidates))
+	docFreq := map[string]int{}
+	for _, article := range candidates {
+		doc := termFrequencies{
+			article:     article,
+			title:       countTerms(article.Title, terms),
+			description: countTerms(article.Description, terms),
+			body:        countTerms(article.Body, terms),
+		}
+		for term := range terms {
+			if doc.title[term]+doc.description[term]+doc.body[term] > 0 {
+				docFreq[term]++
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	var results []*ArticleSearchResult
+	for _, doc := range docs {
+		score := 0.0
+		for term := range terms {
+			if docFreq[term] == 0 {
+				continue
+			}
+			idf := math.Log(1 + float64(len(docs))/float64(docFreq[term]))
+			tf := searchFieldWeights.title*float64(doc.title[term]) +
+				searchFieldWeights.description*float64(doc.description[term]) +
+				searchFieldWeights.body*float64(doc.body[term])
+			score += tf * idf
+		}
+		if score > 0 {
+			results = append(results, &ArticleSearchResult{Article: doc.article, Score: score})
+		}
+	}
+
+	// filter đã sắp xếp mới nhất trước, SliceStable giữ thứ tự đó khi cùng điểm
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// uniqueTerms chuyển danh sách token thành set
+func uniqueTerms(tokens []string) map[string]bool {
+	terms := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		terms[token] = true
+	}
+	return terms
+}
+
+// countTerms đếm số lần mỗi term xuất hiện trong text
+func countTerms(text string, terms map[string]bool) map[string]int {
+	counts := map[string]int{}
+	for _, token := range utils.Tokenize(text) {
+		if terms[token] {
+			counts[token]++
+		}
+	}
+	return counts
+}
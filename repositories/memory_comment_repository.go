@@ -0,0 +1,466 @@
+package repositories
+
+import (
+	"news/models"
+	"sort"
+	"time"
+)
+
+// memoryCommentRepository implement CommentRepository bằng MemoryStore
+type memoryCommentRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCommentRepository tạo CommentRepository lưu dữ liệu trong store
+func NewMemoryCommentRepository(store *MemoryStore) CommentRepository {
+	return &memoryCommentRepository{store: store}
+}
+
+// Create tạo comment mới, parent khác nil thì comment là reply của parent
+func (r *memoryCommentRepository) Create(articleID, authorID int, parent *models.Comment, body, bodyHTML string) (*models.Comment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	comment := &models.Comment{
+		ID:        r.store.nextCommentID,
+		ArticleID: articleID,
+		AuthorID:  authorID,
+		Body:      body,
+		BodyHTML:  bodyHTML,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if parent != nil {
+		parentID := parent.ID
+		comment.ParentID = &parentID
+		comment.Depth = parent.Depth + 1
+	}
+	r.store.nextCommentID++
+	r.store.comments[comment.ID] = comment
+
+	return copyComment(comment), nil
+}
+
+// GetByID lấy comment theo ID, trả về nil nếu không tồn tại hoặc đã bị xóa
+func (r *memoryCommentRepository) GetByID(id int) (*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, ok := r.store.comments[id]
+	if !ok || comment.DeletedAt != nil {
+		return nil, nil
+	}
+	return r.copyWithRepliesCount(comment), nil
+}
+
+// GetByArticleID lấy comments của một article kèm số replies, sắp xếp theo order
+// Comment đã xóa hoặc bị ẩn vẫn được lấy nếu còn reply đang hiển thị ở bất kỳ cấp nào bên dưới
+// (placeholder giữ thread). after khác nil thì chỉ lấy comments đứng sau cursor; limit <= 0 là lấy tất cả
+func (r *memoryCommentRepository) GetByArticleID(articleID int, order CommentSort, after *Cursor, limit int) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	visible, repliesCount := r.visibleComments(articleID)
+
+	spec := order.sortSpec()
+	var comments []*models.Comment
+	for id := range visible {
+		c := copyComment(r.store.comments[id])
+		c.RepliesCount = repliesCount[id]
+		if after != nil && !spec.follows(c, after) {
+			continue
+		}
+		comments = append(comments, c)
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return spec.less(comments[i], comments[j])
+	})
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
+
+// visibleComments trả về các comments của article nằm trong danh sách comments (chưa xóa, chưa bị ẩn
+// cùng mọi tổ tiên của chúng) và số replies trực tiếp đang hiển thị của từng comment. Caller phải giữ lock.
+func (r *memoryCommentRepository) visibleComments(articleID int) (map[int]bool, map[int]int) {
+	visible := map[int]bool{}
+	for _, comment := range r.store.comments {
+		if comment.ArticleID != articleID || comment.DeletedAt != nil || comment.HiddenAt != nil {
+			continue
+		}
+		for c := comment; c != nil && !visible[c.ID]; {
+			visible[c.ID] = true
+			if c.ParentID == nil {
+				break
+			}
+			c = r.store.comments[*c.ParentID]
+		}
+	}
+	repliesCount := map[int]int{}
+	for id := range visible {
+		if parentID := r.store.comments[id].ParentID; parentID != nil {
+			repliesCount[*parentID]++
+		}
+	}
+	return visible, repliesCount
+}
+
+// copyWithRepliesCount trả về bản copy của comment kèm số replies đang hiển thị như MySQL lưu trên dòng comment
+// Caller phải giữ lock.
+func (r *memoryCommentRepository) copyWithRepliesCount(comment *models.Comment) *models.Comment {
+	_, repliesCount := r.visibleComments(comment.ArticleID)
+	c := copyComment(comment)
+	c.RepliesCount = repliesCount[comment.ID]
+	return c
+}
+
+// Update sửa body của comment và đánh dấu đã sửa lúc editedAt
+func (r *memoryCommentRepository) Update(commentID int, body, bodyHTML string, editedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[commentID]; ok {
+		comment.Body = body
+		comment.BodyHTML = bodyHTML
+		comment.EditedAt = &editedAt
+		comment.UpdatedAt = editedAt
+	}
+	return nil
+}
+
+// CreateEdit lưu body trước khi sửa của comment vào lịch sử chỉnh sửa
+func (r *memoryCommentRepository) CreateEdit(commentID int, body, bodyHTML string, editedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	edit := &models.CommentEdit{
+		ID:        r.store.nextCommentEditID,
+		CommentID: commentID,
+		Body:      body,
+		BodyHTML:  bodyHTML,
+		CreatedAt: editedAt,
+	}
+	r.store.nextCommentEditID++
+	r.store.commentEdits[edit.ID] = edit
+	return nil
+}
+
+// ListEdits lấy lịch sử chỉnh sửa của comment, lần sửa mới nhất trước
+func (r *memoryCommentRepository) ListEdits(commentID int) ([]*models.CommentEdit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var edits []*models.CommentEdit
+	for _, edit := range r.store.commentEdits {
+		if edit.CommentID == commentID {
+			e := *edit
+			edits = append(edits, &e)
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		if !edits[i].CreatedAt.Equal(edits[j].CreatedAt) {
+			return edits[i].CreatedAt.After(edits[j].CreatedAt)
+		}
+		return edits[i].ID > edits[j].ID
+	})
+	return edits, nil
+}
+
+// Delete chuyển comment vào thùng rác (xóa mềm)
+func (r *memoryCommentRepository) Delete(commentID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[commentID]; ok && comment.DeletedAt == nil {
+		now := time.Now()
+		comment.DeletedAt = &now
+	}
+	return nil
+}
+
+// Restore đưa comment ra khỏi thùng rác
+func (r *memoryCommentRepository) Restore(commentID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[commentID]; ok {
+		comment.DeletedAt = nil
+	}
+	return nil
+}
+
+// GetDeletedByID lấy comment trong thùng rác theo ID, trả về nil nếu không có
+func (r *memoryCommentRepository) GetDeletedByID(id int) (*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comment, ok := r.store.comments[id]
+	if !ok || comment.DeletedAt == nil {
+		return nil, nil
+	}
+	return r.copyWithRepliesCount(comment), nil
+}
+
+// ListDeleted lấy các comments của author bị xóa sau deletedAfter, mới xóa trước
+func (r *memoryCommentRepository) ListDeleted(authorID int, deletedAfter time.Time) ([]*models.Comment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var comments []*models.Comment
+	for _, comment := range r.store.comments {
+		if comment.AuthorID == authorID && comment.DeletedAt != nil && comment.DeletedAt.After(deletedAfter) {
+			comments = append(comments, r.copyWithRepliesCount(comment))
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].DeletedAt.Equal(*comments[j].DeletedAt) {
+			return comments[i].DeletedAt.After(*comments[j].DeletedAt)
+		}
+		return comments[i].ID > comments[j].ID
+	})
+	return comments, nil
+}
+
+// PurgeDeleted xóa hẳn tối đa limit comments bị xóa trước deletedBefore, trả về số comments đã xóa
+// Comment còn replies (kể cả replies trong thùng rác) được giữ lại tới khi mọi replies bị xóa hẳn.
+func (r *memoryCommentRepository) PurgeDeleted(deletedBefore time.Time, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	parents := map[int]bool{}
+	for _, comment := range r.store.comments {
+		if comment.ParentID != nil {
+			parents[*comment.ParentID] = true
+		}
+	}
+
+	var expired []*models.Comment
+	for _, comment := range r.store.comments {
+		if comment.DeletedAt != nil && !comment.DeletedAt.After(deletedBefore) && !parents[comment.ID] {
+			expired = append(expired, comment)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Before(*expired[j].DeletedAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	for _, comment := range expired {
+		r.store.deleteComment(comment.ID)
+	}
+	return len(expired), nil
+}
+
+// Hide ẩn comment khỏi danh sách comments của article từ hiddenAt, comment đã bị ẩn thì giữ nguyên
+func (r *memoryCommentRepository) Hide(commentID int, hiddenAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[commentID]; ok && comment.HiddenAt == nil {
+		comment.HiddenAt = &hiddenAt
+	}
+	return nil
+}
+
+// Unhide hiển thị lại comment bị ẩn
+func (r *memoryCommentRepository) Unhide(commentID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if comment, ok := r.store.comments[commentID]; ok {
+		comment.HiddenAt = nil
+	}
+	return nil
+}
+
+// HasRecentBody kiểm tra author đã đăng comment chưa xóa có đúng body này từ since trở lại đây chưa
+// excludeCommentID là comment đang sửa (0 khi tạo mới), không tính là trùng với chính nó.
+func (r *memoryCommentRepository) HasRecentBody(authorID int, body string, since time.Time, excludeCommentID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, comment := range r.store.comments {
+		if comment.AuthorID == authorID && comment.Body == body && !comment.CreatedAt.Before(since) &&
+			comment.ID != excludeCommentID && comment.DeletedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateReport lưu report của reporterID về comment, reporter đã report comment này rồi thì bỏ qua
+// reporterID nil là report do content filter tạo.
+func (r *memoryCommentRepository) CreateReport(commentID int, reporterID *int, reason string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	report := &models.CommentReport{
+		ID:        r.store.nextCommentReportID,
+		CommentID: commentID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if reporterID != nil {
+		for _, existing := range r.store.commentReports {
+			if existing.CommentID == commentID && existing.ReporterID != nil && *existing.ReporterID == *reporterID {
+				return nil
+			}
+		}
+		id := *reporterID
+		report.ReporterID = &id
+	}
+	r.store.nextCommentReportID++
+	r.store.commentReports[report.ID] = report
+	return nil
+}
+
+// reported trả về các comments chưa xóa trên articles chưa xóa còn reports chưa xử lý,
+// report sớm nhất trước. Caller phải giữ lock.
+func (r *memoryCommentRepository) reported() []*models.ReportedComment {
+	byComment := map[int]*models.ReportedComment{}
+	for _, report := range r.store.commentReports {
+		if report.ResolvedAt != nil {
+			continue
+		}
+		item, ok := byComment[report.CommentID]
+		if !ok {
+			comment := r.store.comments[report.CommentID]
+			if comment == nil || comment.DeletedAt != nil {
+				continue
+			}
+			article := r.store.articles[comment.ArticleID]
+			if article == nil || article.DeletedAt != nil {
+				continue
+			}
+			item = &models.ReportedComment{Comment: *r.copyWithRepliesCount(comment), ArticleSlug: article.Slug, FirstReportedAt: report.CreatedAt}
+			byComment[report.CommentID] = item
+		}
+		item.ReportsCount++
+		if report.CreatedAt.Before(item.FirstReportedAt) {
+			item.FirstReportedAt = report.CreatedAt
+		}
+	}
+
+	reported := make([]*models.ReportedComment, 0, len(byComment))
+	for _, item := range byComment {
+		reported = append(reported, item)
+	}
+	sort.Slice(reported, func(i, j int) bool {
+		if !reported[i].FirstReportedAt.Equal(reported[j].FirstReportedAt) {
+			return reported[i].FirstReportedAt.Before(reported[j].FirstReportedAt)
+		}
+		return reported[i].ID < reported[j].ID
+	})
+	return reported
+}
+
+// ListReported lấy các comments còn reports chưa xử lý, report sớm nhất trước
+func (r *memoryCommentRepository) ListReported(limit, offset int) ([]*models.ReportedComment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reported := r.reported()
+	if offset >= len(reported) {
+		return nil, nil
+	}
+	reported = reported[offset:]
+	if len(reported) > limit {
+		reported = reported[:limit]
+	}
+	return reported, nil
+}
+
+// CountReported đếm số comments trong hàng đợi kiểm duyệt
+func (r *memoryCommentRepository) CountReported() (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.reported()), nil
+}
+
+// GetOpenReports lấy reports chưa xử lý của các comments, report sớm nhất trước
+func (r *memoryCommentRepository) GetOpenReports(commentIDs []int) (map[int][]*models.CommentReport, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(commentIDs))
+	for _, commentID := range commentIDs {
+		wanted[commentID] = true
+	}
+	reports := map[int][]*models.CommentReport{}
+	for _, report := range r.store.commentReports {
+		if wanted[report.CommentID] && report.ResolvedAt == nil {
+			reports[report.CommentID] = append(reports[report.CommentID], copyCommentReport(report))
+		}
+	}
+	for _, list := range reports {
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].CreatedAt.Before(list[j].CreatedAt)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	return reports, nil
+}
+
+// ResolveReports đánh dấu mọi reports chưa xử lý của comment là đã xử lý lúc resolvedAt
+func (r *memoryCommentRepository) ResolveReports(commentID int, resolvedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, report := range r.store.commentReports {
+		if report.CommentID == commentID && report.ResolvedAt == nil {
+			at := resolvedAt
+			report.ResolvedAt = &at
+		}
+	}
+	return nil
+}
+
+// CreateAction ghi lại hành động kiểm duyệt action của actorID với comment lúc createdAt
+func (r *memoryCommentRepository) CreateAction(commentID, actorID int, action string, createdAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record := &models.CommentModerationAction{
+		ID:        r.store.nextCommentActionID,
+		CommentID: commentID,
+		ActorID:   actorID,
+		Action:    action,
+		CreatedAt: createdAt,
+	}
+	r.store.nextCommentActionID++
+	r.store.commentActions[record.ID] = record
+	return nil
+}
+
+// GetActions lấy lịch sử kiểm duyệt của các comments, hành động mới nhất trước
+func (r *memoryCommentRepository) GetActions(commentIDs []int) (map[int][]*models.CommentModerationAction, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(commentIDs))
+	for _, commentID := range commentIDs {
+		wanted[commentID] = true
+	}
+	actions := map[int][]*models.CommentModerationAction{}
+	for _, action := range r.store.commentActions {
+		if wanted[action.CommentID] {
+			a := *action
+			actions[action.CommentID] = append(actions[action.CommentID], &a)
+		}
+	}
+	for _, list := range actions {
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].CreatedAt.After(list[j].CreatedAt)
+			}
+			return list[i].ID > list[j].ID
+		})
+	}
+	return actions, nil
+}
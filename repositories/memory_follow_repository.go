@@ -0,0 +1,58 @@
+package repositories
+
+import "time"
+
+// memoryFollowRepository implement FollowRepository bằng MemoryStore
+type memoryFollowRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryFollowRepository tạo FollowRepository lưu dữ liệu trong store
+func NewMemoryFollowRepository(store *MemoryStore) FollowRepository {
+	return &memoryFollowRepository{store: store}
+}
+
+// Follow tạo relationship follow, trả về ErrDuplicateEntry nếu đã follow (giống primary key MySQL)
+func (r *memoryFollowRepository) Follow(followerID, followingID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := followKey{followerID: followerID, followingID: followingID}
+	if _, ok := r.store.follows[key]; ok {
+		return ErrDuplicateEntry
+	}
+	r.store.follows[key] = time.Now()
+	return nil
+}
+
+// Unfollow xóa relationship follow
+func (r *memoryFollowRepository) Unfollow(followerID, followingID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.follows, followKey{followerID: followerID, followingID: followingID})
+	return nil
+}
+
+// IsFollowing kiểm tra xem follower có đang follow following không
+func (r *memoryFollowRepository) IsFollowing(followerID, followingID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.follows[followKey{followerID: followerID, followingID: followingID}]
+	return ok, nil
+}
+
+// GetFollowingIDs trả về set các user trong followingIDs mà follower đang follow
+func (r *memoryFollowRepository) GetFollowingIDs(followerID int, followingIDs []int) (map[int]bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	following := map[int]bool{}
+	for _, followingID := range followingIDs {
+		if _, ok := r.store.follows[followKey{followerID: followerID, followingID: followingID}]; ok {
+			following[followingID] = true
+		}
+	}
+	return following, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"news/models"
+	"sort"
+	"time"
+)
+
+// memoryRevisionRepository implement RevisionRepository bằng MemoryStore
+type memoryRevisionRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryRevisionRepository tạo RevisionRepository lưu dữ liệu trong store
+func NewMemoryRevisionRepository(store *MemoryStore) RevisionRepository {
+	return &memoryRevisionRepository{store: store}
+}
+
+// Create lưu revision mới cho article với number kế tiếp
+func (r *memoryRevisionRepository) Create(articleID, editorID int, title, description, body, bodyHTML string) (*models.ArticleRevision, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	number := 1
+	for _, revision := range r.store.revisions {
+		if revision.ArticleID == articleID && revision.Number >= number {
+			number = revision.Number + 1
+		}
+	}
+
+	revision := &models.ArticleRevision{
+		ID:          r.store.nextRevisionID,
+		ArticleID:   articleID,
+		Number:      number,
+		Title:       title,
+		Description: description,
+		Body:        body,
+		BodyHTML:    bodyHTML,
+		EditorID:    editorID,
+		CreatedAt:   time.Now(),
+	}
+	r.store.nextRevisionID++
+	r.store.revisions[revision.ID] = revision
+
+	return copyRevision(revision), nil
+}
+
+// GetByNumber lấy revision number của article, trả về nil nếu không tồn tại
+func (r *memoryRevisionRepository) GetByNumber(articleID, number int) (*models.ArticleRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, revision := range r.store.revisions {
+		if revision.ArticleID == articleID && revision.Number == number {
+			return copyRevision(revision), nil
+		}
+	}
+	return nil, nil
+}
+
+// ListByArticleID lấy tất cả revisions của article, mới nhất trước
+func (r *memoryRevisionRepository) ListByArticleID(articleID int) ([]*models.ArticleRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var revisions []*models.ArticleRevision
+	for _, revision := range r.store.revisions {
+		if revision.ArticleID == articleID {
+			revisions = append(revisions, copyRevision(revision))
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+	return revisions, nil
+}
@@ -0,0 +1,363 @@
+package repositories
+
+import (
+	"errors"
+	"news/models"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateEntry được trả về bởi in-memory backend khi vi phạm unique constraint
+// (tương đương lỗi 1062 Duplicate entry của MySQL)
+var ErrDuplicateEntry = errors.New("duplicate entry")
+
+// favoriteKey là primary key của bảng favorites
+type favoriteKey struct {
+	userID    int
+	articleID int
+}
+
+// followKey là primary key của bảng follows
+type followKey struct {
+	followerID  int
+	followingID int
+}
+
+// MemoryStore lưu toàn bộ dữ liệu trong bộ nhớ, thay thế cho MySQL
+// Dùng cho unit test và chạy demo local không cần database.
+// Tất cả các memory repository tạo từ cùng một store sẽ chia sẻ dữ liệu với nhau.
+type MemoryStore struct {
+	mu sync.RWMutex
+	// txMu serialize các transaction của memoryUnitOfWork
+	txMu sync.Mutex
+
+	users        map[int]*models.User
+	articles     map[int]*models.Article
+	comments     map[int]*models.Comment
+	tags         map[int]*models.Tag
+	articleTags  map[int]map[int]bool // article_id -> set tag_id
+	favorites    map[favoriteKey]time.Time
+	follows      map[followKey]time.Time
+	slugHistory  map[string]int // slug cũ -> article_id
+	revisions    map[int]*models.ArticleRevision
+	tagAliases   map[string]models.TagAlias // alias -> TagAlias (không lưu TagName)
+	commentEdits map[int]*models.CommentEdit
+	// commentReports và commentActions là reports và lịch sử kiểm duyệt của comments
+	commentReports map[int]*models.CommentReport
+	commentActions map[int]*models.CommentModerationAction
+
+	nextUserID          int
+	nextArticleID       int
+	nextCommentID       int
+	nextTagID           int
+	nextRevisionID      int
+	nextCommentEditID   int
+	nextCommentReportID int
+	nextCommentActionID int
+}
+
+// NewMemoryStore tạo MemoryStore rỗng
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:               map[int]*models.User{},
+		articles:            map[int]*models.Article{},
+		comments:            map[int]*models.Comment{},
+		tags:                map[int]*models.Tag{},
+		articleTags:         map[int]map[int]bool{},
+		favorites:           map[favoriteKey]time.Time{},
+		follows:             map[followKey]time.Time{},
+		slugHistory:         map[string]int{},
+		revisions:           map[int]*models.ArticleRevision{},
+		tagAliases:          map[string]models.TagAlias{},
+		commentEdits:        map[int]*models.CommentEdit{},
+		commentReports:      map[int]*models.CommentReport{},
+		commentActions:      map[int]*models.CommentModerationAction{},
+		nextUserID:          1,
+		nextArticleID:       1,
+		nextCommentID:       1,
+		nextTagID:           1,
+		nextRevisionID:      1,
+		nextCommentEditID:   1,
+		nextCommentReportID: 1,
+		nextCommentActionID: 1,
+	}
+}
+
+// memorySnapshot là bản sao toàn bộ dữ liệu của MemoryStore, dùng để rollback transaction
+type memorySnapshot struct {
+	users          map[int]*models.User
+	articles       map[int]*models.Article
+	comments       map[int]*models.Comment
+	tags           map[int]*models.Tag
+	articleTags    map[int]map[int]bool
+	favorites      map[favoriteKey]time.Time
+	follows        map[followKey]time.Time
+	slugHistory    map[string]int
+	revisions      map[int]*models.ArticleRevision
+	tagAliases     map[string]models.TagAlias
+	commentEdits   map[int]*models.CommentEdit
+	commentReports map[int]*models.CommentReport
+	commentActions map[int]*models.CommentModerationAction
+
+	nextUserID          int
+	nextArticleID       int
+	nextCommentID       int
+	nextTagID           int
+	nextRevisionID      int
+	nextCommentEditID   int
+	nextCommentReportID int
+	nextCommentActionID int
+}
+
+// snapshot chụp lại toàn bộ dữ liệu hiện tại của store
+func (s *MemoryStore) snapshot() *memorySnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &memorySnapshot{
+		users:               make(map[int]*models.User, len(s.users)),
+		articles:            make(map[int]*models.Article, len(s.articles)),
+		comments:            make(map[int]*models.Comment, len(s.comments)),
+		tags:                make(map[int]*models.Tag, len(s.tags)),
+		articleTags:         make(map[int]map[int]bool, len(s.articleTags)),
+		favorites:           make(map[favoriteKey]time.Time, len(s.favorites)),
+		follows:             make(map[followKey]time.Time, len(s.follows)),
+		slugHistory:         make(map[string]int, len(s.slugHistory)),
+		revisions:           make(map[int]*models.ArticleRevision, len(s.revisions)),
+		tagAliases:          make(map[string]models.TagAlias, len(s.tagAliases)),
+		commentEdits:        make(map[int]*models.CommentEdit, len(s.commentEdits)),
+		commentReports:      make(map[int]*models.CommentReport, len(s.commentReports)),
+		commentActions:      make(map[int]*models.CommentModerationAction, len(s.commentActions)),
+		nextUserID:          s.nextUserID,
+		nextArticleID:       s.nextArticleID,
+		nextCommentID:       s.nextCommentID,
+		nextTagID:           s.nextTagID,
+		nextRevisionID:      s.nextRevisionID,
+		nextCommentEditID:   s.nextCommentEditID,
+		nextCommentReportID: s.nextCommentReportID,
+		nextCommentActionID: s.nextCommentActionID,
+	}
+	for id, user := range s.users {
+		snap.users[id] = copyUser(user)
+	}
+	for id, article := range s.articles {
+		snap.articles[id] = copyArticle(article)
+	}
+	for id, comment := range s.comments {
+		snap.comments[id] = copyComment(comment)
+	}
+	for id, tag := range s.tags {
+		snap.tags[id] = &models.Tag{ID: tag.ID, Name: tag.Name}
+	}
+	for articleID, tagIDs := range s.articleTags {
+		set := make(map[int]bool, len(tagIDs))
+		for tagID := range tagIDs {
+			set[tagID] = true
+		}
+		snap.articleTags[articleID] = set
+	}
+	for key, at := range s.favorites {
+		snap.favorites[key] = at
+	}
+	for key, at := range s.follows {
+		snap.follows[key] = at
+	}
+	for slug, articleID := range s.slugHistory {
+		snap.slugHistory[slug] = articleID
+	}
+	for id, revision := range s.revisions {
+		snap.revisions[id] = copyRevision(revision)
+	}
+	for key, alias := range s.tagAliases {
+		snap.tagAliases[key] = alias
+	}
+	for id, edit := range s.commentEdits {
+		e := *edit
+		snap.commentEdits[id] = &e
+	}
+	for id, report := range s.commentReports {
+		snap.commentReports[id] = copyCommentReport(report)
+	}
+	for id, action := range s.commentActions {
+		a := *action
+		snap.commentActions[id] = &a
+	}
+	return snap
+}
+
+// restore khôi phục store về snapshot
+func (s *MemoryStore) restore(snap *memorySnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = snap.users
+	s.articles = snap.articles
+	s.comments = snap.comments
+	s.tags = snap.tags
+	s.articleTags = snap.articleTags
+	s.favorites = snap.favorites
+	s.follows = snap.follows
+	s.slugHistory = snap.slugHistory
+	s.revisions = snap.revisions
+	s.tagAliases = snap.tagAliases
+	s.commentEdits = snap.commentEdits
+	s.commentReports = snap.commentReports
+	s.commentActions = snap.commentActions
+	s.nextUserID = snap.nextUserID
+	s.nextArticleID = snap.nextArticleID
+	s.nextCommentID = snap.nextCommentID
+	s.nextTagID = snap.nextTagID
+	s.nextRevisionID = snap.nextRevisionID
+	s.nextCommentEditID = snap.nextCommentEditID
+	s.nextCommentReportID = snap.nextCommentReportID
+	s.nextCommentActionID = snap.nextCommentActionID
+}
+
+// deleteComment xóa hẳn comment cùng lịch sử chỉnh sửa, reports và lịch sử kiểm duyệt của nó
+// (giống ON DELETE CASCADE). Caller phải giữ lock.
+func (s *MemoryStore) deleteComment(commentID int) {
+	delete(s.comments, commentID)
+	for id, edit := range s.commentEdits {
+		if edit.CommentID == commentID {
+			delete(s.commentEdits, id)
+		}
+	}
+	for id, report := range s.commentReports {
+		if report.CommentID == commentID {
+			delete(s.commentReports, id)
+		}
+	}
+	for id, action := range s.commentActions {
+		if action.CommentID == commentID {
+			delete(s.commentActions, id)
+		}
+	}
+}
+
+// userByUsername tìm user theo username, caller phải giữ lock
+func (s *MemoryStore) userByUsername(username string) *models.User {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user
+		}
+	}
+	return nil
+}
+
+// tagIDsByName trả về ID của các tags có tên trong names, bỏ qua tên không tồn tại. Caller phải giữ lock.
+func (s *MemoryStore) tagIDsByName(names []string) []int {
+	var ids []int
+	for _, name := range uniqueStrings(names) {
+		for _, tag := range s.tags {
+			if tag.Name == name {
+				ids = append(ids, tag.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// copyUser trả về bản copy của user để caller không sửa trực tiếp dữ liệu trong store
+func copyUser(user *models.User) *models.User {
+	if user == nil {
+		return nil
+	}
+	c := *user
+	if user.Bio != nil {
+		bio := *user.Bio
+		c.Bio = &bio
+	}
+	if user.Image != nil {
+		image := *user.Image
+		c.Image = &image
+	}
+	if user.BannedAt != nil {
+		bannedAt := *user.BannedAt
+		c.BannedAt = &bannedAt
+	}
+	return &c
+}
+
+// copyArticle trả về bản copy của article
+func copyArticle(article *models.Article) *models.Article {
+	if article == nil {
+		return nil
+	}
+	c := *article
+	if article.DeletedAt != nil {
+		deletedAt := *article.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	if article.PublishedAt != nil {
+		publishedAt := *article.PublishedAt
+		c.PublishedAt = &publishedAt
+	}
+	if article.PublishAt != nil {
+		publishAt := *article.PublishAt
+		c.PublishAt = &publishAt
+	}
+	if article.ReviewReason != nil {
+		reviewReason := *article.ReviewReason
+		c.ReviewReason = &reviewReason
+	}
+	c.Meta = copyArticleMeta(article.Meta)
+	return &c
+}
+
+// copyArticleMeta trả về bản copy của meta
+func copyArticleMeta(meta *models.ArticleMeta) *models.ArticleMeta {
+	if meta == nil {
+		return nil
+	}
+	c := *meta
+	c.TableOfContents = append([]models.TOCEntry(nil), meta.TableOfContents...)
+	return &c
+}
+
+// copyComment trả về bản copy của comment
+func copyComment(comment *models.Comment) *models.Comment {
+	if comment == nil {
+		return nil
+	}
+	c := *comment
+	if comment.EditedAt != nil {
+		editedAt := *comment.EditedAt
+		c.EditedAt = &editedAt
+	}
+	if comment.ParentID != nil {
+		parentID := *comment.ParentID
+		c.ParentID = &parentID
+	}
+	if comment.HiddenAt != nil {
+		hiddenAt := *comment.HiddenAt
+		c.HiddenAt = &hiddenAt
+	}
+	if comment.DeletedAt != nil {
+		deletedAt := *comment.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+// copyCommentReport trả về bản copy của report
+func copyCommentReport(report *models.CommentReport) *models.CommentReport {
+	c := *report
+	if report.ReporterID != nil {
+		reporterID := *report.ReporterID
+		c.ReporterID = &reporterID
+	}
+	if report.ResolvedAt != nil {
+		resolvedAt := *report.ResolvedAt
+		c.ResolvedAt = &resolvedAt
+	}
+	return &c
+}
+
+// copyRevision trả về bản copy của revision
+func copyRevision(revision *models.ArticleRevision) *models.ArticleRevision {
+	if revision == nil {
+		return nil
+	}
+	c := *revision
+	return &c
+}
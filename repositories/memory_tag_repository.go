@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"news/models"
+	"sort"
+	"time"
+)
+
+// memoryTagRepository implement TagRepository bằng MemoryStore
+type memoryTagRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryTagRepository tạo TagRepository lưu dữ liệu trong store
+func NewMemoryTagRepository(store *MemoryStore) TagRepository {
+	return &memoryTagRepository{store: store}
+}
+
+// GetOrCreate lấy tag theo name hoặc alias, nếu không có thì tạo mới
+func (r *memoryTagRepository) GetOrCreate(name string) (*models.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if tag := r.byName(name); tag != nil {
+		return tag, nil
+	}
+
+	tag := &models.Tag{ID: r.store.nextTagID, Name: name}
+	r.store.nextTagID++
+	r.store.tags[tag.ID] = tag
+
+	return &models.Tag{ID: tag.ID, Name: tag.Name}, nil
+}
+
+// GetByName lấy tag theo name, nếu name là alias thì trả về tag gốc; trả về nil nếu không tồn tại
+func (r *memoryTagRepository) GetByName(name string) (*models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.byName(name), nil
+}
+
+// byName tìm tag theo name rồi tới alias, caller phải giữ lock
+func (r *memoryTagRepository) byName(name string) *models.Tag {
+	for _, tag := range r.store.tags {
+		if tag.Name == name {
+			return &models.Tag{ID: tag.ID, Name: tag.Name}
+		}
+	}
+	if tag, ok := r.store.tags[r.store.tagAliases[name].TagID]; ok {
+		return &models.Tag{ID: tag.ID, Name: tag.Name}
+	}
+	return nil
+}
+
+// GetCounts lấy các tags đang được dùng bởi ít nhất một article đã publish kèm số articles đó
+func (r *memoryTagRepository) GetCounts(sortBy TagSort, limit int) ([]*models.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := map[int]int{}
+	for articleID, tagIDs := range r.store.articleTags {
+		if article, ok := r.store.articles[articleID]; ok && article.IsPublished() && !article.IsDeleted() {
+			for tagID := range tagIDs {
+				counts[tagID]++
+			}
+		}
+	}
+
+	var tags []*models.TagCount
+	for tagID, count := range counts {
+		if tag, ok := r.store.tags[tagID]; ok {
+			tags = append(tags, &models.TagCount{Name: tag.Name, ArticlesCount: count})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if sortBy == TagSortPopular && tags[i].ArticlesCount != tags[j].ArticlesCount {
+			return tags[i].ArticlesCount > tags[j].ArticlesCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
+
+// GetTrending xếp hạng tags theo mức tăng số articles publish trong window gần nhất so với window liền trước
+func (r *memoryTagRepository) GetTrending(now time.Time, window time.Duration, limit int) ([]*models.TrendingTag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	currentStart := now.Add(-window)
+	previousStart := currentStart.Add(-window)
+
+	byTag := map[int]*models.TrendingTag{}
+	for articleID, tagIDs := range r.store.articleTags {
+		article, ok := r.store.articles[articleID]
+		if !ok || !article.IsPublished() || article.IsDeleted() || article.PublishedAt == nil {
+			continue
+		}
+		publishedAt := *article.PublishedAt
+		if publishedAt.Before(previousStart) || !publishedAt.Before(now) {
+			continue
+		}
+		for tagID := range tagIDs {
+			tag, ok := r.store.tags[tagID]
+			if !ok {
+				continue
+			}
+			trending := byTag[tagID]
+			if trending == nil {
+				trending = &models.TrendingTag{Name: tag.Name}
+				byTag[tagID] = trending
+			}
+			if publishedAt.Before(currentStart) {
+				trending.PreviousCount++
+			} else {
+				trending.CurrentCount++
+			}
+		}
+	}
+
+	var tags []*models.TrendingTag
+	for _, tag := range byTag {
+		if tag.CurrentCount > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		gi, gj := tags[i].CurrentCount-tags[i].PreviousCount, tags[j].CurrentCount-tags[j].PreviousCount
+		if gi != gj {
+			return gi > gj
+		}
+		if tags[i].CurrentCount != tags[j].CurrentCount {
+			return tags[i].CurrentCount > tags[j].CurrentCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
+
+// AddTagsToArticle thay thế toàn bộ tags của article bằng tagIDs
+func (r *memoryTagRepository) AddTagsToArticle(articleID int, tagIDs []int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	set := map[int]bool{}
+	for _, tagID := range tagIDs {
+		set[tagID] = true
+	}
+	r.store.articleTags[articleID] = set
+	return nil
+}
+
+// DeleteUnused xóa các tags trong names không còn gắn với article nào
+func (r *memoryTagRepository) DeleteUnused(names []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unused := map[string]bool{}
+	for _, name := range names {
+		unused[name] = true
+	}
+	for _, tagIDs := range r.store.articleTags {
+		for tagID := range tagIDs {
+			if tag, ok := r.store.tags[tagID]; ok {
+				delete(unused, tag.Name)
+			}
+		}
+	}
+	for _, alias := range r.store.tagAliases {
+		if tag, ok := r.store.tags[alias.TagID]; ok {
+			delete(unused, tag.Name)
+		}
+	}
+	for id, tag := range r.store.tags {
+		if unused[tag.Name] {
+			delete(r.store.tags, id)
+		}
+	}
+	return nil
+}
+
+// Merge chuyển articles và aliases của tag fromID sang tag toID rồi xóa tag fromID
+func (r *memoryTagRepository) Merge(fromID, toID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, tagIDs := range r.store.articleTags {
+		if tagIDs[fromID] {
+			delete(tagIDs, fromID)
+			tagIDs[toID] = true
+		}
+	}
+	for key, alias := range r.store.tagAliases {
+		if alias.TagID == fromID {
+			alias.TagID = toID
+			r.store.tagAliases[key] = alias
+		}
+	}
+	delete(r.store.tags, fromID)
+	return nil
+}
+
+// CreateAlias tạo alias cho tag, trả về ErrDuplicateEntry nếu alias đã tồn tại
+func (r *memoryTagRepository) CreateAlias(alias string, tagID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tagAliases[alias]; ok {
+		return ErrDuplicateEntry
+	}
+	r.store.tagAliases[alias] = models.TagAlias{Alias: alias, TagID: tagID, CreatedAt: time.Now()}
+	return nil
+}
+
+// DeleteAlias xóa alias, trả về false nếu alias không tồn tại
+func (r *memoryTagRepository) DeleteAlias(alias string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tagAliases[alias]; !ok {
+		return false, nil
+	}
+	delete(r.store.tagAliases, alias)
+	return true, nil
+}
+
+// ListAliases lấy tất cả aliases kèm tên tag gốc, sắp xếp theo alias
+func (r *memoryTagRepository) ListAliases() ([]*models.TagAlias, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var aliases []*models.TagAlias
+	for _, alias := range r.store.tagAliases {
+		if tag, ok := r.store.tags[alias.TagID]; ok {
+			c := alias
+			c.TagName = tag.Name
+			aliases = append(aliases, &c)
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+	return aliases, nil
+}
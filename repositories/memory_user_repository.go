@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"news/models"
+	"time"
+)
+
+// memoryUserRepository implement UserRepository bằng MemoryStore
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryUserRepository tạo UserRepository lưu dữ liệu trong store
+func NewMemoryUserRepository(store *MemoryStore) UserRepository {
+	return &memoryUserRepository{store: store}
+}
+
+// Create tạo user mới, trả về ErrDuplicateEntry nếu username hoặc email đã tồn tại
+func (r *memoryUserRepository) Create(username, email, passwordHash string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.Username == username || user.Email == email {
+			return nil, ErrDuplicateEntry
+		}
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:           r.store.nextUserID,
+		Username:     username,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         models.UserRoleUser,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	r.store.nextUserID++
+	r.store.users[user.ID] = user
+
+	return copyUser(user), nil
+}
+
+// GetByID lấy user theo ID, trả về nil nếu không tồn tại
+func (r *memoryUserRepository) GetByID(id int) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return copyUser(r.store.users[id]), nil
+}
+
+// GetByIDs lấy nhiều users, trả về map id -> user (bỏ qua ID không tồn tại)
+func (r *memoryUserRepository) GetByIDs(ids []int) (map[int]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := map[int]*models.User{}
+	for _, id := range ids {
+		if user, ok := r.store.users[id]; ok {
+			users[id] = copyUser(user)
+		}
+	}
+	return users, nil
+}
+
+// GetByEmail lấy user theo email, trả về nil nếu không tồn tại
+func (r *memoryUserRepository) GetByEmail(email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetByUsername lấy user theo username, trả về nil nếu không tồn tại
+func (r *memoryUserRepository) GetByUsername(username string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return copyUser(r.store.userByUsername(username)), nil
+}
+
+// Update cập nhật các field khác nil của user
+func (r *memoryUserRepository) Update(userID int, email, username, passwordHash *string, bio, image *string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	for _, other := range r.store.users {
+		if other.ID == userID {
+			continue
+		}
+		if (email != nil && other.Email == *email) || (username != nil && other.Username == *username) {
+			return nil, ErrDuplicateEntry
+		}
+	}
+
+	if email != nil {
+		user.Email = *email
+	}
+	if username != nil {
+		user.Username = *username
+	}
+	if passwordHash != nil {
+		user.PasswordHash = *passwordHash
+	}
+	if bio != nil {
+		value := *bio
+		user.Bio = &value
+	}
+	if image != nil {
+		value := *image
+		user.Image = &value
+	}
+	user.UpdatedAt = time.Now()
+
+	return copyUser(user), nil
+}
+
+// SetRole đổi role của user
+func (r *memoryUserRepository) SetRole(userID int, role string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if user, ok := r.store.users[userID]; ok {
+		user.Role = role
+		user.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// SetBanned cấm user bình luận từ bannedAt, bannedAt nil là bỏ cấm
+func (r *memoryUserRepository) SetBanned(userID int, bannedAt *time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if user, ok := r.store.users[userID]; ok {
+		if bannedAt != nil {
+			at := *bannedAt
+			user.BannedAt = &at
+		} else {
+			user.BannedAt = nil
+		}
+		user.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"news/config"
+	"news/database"
+)
+
+// Repositories gom tất cả repositories mà services cần
+// Services nhận từng repository qua constructor nên có thể thay MySQL bằng in-memory khi test.
+type Repositories struct {
+	Article  ArticleRepository
+	User     UserRepository
+	Comment  CommentRepository
+	Tag      TagRepository
+	Follow   FollowRepository
+	Revision RevisionRepository
+
+	// UnitOfWork chạy nhiều thao tác trên các repositories trong cùng một transaction
+	UnitOfWork UnitOfWork
+}
+
+// NewMySQLRepositories tạo Repositories dùng MySQL connection db
+func NewMySQLRepositories(db *sql.DB) *Repositories {
+	repos := newMySQLRepositories(db)
+	repos.UnitOfWork = &mysqlUnitOfWork{db: db}
+	return repos
+}
+
+// newMySQLRepositories tạo các MySQL repositories dùng chung executor db (connection hoặc transaction)
+func newMySQLRepositories(db database.DBTX) *Repositories {
+	return &Repositories{
+		Article:  NewArticleRepository(db),
+		User:     NewUserRepository(db),
+		Comment:  NewCommentRepository(db),
+		Tag:      NewTagRepository(db),
+		Follow:   NewFollowRepository(db),
+		Revision: NewRevisionRepository(db),
+	}
+}
+
+// NewMemoryRepositories tạo Repositories dùng chung một MemoryStore mới
+func NewMemoryRepositories() *Repositories {
+	store := NewMemoryStore()
+	repos := &Repositories{
+		Article:  NewMemoryArticleRepository(store),
+		User:     NewMemoryUserRepository(store),
+		Comment:  NewMemoryCommentRepository(store),
+		Tag:      NewMemoryTagRepository(store),
+		Follow:   NewMemoryFollowRepository(store),
+		Revision: NewMemoryRevisionRepository(store),
+	}
+	repos.UnitOfWork = &memoryUnitOfWork{store: store, repos: repos}
+	return repos
+}
+
+// NewRepositories chọn backend theo cfg.StorageDriver
+// "mysql": khởi tạo database.DB và dùng MySQL
+// "memory": lưu dữ liệu trong bộ nhớ, mất khi restart
+func NewRepositories(cfg *config.Config) (*Repositories, error) {
+	switch cfg.StorageDriver {
+	case config.StorageMemory:
+		return NewMemoryRepositories(), nil
+	case config.StorageMySQL:
+		if err := database.InitDB(); err != nil {
+			return nil, err
+		}
+		return NewMySQLRepositories(database.DB), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql"
+	"news/database"
+	"news/models"
+	"time"
+)
+
+// RevisionRepository định nghĩa các thao tác dữ liệu trên bảng article_revisions
+type RevisionRepository interface {
+	Create(articleID, editorID int, title, description, body, bodyHTML string) (*models.ArticleRevision, error)
+	GetByNumber(articleID, number int) (*models.ArticleRevision, error)
+	ListByArticleID(articleID int) ([]*models.ArticleRevision, error)
+}
+
+// mysqlRevisionRepository implement RevisionRepository bằng MySQL
+type mysqlRevisionRepository struct {
+	db database.DBTX
+}
+
+// NewRevisionRepository tạo RevisionRepository dùng MySQL connection hoặc transaction db
+func NewRevisionRepository(db database.DBTX) RevisionRepository {
+	return &mysqlRevisionRepository{db: db}
+}
+
+// revisionColumns là các cột của article_revisions theo thứ tự scanRevision đọc
+const revisionColumns = `id, article_id, number, title, description, body, COALESCE(body_html, ''), editor_id, created_at`
+
+// scanRevision đọc một dòng article_revisions
+func scanRevision(row rowScanner) (*models.ArticleRevision, error) {
+	revision := &models.ArticleRevision{}
+	err := row.Scan(
+		&revision.ID,
+		&revision.ArticleID,
+		&revision.Number,
+		&revision.Title,
+		&revision.Description,
+		&revision.Body,
+		&revision.BodyHTML,
+		&revision.EditorID,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// Create lưu revision mới cho article với number kế tiếp
+// Nên gọi trong cùng transaction với việc sửa article: dòng articles đang bị khóa
+// nên hai lần sửa đồng thời không lấy trùng number.
+func (r *mysqlRevisionRepository) Create(articleID, editorID int, title, description, body, bodyHTML string) (*models.ArticleRevision, error) {
+	query := `INSERT INTO article_revisions (article_id, number, title, description, body, body_html, editor_id, created_at)
+	          SELECT ?, COALESCE(MAX(number), 0) + 1, ?, ?, ?, ?, ?, ?
+	          FROM article_revisions WHERE article_id = ?`
+
+	result, err := r.db.Exec(query, articleID, title, description, body, bodyHTML, editorID, time.Now(), articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy ID vừa tạo
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.QueryRow(`SELECT `+revisionColumns+` FROM article_revisions WHERE id = ?`, id)
+	return scanRevision(row)
+}
+
+// GetByNumber lấy revision number của article, trả về nil nếu không tồn tại
+func (r *mysqlRevisionRepository) GetByNumber(articleID, number int) (*models.ArticleRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM article_revisions WHERE article_id = ? AND number = ?`
+
+	revision, err := scanRevision(r.db.QueryRow(query, articleID, number))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// ListByArticleID lấy tất cả revisions của article, mới nhất trước
+func (r *mysqlRevisionRepository) ListByArticleID(articleID int) ([]*models.ArticleRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM article_revisions
+	          WHERE article_id = ?
+	          ORDER BY number DESC`
+
+	rows, err := r.db.Query(query, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.ArticleRevision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import "strings"
+
+// inPlaceholders trả về chuỗi "?, ?, ?" gồm n placeholder cho mệnh đề IN
+func inPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// intArgs chuyển danh sách ID thành args cho db.Query
+func intArgs(ids []int) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
+
+// stringArgs chuyển danh sách string thành args cho db.Query
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
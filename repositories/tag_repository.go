@@ -5,36 +5,60 @@ import (
 	"news/database"
 	"news/models"
 	"strings"
+	"time"
 )
 
-// TagRepository chứa các method để làm việc với bảng tags
-type TagRepository struct{}
+// TagSort là thứ tự sắp xếp danh sách tags
+type TagSort string
 
-// NewTagRepository tạo instance mới của TagRepository
-func NewTagRepository() *TagRepository {
-	return &TagRepository{}
+const (
+	// TagSortName sắp xếp theo tên (mặc định)
+	TagSortName TagSort = "name"
+	// TagSortPopular sắp xếp nhiều articles nhất trước
+	TagSortPopular TagSort = "popular"
+)
+
+// IsValid kiểm tra sort có được hỗ trợ không, rỗng là hợp lệ (dùng TagSortName)
+func (s TagSort) IsValid() bool {
+	return s == "" || s == TagSortName || s == TagSortPopular
 }
 
-// GetOrCreate lấy tag theo name, nếu không có thì tạo mới
-func (r *TagRepository) GetOrCreate(name string) (*models.Tag, error) {
-	// Tìm tag theo name
-	query := `SELECT id, name FROM tags WHERE name = ?`
-	tag := &models.Tag{}
-	err := database.DB.QueryRow(query, name).Scan(&tag.ID, &tag.Name)
+// TagRepository định nghĩa các thao tác dữ liệu trên bảng tags và article_tags
+type TagRepository interface {
+	GetOrCreate(name string) (*models.Tag, error)
+	GetByName(name string) (*models.Tag, error)
+	GetCounts(sort TagSort, limit int) ([]*models.TagCount, error)
+	GetTrending(now time.Time, window time.Duration, limit int) ([]*models.TrendingTag, error)
+	AddTagsToArticle(articleID int, tagIDs []int) error
+	DeleteUnused(names []string) error
+	Merge(fromID, toID int) error
+	CreateAlias(alias string, tagID int) error
+	DeleteAlias(alias string) (bool, error)
+	ListAliases() ([]*models.TagAlias, error)
+}
 
-	if err == nil {
-		// Tag đã tồn tại
-		return tag, nil
-	}
+// mysqlTagRepository implement TagRepository bằng MySQL
+type mysqlTagRepository struct {
+	db database.DBTX
+}
 
-	if err != sql.ErrNoRows {
-		// Lỗi khác
-		return nil, err
+// NewTagRepository tạo TagRepository dùng MySQL connection hoặc transaction db
+func NewTagRepository(db database.DBTX) TagRepository {
+	return &mysqlTagRepository{db: db}
+}
+
+// GetOrCreate lấy tag theo name hoặc alias, nếu không có thì tạo mới
+// name phải đã được chuẩn hóa (utils.NormalizeTag).
+func (r *mysqlTagRepository) GetOrCreate(name string) (*models.Tag, error) {
+	// Tìm tag theo name hoặc alias
+	tag, err := r.GetByName(name)
+	if err != nil || tag != nil {
+		return tag, err
 	}
 
 	// Tag chưa tồn tại, tạo mới
 	insertQuery := `INSERT INTO tags (name) VALUES (?)`
-	result, err := database.DB.Exec(insertQuery, name)
+	result, err := r.db.Exec(insertQuery, name)
 	if err != nil {
 		return nil, err
 	}
@@ -44,39 +68,106 @@ func (r *TagRepository) GetOrCreate(name string) (*models.Tag, error) {
 		return nil, err
 	}
 
-	tag.ID = int(id)
-	tag.Name = name
+	return &models.Tag{ID: int(id), Name: name}, nil
+}
+
+// GetByName lấy tag theo name, nếu name là alias thì trả về tag gốc; trả về nil nếu không tồn tại
+func (r *mysqlTagRepository) GetByName(name string) (*models.Tag, error) {
+	query := `SELECT id, name FROM tags WHERE name = ?
+	          UNION ALL
+	          SELECT t.id, t.name FROM tag_aliases ta INNER JOIN tags t ON ta.tag_id = t.id WHERE ta.alias = ?
+	          LIMIT 1`
+	tag := &models.Tag{}
+	err := r.db.QueryRow(query, name, name).Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
 	return tag, nil
 }
 
-// GetAll lấy tất cả tags
-func (r *TagRepository) GetAll() ([]*models.Tag, error) {
-	query := `SELECT id, name FROM tags ORDER BY name`
+// GetCounts lấy các tags đang được dùng bởi ít nhất một article đã publish kèm số articles đó
+// Tags chỉ có trên draft/archived không hiện ra để không lộ nội dung chưa publish;
+// articles trong thùng rác cũng không được tính. limit <= 0 là không giới hạn.
+func (r *mysqlTagRepository) GetCounts(sort TagSort, limit int) ([]*models.TagCount, error) {
+	query := `SELECT t.name, COUNT(*) AS articles_count
+	          FROM tags t
+	          INNER JOIN article_tags at ON at.tag_id = t.id
+	          INNER JOIN articles a ON at.article_id = a.id
+	          WHERE a.status = ? AND a.deleted_at IS NULL
+	          GROUP BY t.id, t.name`
+	if sort == TagSortPopular {
+		query += ` ORDER BY articles_count DESC, t.name`
+	} else {
+		query += ` ORDER BY t.name`
+	}
+	args := []interface{}{models.ArticleStatusPublished}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
 
-	rows, err := database.DB.Query(query)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tags []*models.Tag
+	var tags []*models.TagCount
 	for rows.Next() {
-		tag := &models.Tag{}
-		err := rows.Scan(&tag.ID, &tag.Name)
-		if err != nil {
+		tag := &models.TagCount{}
+		if err := rows.Scan(&tag.Name, &tag.ArticlesCount); err != nil {
 			return nil, err
 		}
 		tags = append(tags, tag)
 	}
+	return tags, rows.Err()
+}
+
+// GetTrending xếp hạng tags theo mức tăng số articles publish trong window gần nhất (tính tới now)
+// so với window liền trước; chỉ lấy tags có article trong window gần nhất.
+// Cùng mức tăng thì tag có nhiều articles hơn đứng trước.
+func (r *mysqlTagRepository) GetTrending(now time.Time, window time.Duration, limit int) ([]*models.TrendingTag, error) {
+	currentStart := now.Add(-window)
+	previousStart := currentStart.Add(-window)
+
+	query := `SELECT t.name,
+	                 SUM(a.published_at >= ?) AS current_count,
+	                 SUM(a.published_at < ?) AS previous_count
+	          FROM tags t
+	          INNER JOIN article_tags at ON at.tag_id = t.id
+	          INNER JOIN articles a ON at.article_id = a.id
+	          WHERE a.status = ? AND a.deleted_at IS NULL AND a.published_at >= ? AND a.published_at < ?
+	          GROUP BY t.id, t.name
+	          HAVING current_count > 0
+	          ORDER BY current_count - previous_count DESC, current_count DESC, t.name
+	          LIMIT ?`
+
+	rows, err := r.db.Query(query, currentStart, currentStart, models.ArticleStatusPublished, previousStart, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return tags, nil
+	var tags []*models.TrendingTag
+	for rows.Next() {
+		tag := &models.TrendingTag{}
+		if err := rows.Scan(&tag.Name, &tag.CurrentCount, &tag.PreviousCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
 }
 
 // AddTagsToArticle thêm tags vào article (many-to-many)
-func (r *TagRepository) AddTagsToArticle(articleID int, tagIDs []int) error {
+// Nên gọi trong UnitOfWork để việc xóa tags cũ và thêm tags mới cùng commit
+func (r *mysqlTagRepository) AddTagsToArticle(articleID int, tagIDs []int) error {
 	// Xóa tags cũ của article trước
 	deleteQuery := `DELETE FROM article_tags WHERE article_id = ?`
-	_, err := database.DB.Exec(deleteQuery, articleID)
+	_, err := r.db.Exec(deleteQuery, articleID)
 	if err != nil {
 		return err
 	}
@@ -96,6 +187,81 @@ func (r *TagRepository) AddTagsToArticle(articleID int, tagIDs []int) error {
 	}
 
 	insertQuery += strings.Join(placeholders, ", ")
-	_, err = database.DB.Exec(insertQuery, values...)
+	_, err = r.db.Exec(insertQuery, values...)
 	return err
 }
+
+// DeleteUnused xóa các tags trong names không còn gắn với article nào
+// Tags vẫn gắn với article trong thùng rác được giữ lại để khôi phục article không mất tags;
+// tags có alias cũng được giữ lại để không mất alias admin đã tạo.
+func (r *mysqlTagRepository) DeleteUnused(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	query := `DELETE FROM tags
+	          WHERE name IN (` + inPlaceholders(len(names)) + `)
+	            AND NOT EXISTS (SELECT 1 FROM article_tags at WHERE at.tag_id = tags.id)
+	            AND NOT EXISTS (SELECT 1 FROM tag_aliases ta WHERE ta.tag_id = tags.id)`
+	_, err := r.db.Exec(query, stringArgs(names)...)
+	return err
+}
+
+// Merge chuyển articles và aliases của tag fromID sang tag toID rồi xóa tag fromID
+// Article đã có cả hai tags chỉ còn một dòng article_tags. Nên gọi trong UnitOfWork.
+func (r *mysqlTagRepository) Merge(fromID, toID int) error {
+	if _, err := r.db.Exec(`INSERT IGNORE INTO article_tags (article_id, tag_id)
+	                        SELECT article_id, ? FROM article_tags WHERE tag_id = ?`, toID, fromID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(`UPDATE tag_aliases SET tag_id = ? WHERE tag_id = ?`, toID, fromID); err != nil {
+		return err
+	}
+	// Các dòng article_tags còn lại của fromID bị xóa theo (ON DELETE CASCADE)
+	_, err := r.db.Exec(`DELETE FROM tags WHERE id = ?`, fromID)
+	return err
+}
+
+// CreateAlias tạo alias cho tag, alias đã tồn tại thì trả về lỗi duplicate entry
+func (r *mysqlTagRepository) CreateAlias(alias string, tagID int) error {
+	query := `INSERT INTO tag_aliases (alias, tag_id, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.Exec(query, alias, tagID, time.Now())
+	return err
+}
+
+// DeleteAlias xóa alias, trả về false nếu alias không tồn tại
+func (r *mysqlTagRepository) DeleteAlias(alias string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM tag_aliases WHERE alias = ?`, alias)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListAliases lấy tất cả aliases kèm tên tag gốc, sắp xếp theo alias
+func (r *mysqlTagRepository) ListAliases() ([]*models.TagAlias, error) {
+	query := `SELECT ta.alias, ta.tag_id, t.name, ta.created_at
+	          FROM tag_aliases ta
+	          INNER JOIN tags t ON ta.tag_id = t.id
+	          ORDER BY ta.alias`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []*models.TagAlias
+	for rows.Next() {
+		alias := &models.TagAlias{}
+		if err := rows.Scan(&alias.Alias, &alias.TagID, &alias.TagName, &alias.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
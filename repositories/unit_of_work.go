@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// UnitOfWork chạy một nhóm thao tác như một đơn vị: tất cả commit hoặc tất cả rollback
+// fn nhận bộ Repositories gắn với transaction; dùng các repository này thay vì repository gốc
+// để các thao tác nằm trong cùng transaction. Nếu fn trả về error (hoặc panic) thì rollback.
+// Gọi Do lồng nhau từ bên trong fn sẽ join vào transaction đang có.
+type UnitOfWork interface {
+	Do(fn func(tx *Repositories) error) error
+}
+
+// mysqlUnitOfWork implement UnitOfWork bằng sql.Tx
+type mysqlUnitOfWork struct {
+	db *sql.DB
+}
+
+// Do mở transaction, chạy fn với repositories dùng transaction đó rồi commit/rollback
+func (u *mysqlUnitOfWork) Do(fn func(tx *Repositories) error) error {
+	sqlTx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txRepos := newMySQLRepositories(sqlTx)
+	txRepos.UnitOfWork = &joinedUnitOfWork{repos: txRepos}
+
+	if err := fn(txRepos); err != nil {
+		if rbErr := sqlTx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+// memoryUnitOfWork implement UnitOfWork cho MemoryStore bằng snapshot/restore
+// Các transaction chạy tuần tự (txMu); nếu fn lỗi thì khôi phục store về snapshot.
+// Thao tác ngoài transaction chạy xen kẽ sẽ bị mất khi rollback; chấp nhận được vì backend
+// này chỉ dùng cho test và demo.
+type memoryUnitOfWork struct {
+	store *MemoryStore
+	repos *Repositories
+}
+
+// Do chụp snapshot store, chạy fn và khôi phục snapshot nếu fn lỗi
+func (u *memoryUnitOfWork) Do(fn func(tx *Repositories) error) error {
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	snapshot := u.store.snapshot()
+
+	defer func() {
+		if p := recover(); p != nil {
+			u.store.restore(snapshot)
+			panic(p)
+		}
+	}()
+
+	txRepos := *u.repos
+	txRepos.UnitOfWork = &joinedUnitOfWork{repos: &txRepos}
+
+	if err := fn(&txRepos); err != nil {
+		u.store.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+// joinedUnitOfWork dùng bên trong transaction đang mở: chạy fn trực tiếp với cùng repositories
+type joinedUnitOfWork struct {
+	repos *Repositories
+}
+
+// Do chạy fn trong transaction hiện tại
+func (u *joinedUnitOfWork) Do(fn func(tx *Repositories) error) error {
+	return fn(u.repos)
+}
+
+// IsDuplicateEntry kiểm tra lỗi vi phạm unique/primary key của MySQL hoặc in-memory backend
+func IsDuplicateEntry(err error) bool {
+	if errors.Is(err, ErrDuplicateEntry) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"errors"
+	"news/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryUnitOfWork_Commit kiểm tra thay đổi trong transaction thành công được giữ lại
+func TestMemoryUnitOfWork_Commit(t *testing.T) {
+	repos := NewMemoryRepositories()
+	user, err := repos.User.Create("author", "author@example.com", "hash")
+	require.NoError(t, err)
+
+	err = repos.UnitOfWork.Do(func(tx *Repositories) error {
+		article, err := tx.Article.Create("hello", "Hello", "desc", "body", "<p>body</p>\n", user.ID, models.ArticleStatusPublished)
+		if err != nil {
+			return err
+		}
+		return tx.Article.Favorite(user.ID, article.ID)
+	})
+	require.NoError(t, err)
+
+	article, err := repos.Article.GetBySlug("hello")
+	require.NoError(t, err)
+	require.NotNil(t, article)
+	assert.Equal(t, 1, article.FavoritesCount)
+}
+
+// TestMemoryUnitOfWork_Rollback kiểm tra transaction lỗi thì mọi thay đổi bị hủy
+func TestMemoryUnitOfWork_Rollback(t *testing.T) {
+	repos := NewMemoryRepositories()
+	user, err := repos.User.Create("author", "author@example.com", "hash")
+	require.NoError(t, err)
+
+	errBoom := errors.New("boom")
+	err = repos.UnitOfWork.Do(func(tx *Repositories) error {
+		article, err := tx.Article.Create("hello", "Hello", "desc", "body", "<p>body</p>\n", user.ID, models.ArticleStatusPublished)
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Tag.GetOrCreate("go")
+		if err != nil {
+			return err
+		}
+		if err := tx.Tag.AddTagsToArticle(article.ID, []int{tag.ID}); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	assert.ErrorIs(t, err, errBoom)
+
+	exists, err := repos.Article.IsSlugExists("hello")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	tag, err := repos.Tag.GetByName("go")
+	require.NoError(t, err)
+	assert.Nil(t, tag)
+}
+
+// TestMemoryUnitOfWork_Nested kiểm tra Do lồng nhau join vào transaction ngoài
+func TestMemoryUnitOfWork_Nested(t *testing.T) {
+	repos := NewMemoryRepositories()
+
+	err := repos.UnitOfWork.Do(func(tx *Repositories) error {
+		if _, err := tx.User.Create("inner", "inner@example.com", "hash"); err != nil {
+			return err
+		}
+		// Nested Do không được deadlock và phải rollback cùng transaction ngoài
+		if err := tx.UnitOfWork.Do(func(inner *Repositories) error {
+			_, err := inner.User.Create("nested", "nested@example.com", "hash")
+			return err
+		}); err != nil {
+			return err
+		}
+		return errors.New("rollback all")
+	})
+	assert.Error(t, err)
+
+	user, err := repos.User.GetByUsername("nested")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+// TestIsDuplicateEntry kiểm tra nhận diện lỗi trùng key
+func TestIsDuplicateEntry(t *testing.T) {
+	assert.True(t, IsDuplicateEntry(ErrDuplicateEntry))
+	assert.False(t, IsDuplicateEntry(errors.New("other")))
+}
@@ -7,21 +7,35 @@ import (
 	"time"
 )
 
-// UserRepository chứa các method để làm việc với bảng users
-type UserRepository struct{}
+// UserRepository định nghĩa các thao tác dữ liệu trên bảng users
+type UserRepository interface {
+	Create(username, email, passwordHash string) (*models.User, error)
+	GetByID(id int) (*models.User, error)
+	GetByIDs(ids []int) (map[int]*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	Update(userID int, email, username, passwordHash *string, bio, image *string) (*models.User, error)
+	SetRole(userID int, role string) error
+	SetBanned(userID int, bannedAt *time.Time) error
+}
+
+// mysqlUserRepository implement UserRepository bằng MySQL
+type mysqlUserRepository struct {
+	db database.DBTX
+}
 
-// NewUserRepository tạo instance mới của UserRepository
-func NewUserRepository() *UserRepository {
-	return &UserRepository{}
+// NewUserRepository tạo UserRepository dùng MySQL connection hoặc transaction db
+func NewUserRepository(db database.DBTX) UserRepository {
+	return &mysqlUserRepository{db: db}
 }
 
 // Create tạo user mới trong database
-func (r *UserRepository) Create(username, email, passwordHash string) (*models.User, error) {
+func (r *mysqlUserRepository) Create(username, email, passwordHash string) (*models.User, error) {
 	query := `INSERT INTO users (username, email, password_hash, created_at, updated_at) 
 	          VALUES (?, ?, ?, ?, ?)`
 
 	now := time.Now()
-	result, err := database.DB.Exec(query, username, email, passwordHash, now, now)
+	result, err := r.db.Exec(query, username, email, passwordHash, now, now)
 	if err != nil {
 		return nil, err
 	}
@@ -37,20 +51,22 @@ func (r *UserRepository) Create(username, email, passwordHash string) (*models.U
 }
 
 // GetByID lấy user theo ID
-func (r *UserRepository) GetByID(id int) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, bio, image, created_at, updated_at 
+func (r *mysqlUserRepository) GetByID(id int) (*models.User, error) {
+	query := `SELECT id, username, email, password_hash, bio, image, role, banned_at, created_at, updated_at 
 	          FROM users WHERE id = ?`
 
 	user := &models.User{}
 	var bio, image sql.NullString
 
-	err := database.DB.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
 		&bio,
 		&image,
+		&user.Role,
+		&user.BannedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -72,21 +88,73 @@ func (r *UserRepository) GetByID(id int) (*models.User, error) {
 	return user, nil
 }
 
+// GetByIDs lấy nhiều users trong một query, trả về map id -> user
+// ID không tồn tại thì không có trong map
+func (r *mysqlUserRepository) GetByIDs(ids []int) (map[int]*models.User, error) {
+	users := map[int]*models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `SELECT id, username, email, password_hash, bio, image, role, banned_at, created_at, updated_at 
+	          FROM users WHERE id IN (` + inPlaceholders(len(ids)) + `)`
+
+	rows, err := r.db.Query(query, intArgs(ids)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &models.User{}
+		var bio, image sql.NullString
+
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&bio,
+			&image,
+			&user.Role,
+			&user.BannedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Convert NullString sang *string
+		if bio.Valid {
+			user.Bio = &bio.String
+		}
+		if image.Valid {
+			user.Image = &image.String
+		}
+		users[user.ID] = user
+	}
+
+	return users, rows.Err()
+}
+
 // GetByEmail lấy user theo email
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, bio, image, created_at, updated_at 
+func (r *mysqlUserRepository) GetByEmail(email string) (*models.User, error) {
+	query := `SELECT id, username, email, password_hash, bio, image, role, banned_at, created_at, updated_at 
 	          FROM users WHERE email = ?`
 
 	user := &models.User{}
 	var bio, image sql.NullString
 
-	err := database.DB.QueryRow(query, email).Scan(
+	err := r.db.QueryRow(query, email).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
 		&bio,
 		&image,
+		&user.Role,
+		&user.BannedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -109,20 +177,22 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 }
 
 // GetByUsername lấy user theo username
-func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, bio, image, created_at, updated_at 
+func (r *mysqlUserRepository) GetByUsername(username string) (*models.User, error) {
+	query := `SELECT id, username, email, password_hash, bio, image, role, banned_at, created_at, updated_at 
 	          FROM users WHERE username = ?`
 
 	user := &models.User{}
 	var bio, image sql.NullString
 
-	err := database.DB.QueryRow(query, username).Scan(
+	err := r.db.QueryRow(query, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
 		&bio,
 		&image,
+		&user.Role,
+		&user.BannedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -145,7 +215,7 @@ func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
 }
 
 // Update cập nhật thông tin user
-func (r *UserRepository) Update(userID int, email, username, passwordHash *string, bio, image *string) (*models.User, error) {
+func (r *mysqlUserRepository) Update(userID int, email, username, passwordHash *string, bio, image *string) (*models.User, error) {
 	// Build query động dựa trên các field cần update
 	query := "UPDATE users SET updated_at = ?"
 	args := []interface{}{time.Now()}
@@ -174,7 +244,7 @@ func (r *UserRepository) Update(userID int, email, username, passwordHash *strin
 	query += " WHERE id = ?"
 	args = append(args, userID)
 
-	_, err := database.DB.Exec(query, args...)
+	_, err := r.db.Exec(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -182,3 +252,17 @@ func (r *UserRepository) Update(userID int, email, username, passwordHash *strin
 	// Lấy user đã được update
 	return r.GetByID(userID)
 }
+
+// SetRole đổi role của user
+func (r *mysqlUserRepository) SetRole(userID int, role string) error {
+	query := `UPDATE users SET role = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, role, time.Now(), userID)
+	return err
+}
+
+// SetBanned cấm user bình luận từ bannedAt, bannedAt nil là bỏ cấm
+func (r *mysqlUserRepository) SetBanned(userID int, bannedAt *time.Time) error {
+	query := `UPDATE users SET banned_at = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, bannedAt, time.Now(), userID)
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"news/database"
+	"news/models"
+	"news/repositories"
+)
+
+// roleUsage hướng dẫn sử dụng subcommand role
+const roleUsage = "usage: news role <username> user|moderator|admin"
+
+// runRoleCommand xử lý subcommand "role <username> <role>": đổi role của user
+// Luôn chạy trên MySQL, không phụ thuộc STORAGE_DRIVER.
+func runRoleCommand(args []string) error {
+	if len(args) != 2 {
+		return errors.New(roleUsage)
+	}
+	username, role := args[0], args[1]
+	if !models.IsValidRole(role) {
+		return errors.New(roleUsage)
+	}
+
+	if err := database.InitDB(); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.CloseDB()
+
+	userRepo := repositories.NewUserRepository(database.DB)
+	user, err := userRepo.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %q not found", username)
+	}
+	if err := userRepo.SetRole(user.ID, role); err != nil {
+		return err
+	}
+
+	log.Printf("%s is now %s", username, role)
+	return nil
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"news/config"
+	"news/controllers"
+	"news/middlewares"
+	"news/repositories"
+	"news/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Setup khởi tạo services, controllers từ repos và đăng ký middlewares + API routes lên router
+// Dùng chung cho main.go và integration tests để hai nơi luôn có cùng routes.
+func Setup(router *gin.Engine, repos *repositories.Repositories, cfg *config.Config) {
+	// Middleware xử lý lỗi
+	router.Use(middlewares.ErrorHandler())
+
+	// Middleware xác thực (không bắt buộc, để controller quyết định)
+	router.Use(middlewares.AuthMiddleware())
+
+	// Khởi tạo services
+	contentFilter := services.NewContentFilterFromConfig(cfg, repos.Article, repos.Comment)
+	authService := services.NewAuthService(repos.User)
+	profileService := services.NewProfileService(repos.User, repos.Follow)
+	articleService := services.NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, contentFilter)
+	commentService := services.NewCommentService(repos.UnitOfWork, repos.Comment, repos.Article, repos.User, repos.Follow, cfg.CommentMaxDepth, cfg.CommentEditWindow, contentFilter)
+	tagService := services.NewTagService(repos.UnitOfWork, repos.Tag, repos.User, cfg.TagCacheTTL)
+	revisionService := services.NewRevisionService(articleService, repos.Article, repos.Revision, repos.User)
+	moderationService := services.NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	trashService := services.NewTrashService(repos.UnitOfWork, articleService, commentService, repos.Article, repos.Comment, cfg.TrashRetention)
+
+	// Khởi tạo controllers
+	authController := controllers.NewAuthController(authService)
+	profileController := controllers.NewProfileController(profileService)
+	articleController := controllers.NewArticleController(articleService)
+	commentController := controllers.NewCommentController(commentService)
+	tagController := controllers.NewTagController(tagService)
+	revisionController := controllers.NewRevisionController(revisionService)
+	trashController := controllers.NewTrashController(trashService)
+	moderationController := controllers.NewModerationController(moderationService)
+
+	// Redirect GET request dùng slug cũ (trước khi đổi title) sang slug hiện tại
+	movedSlug := middlewares.RedirectMovedSlug(articleService.ResolveMovedSlug)
+
+	// API routes
+	api := router.Group("/api")
+	{
+		// Authentication routes
+		api.POST("/users", authController.Register)
+		api.POST("/users/login", authController.Login)
+		api.GET("/user", middlewares.RequireAuth(), authController.GetCurrentUser)
+		api.PUT("/user", middlewares.RequireAuth(), authController.UpdateCurrentUser)
+
+		// Trash routes (articles/comments đã xóa của user hiện tại)
+		api.GET("/user/trash", middlewares.RequireAuth(), trashController.GetTrash)
+		api.POST("/user/trash/articles/:slug/restore", middlewares.RequireAuth(), trashController.RestoreArticle)
+		api.POST("/user/trash/comments/:id/restore", middlewares.RequireAuth(), trashController.RestoreComment)
+
+		// Profile routes
+		api.GET("/profiles/:username", profileController.GetProfile)
+		api.POST("/profiles/:username/follow", middlewares.RequireAuth(), profileController.FollowUser)
+		api.DELETE("/profiles/:username/follow", middlewares.RequireAuth(), profileController.UnfollowUser)
+
+		// Article routes
+		api.GET("/articles", articleController.ListArticles)
+		api.GET("/articles/feed", middlewares.RequireAuth(), articleController.FeedArticles)
+		api.GET("/articles/search", articleController.SearchArticles)
+		api.GET("/articles/:slug", movedSlug, articleController.GetArticle)
+		api.POST("/articles", middlewares.RequireAuth(), articleController.CreateArticle)
+		api.PUT("/articles/:slug", middlewares.RequireAuth(), articleController.UpdateArticle)
+		api.DELETE("/articles/:slug", middlewares.RequireAuth(), articleController.DeleteArticle)
+		api.POST("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.FavoriteArticle)
+		api.DELETE("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.UnfavoriteArticle)
+		api.POST("/articles/:slug/publish", middlewares.RequireAuth(), articleController.PublishArticle)
+		api.DELETE("/articles/:slug/publish", middlewares.RequireAuth(), articleController.UnpublishArticle)
+		api.POST("/articles/:slug/archive", middlewares.RequireAuth(), articleController.ArchiveArticle)
+		api.POST("/articles/:slug/schedule", middlewares.RequireAuth(), articleController.ScheduleArticle)
+
+		// Revision routes
+		api.GET("/articles/:slug/revisions", movedSlug, revisionController.ListRevisions)
+		api.GET("/articles/:slug/revisions/diff", movedSlug, revisionController.DiffRevisions)
+		api.GET("/articles/:slug/revisions/:n", movedSlug, revisionController.GetRevision)
+		api.POST("/articles/:slug/revisions/:n/restore", middlewares.RequireAuth(), revisionController.RestoreRevision)
+
+		// Comment routes
+		api.POST("/articles/:slug/comments", middlewares.RequireAuth(), commentController.AddComment)
+		api.GET("/articles/:slug/comments", movedSlug, commentController.GetComments)
+		api.PUT("/articles/:slug/comments/:id", middlewares.RequireAuth(), commentController.UpdateComment)
+		api.DELETE("/articles/:slug/comments/:id", middlewares.RequireAuth(), commentController.DeleteComment)
+		api.GET("/articles/:slug/comments/:id/edits", middlewares.RequireAuth(), commentController.ListCommentEdits)
+		api.POST("/articles/:slug/comments/:id/report", middlewares.RequireAuth(), commentController.ReportComment)
+		api.POST("/articles/:slug/comments/:id/hide", middlewares.RequireAuth(), commentController.HideComment)
+
+		// Moderation routes (hàng đợi comments bị report và articles chờ xem xét, chỉ dành cho moderators)
+		api.GET("/moderation/comments", middlewares.RequireAuth(), moderationController.ListReportedComments)
+		api.POST("/moderation/comments/:id", middlewares.RequireAuth(), moderationController.ModerateComment)
+		api.GET("/moderation/articles", middlewares.RequireAuth(), moderationController.ListArticlesForReview)
+		api.POST("/moderation/articles/:slug", middlewares.RequireAuth(), moderationController.ModerateArticle)
+
+		// Tag routes (aliases và merge chỉ dành cho admin)
+		api.GET("/tags", tagController.GetTags)
+		api.GET("/tags/trending", tagController.TrendingTags)
+		api.GET("/tags/aliases", middlewares.RequireAuth(), tagController.ListAliases)
+		api.POST("/tags/aliases", middlewares.RequireAuth(), tagController.CreateAlias)
+		api.DELETE("/tags/aliases/:alias", middlewares.RequireAuth(), tagController.DeleteAlias)
+		api.POST("/tags/merge", middlewares.RequireAuth(), tagController.MergeTags)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/dto"
+	"news/repositories"
+	"strings"
+	"time"
+)
+
+// dateLayout là định dạng ngày (không có giờ) được chấp nhận cho createdAfter/createdBefore
+const dateLayout = "2006-01-02"
+
+// articleFilter chuyển query params thành repositories.ArticleFilter
+// Trả về lỗi validation gom theo từng field nếu sort, tagMode hoặc khoảng thời gian không hợp lệ.
+func articleFilter(query dto.ArticleListQuery) (repositories.ArticleFilter, error) {
+	filter := repositories.ArticleFilter{
+		Tags:        splitValues(query.Tag),
+		TagMode:     repositories.TagMatchMode(query.TagMode),
+		ExcludeTags: splitValues(query.ExcludeTag),
+		Authors:     splitValues(query.Author),
+		Favorited:   strings.TrimSpace(query.Favorited),
+		Sort:        repositories.ArticleSort(query.Sort),
+	}
+
+	fields := map[string][]string{}
+	if !filter.Sort.IsValid() {
+		fields["sort"] = append(fields["sort"], "is invalid")
+	}
+	if !filter.TagMode.IsValid() {
+		fields["tagMode"] = append(fields["tagMode"], "is invalid")
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseFilterTime(query.CreatedAfter, false); err != nil {
+		fields["createdAfter"] = append(fields["createdAfter"], "is invalid")
+	}
+	if filter.CreatedBefore, err = parseFilterTime(query.CreatedBefore, true); err != nil {
+		fields["createdBefore"] = append(fields["createdBefore"], "is invalid")
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		fields["createdBefore"] = append(fields["createdBefore"], "must be after createdAfter")
+	}
+
+	if len(fields) > 0 {
+		return filter, apperrors.Validation(fields)
+	}
+	return filter, nil
+}
+
+// splitValues tách các giá trị phân cách bằng dấu phẩy, bỏ khoảng trắng và giá trị rỗng
+// Ví dụ: ["go,web", " rust "] -> ["go", "web", "rust"]
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// parseFilterTime parse RFC3339 hoặc YYYY-MM-DD (UTC), chuỗi rỗng trả về nil
+// Với endOfDay, ngày không có giờ được tính đến hết ngày đó (đầu ngày hôm sau).
+func parseFilterTime(value string, endOfDay bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
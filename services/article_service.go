@@ -2,120 +2,211 @@ package services
 
 import (
 	"errors"
+	"news/apperrors"
 	"news/dto"
+	"news/models"
 	"news/repositories"
 	"news/utils"
+	"strings"
+	"time"
 )
 
+// reservedSlugs là các slug trùng với route tĩnh dưới /api/articles/, article không được dùng
+var reservedSlugs = map[string]bool{
+	"feed":   true,
+	"search": true,
+}
+
 // ArticleService chứa business logic cho articles
 type ArticleService struct {
-	articleRepo *repositories.ArticleRepository
-	tagRepo     *repositories.TagRepository
-	userRepo    *repositories.UserRepository
-	followRepo  *repositories.FollowRepository
+	uow         repositories.UnitOfWork
+	articleRepo repositories.ArticleRepository
+	tagRepo     repositories.TagRepository
+	userRepo    repositories.UserRepository
+	followRepo  repositories.FollowRepository
+	filter      *ContentFilterPipeline
 }
 
 // NewArticleService tạo instance mới của ArticleService
-func NewArticleService() *ArticleService {
+// uow dùng để tạo/sửa/xóa/favorite article trong một transaction
+// filter kiểm tra title, description và body trước khi lưu, nil là không kiểm tra.
+func NewArticleService(uow repositories.UnitOfWork, articleRepo repositories.ArticleRepository, tagRepo repositories.TagRepository, userRepo repositories.UserRepository, followRepo repositories.FollowRepository, filter *ContentFilterPipeline) *ArticleService {
 	return &ArticleService{
-		articleRepo: repositories.NewArticleRepository(),
-		tagRepo:     repositories.NewTagRepository(),
-		userRepo:    repositories.NewUserRepository(),
-		followRepo:  repositories.NewFollowRepository(),
+		uow:         uow,
+		articleRepo: articleRepo,
+		tagRepo:     tagRepo,
+		userRepo:    userRepo,
+		followRepo:  followRepo,
+		filter:      filter,
 	}
 }
 
 // CreateArticle tạo article mới
+// Mặc định article được publish ngay; req.Article.Status = "draft" để lưu nháp.
+// Nội dung bị content filter từ chối thì không lưu; bị giữ lại thì article sẽ public (publish ngay
+// hoặc hẹn giờ) được lưu với status pending chờ moderator duyệt, lịch hẹn publishAt vẫn được lưu.
 func (s *ArticleService) CreateArticle(authorID int, req dto.CreateArticleRequest) (*dto.ArticleResponse, error) {
+	status := req.Article.Status
+	if status == "" {
+		status = models.ArticleStatusPublished
+	}
+
+	// Hẹn giờ publish: article được tạo như draft rồi chuyển sang scheduled trong cùng transaction
+	publishAt := req.Article.PublishAt
+	if publishAt != nil {
+		if req.Article.Status == models.ArticleStatusPublished {
+			return nil, apperrors.FieldError("publishAt", "can't be set when status is published")
+		}
+		if err := validatePublishAt(*publishAt); err != nil {
+			return nil, err
+		}
+		status = models.ArticleStatusDraft
+	}
+
+	// Chuẩn hóa tags trước khi ghi
+	tagList, err := s.canonicalTags("tagList", req.Article.TagList)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra nội dung
+	decision, err := s.checkArticleContent(authorID, 0, req.Article.Title, req.Article.Description, req.Article.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Article bị giữ lại vẫn được hẹn giờ (publish_at) để moderator duyệt xong thì chạy tiếp lịch đó
+	hold := decision.Verdict == ContentHold && (status == models.ArticleStatusPublished || publishAt != nil)
+	if hold && publishAt == nil {
+		status = models.ArticleStatusPending
+	}
+
 	// Tạo slug từ title
 	baseSlug := utils.GenerateSlug(req.Article.Title)
 
 	// Tạo slug unique
 	slug := utils.GenerateUniqueSlug(baseSlug, func(slugStr string) bool {
+		if reservedSlugs[slugStr] {
+			return true
+		}
 		exists, _ := s.articleRepo.IsSlugExists(slugStr)
 		return exists
 	})
 
-	// Tạo article
-	article, err := s.articleRepo.Create(
-		slug,
-		req.Article.Title,
-		req.Article.Description,
-		req.Article.Body,
-		authorID,
-	)
-	if err != nil {
-		return nil, err
-	}
+	// Tạo article và gắn tags trong cùng transaction
+	// Nếu gắn tags lỗi thì article cũng không được tạo
+	var articleID int
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		article, err := tx.Article.Create(
+			slug,
+			req.Article.Title,
+			req.Article.Description,
+			req.Article.Body,
+			utils.RenderMarkdown(req.Article.Body),
+			authorID,
+			status,
+		)
+		if err != nil {
+			return err
+		}
+		articleID = article.ID
+
+		if err := tx.Article.SetMeta(article.ID, articleMeta(article.Body)); err != nil {
+			return err
+		}
 
-	// Xử lý tags nếu có
-	if len(req.Article.TagList) > 0 {
-		tagIDs := []int{}
-		for _, tagName := range req.Article.TagList {
-			tag, err := s.tagRepo.GetOrCreate(tagName)
-			if err != nil {
-				return nil, err
+		// Nội dung ban đầu là revision 1
+		if _, err := tx.Revision.Create(article.ID, authorID, article.Title, article.Description, article.Body, article.BodyHTML); err != nil {
+			return err
+		}
+
+		if publishAt != nil {
+			if _, err := tx.Article.Schedule(article.ID, *publishAt); err != nil {
+				return err
+			}
+			if hold {
+				if err := tx.Article.Hold(article.ID); err != nil {
+					return err
+				}
 			}
-			tagIDs = append(tagIDs, tag.ID)
 		}
-		err = s.tagRepo.AddTagsToArticle(article.ID, tagIDs)
-		if err != nil {
-			return nil, err
+		if decision.Verdict != ContentAccept {
+			reason := decision.Reason()
+			if err := tx.Article.SetReviewReason(article.ID, &reason); err != nil {
+				return err
+			}
+		}
+
+		// Xử lý tags nếu có
+		if len(tagList) > 0 {
+			return setArticleTags(tx, article.ID, tagList)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	invalidateTagStats()
 
 	// Lấy article với đầy đủ thông tin để trả về
-	return s.buildArticleResponse(article.ID, nil)
+	return s.buildArticleResponse(articleID, nil)
 }
 
-// GetArticle lấy article theo slug
+// GetArticle lấy article theo slug, slug cũ (trước khi đổi title) cũng được chấp nhận
+// Response luôn chứa slug hiện tại của article. Draft và archived chỉ author xem được.
 func (s *ArticleService) GetArticle(slug string, currentUserID *int) (*dto.ArticleResponse, error) {
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, currentUserID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
-	}
 
 	return s.buildArticleResponse(article.ID, currentUserID)
 }
 
-// ListArticles lấy danh sách articles với filters và pagination
-func (s *ArticleService) ListArticles(tag, author, favorited *string, limit, offset int, currentUserID *int) (*dto.ArticleListResponse, error) {
-	// Lấy articles
-	articles, err := s.articleRepo.List(tag, author, favorited, limit, offset)
+// ListArticles lấy danh sách articles với filters, sắp xếp và pagination
+// cursor là nextCursor của trang trước (keyset pagination) và chỉ dùng được với cùng sort;
+// khi có cursor thì offset bị bỏ qua.
+func (s *ArticleService) ListArticles(query dto.ArticleListQuery, cursor string, limit, offset int, currentUserID *int) (*dto.ArticleListResponse, error) {
+	filter, err := articleFilter(query)
 	if err != nil {
 		return nil, err
 	}
-
-	// Đếm tổng số
-	count, err := s.articleRepo.Count(tag, author, favorited)
+	s.resolveFilterTags(&filter)
+	after, err := decodeCursor(cursor)
 	if err != nil {
 		return nil, err
 	}
+	if after != nil {
+		offset = 0
+	}
 
-	// Build response
-	response := &dto.ArticleListResponse{
-		Articles:      []dto.ArticleResponse{},
-		ArticlesCount: count,
+	// Lấy thêm 1 article để biết còn trang sau không
+	articles, err := s.articleRepo.List(filter, after, limit+1, offset)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, article := range articles {
-		articleResp, err := s.buildArticleResponse(article.ID, currentUserID)
-		if err != nil {
-			return nil, err
-		}
-		response.Articles = append(response.Articles, *articleResp)
+	// Đếm tổng số
+	count, err := s.articleRepo.Count(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	return s.buildArticleListResponse(articles, count, limit, filter.Sort, currentUserID)
 }
 
 // FeedArticles lấy articles từ users mà currentUser đang follow
-func (s *ArticleService) FeedArticles(currentUserID int, limit, offset int) (*dto.ArticleListResponse, error) {
-	// Lấy articles
-	articles, err := s.articleRepo.Feed(currentUserID, limit, offset)
+// cursor hoạt động giống ListArticles
+func (s *ArticleService) FeedArticles(currentUserID int, cursor string, limit, offset int) (*dto.ArticleListResponse, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		offset = 0
+	}
+
+	// Lấy thêm 1 article để biết còn trang sau không
+	articles, err := s.articleRepo.Feed(currentUserID, after, limit+1, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -126,42 +217,88 @@ func (s *ArticleService) FeedArticles(currentUserID int, limit, offset int) (*dt
 		return nil, err
 	}
 
-	// Build response
-	response := &dto.ArticleListResponse{
-		Articles:      []dto.ArticleResponse{},
-		ArticlesCount: count,
+	return s.buildArticleListResponse(articles, count, limit, repositories.SortRecent, &currentUserID)
+}
+
+// snippetLength là độ dài tối đa (ký tự) của đoạn trích trong kết quả tìm kiếm
+const snippetLength = 200
+
+// SearchArticles tìm articles theo từ khóa q trong title, description và body
+// Có thể kết hợp với các filter của ListArticles; kết quả luôn sắp xếp theo relevance giảm dần.
+func (s *ArticleService) SearchArticles(q string, query dto.ArticleListQuery, limit, offset int, currentUserID *int) (*dto.ArticleSearchResponse, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, apperrors.FieldError("q", "can't be blank")
 	}
+	filter, err := articleFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	s.resolveFilterTags(&filter)
 
-	currentUserIDPtr := &currentUserID
-	for _, article := range articles {
-		articleResp, err := s.buildArticleResponse(article.ID, currentUserIDPtr)
-		if err != nil {
-			return nil, err
-		}
-		response.Articles = append(response.Articles, *articleResp)
+	results, err := s.articleRepo.Search(q, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.articleRepo.SearchCount(q, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]*models.Article, 0, len(results))
+	for _, result := range results {
+		articles = append(articles, result.Article)
+	}
+	articleResps, err := s.buildArticleResponses(articles, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := utils.Tokenize(q)
+	response := &dto.ArticleSearchResponse{
+		Articles:      make([]dto.ArticleSearchResult, 0, len(results)),
+		ArticlesCount: count,
+	}
+	for i, result := range results {
+		response.Articles = append(response.Articles, dto.ArticleSearchResult{
+			ArticleResponse: articleResps[i],
+			Score:           result.Score,
+			Snippet:         searchSnippet(result.Article, terms),
+		})
 	}
 
 	return response, nil
 }
 
+// searchSnippet lấy đoạn trích từ field đầu tiên có từ khớp theo thứ tự body, description, title
+func searchSnippet(article *models.Article, terms []string) string {
+	for _, text := range []string{article.Body, article.Description, article.Title} {
+		snippet := utils.Snippet(text, terms, snippetLength)
+		if strings.Contains(snippet, "<mark>") {
+			return snippet
+		}
+	}
+	return utils.Snippet(article.Body, terms, snippetLength)
+}
+
 // UpdateArticle cập nhật article
+// Nội dung mới qua content filter như khi tạo; article đang public (published hoặc scheduled) bị giữ lại
+// thì chuyển sang pending chờ moderator duyệt.
 func (s *ArticleService) UpdateArticle(slug string, authorID int, req dto.UpdateArticleRequest) (*dto.ArticleResponse, error) {
 	// Lấy article hiện tại
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
-	}
 
 	// Kiểm tra quyền sở hữu
 	if article.AuthorID != authorID {
-		return nil, errors.New("permission denied")
+		return nil, ErrPermissionDenied
 	}
 
 	// Chuẩn bị các giá trị để update
-	var newSlug, title, description, body *string
+	var newSlug, title, description, body, bodyHTML *string
 
 	if req.Article.Title != nil {
 		title = req.Article.Title
@@ -175,10 +312,20 @@ func (s *ArticleService) UpdateArticle(slug string, authorID int, req dto.Update
 				if slugStr == currentSlug {
 					return false
 				}
+				if reservedSlugs[slugStr] {
+					return true
+				}
 				exists, _ := s.articleRepo.IsSlugExists(slugStr)
-				return exists
+				if !exists {
+					return false
+				}
+				// Slug cũ của chính article này thì được dùng lại
+				previous, _ := s.articleRepo.GetBySlugHistory(slugStr)
+				return previous == nil || previous.ID != article.ID
 			})
-			newSlug = &newSlugValue
+			if newSlugValue != currentSlug {
+				newSlug = &newSlugValue
+			}
 		}
 	}
 
@@ -186,53 +333,128 @@ func (s *ArticleService) UpdateArticle(slug string, authorID int, req dto.Update
 		description = req.Article.Description
 	}
 
+	// Chuẩn hóa tags trước khi ghi
+	if req.Article.TagList != nil {
+		tagList, err := s.canonicalTags("tagList", *req.Article.TagList)
+		if err != nil {
+			return nil, err
+		}
+		req.Article.TagList = &tagList
+	}
+	if req.Article.AddTags, err = s.canonicalTags("addTags", req.Article.AddTags); err != nil {
+		return nil, err
+	}
+	if req.Article.RemoveTags, err = s.canonicalTags("removeTags", req.Article.RemoveTags); err != nil {
+		return nil, err
+	}
+
 	if req.Article.Body != nil {
 		body = req.Article.Body
+		// Render Markdown một lần khi ghi, cache theo revision
+		html := utils.RenderMarkdown(*body)
+		bodyHTML = &html
+	}
+
+	// Kiểm tra nội dung sau khi sửa, chỉ khi title/description/body thay đổi
+	decision := &ContentDecision{Verdict: ContentAccept}
+	finalTitle, finalDescription, finalBody := valueOr(title, article.Title), valueOr(description, article.Description), valueOr(body, article.Body)
+	if finalTitle != article.Title || finalDescription != article.Description || finalBody != article.Body {
+		decision, err = s.checkArticleContent(authorID, article.ID, finalTitle, finalDescription, finalBody)
+		if err != nil {
+			return nil, err
+		}
 	}
+	hold := decision.Verdict == ContentHold &&
+		(article.IsPublished() || article.Status == models.ArticleStatusScheduled)
 
-	// Update article
-	updatedArticle, err := s.articleRepo.Update(article.ID, newSlug, title, description, body)
+	// Update article trong transaction, slug cũ được lưu vào lịch sử để link cũ vẫn dùng được
+	// Tags và revision mới (nếu nội dung thay đổi) được ghi trong cùng transaction.
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		if newSlug != nil {
+			if err := tx.Article.DeleteSlugHistory(*newSlug); err != nil {
+				return err
+			}
+			if err := tx.Article.AddSlugHistory(article.ID, article.Slug); err != nil {
+				return err
+			}
+		}
+		updated, err := tx.Article.Update(article.ID, newSlug, title, description, body, bodyHTML)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return ErrArticleNotFound
+		}
+		if err := updateArticleTags(tx, article.ID, req); err != nil {
+			return err
+		}
+		if hold {
+			if err := tx.Article.Hold(article.ID); err != nil {
+				return err
+			}
+		}
+		if decision.Verdict != ContentAccept {
+			reason := decision.Reason()
+			if err := tx.Article.SetReviewReason(article.ID, &reason); err != nil {
+				return err
+			}
+		}
+		if updated.Body != article.Body {
+			if err := tx.Article.SetMeta(article.ID, articleMeta(updated.Body)); err != nil {
+				return err
+			}
+		}
+		if updated.Title == article.Title && updated.Description == article.Description && updated.Body == article.Body {
+			return nil
+		}
+		_, err = tx.Revision.Create(article.ID, authorID, updated.Title, updated.Description, updated.Body, updated.BodyHTML)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	invalidateTagStats()
 
 	// Build response
-	return s.buildArticleResponse(updatedArticle.ID, &authorID)
+	return s.buildArticleResponse(article.ID, &authorID)
 }
 
-// DeleteArticle xóa article
+// DeleteArticle xóa mềm article, article biến mất khỏi mọi nơi nhưng vẫn nằm trong thùng rác của author
 func (s *ArticleService) DeleteArticle(slug string, authorID int) error {
 	// Lấy article
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
 	if err != nil {
 		return err
 	}
-	if article == nil {
-		return errors.New("article not found")
-	}
 
 	// Kiểm tra quyền sở hữu
 	if article.AuthorID != authorID {
-		return errors.New("permission denied")
+		return ErrPermissionDenied
 	}
 
-	// Xóa article
-	return s.articleRepo.Delete(article.ID)
+	// Chuyển article vào thùng rác, author có thể khôi phục trong thời gian lưu giữ
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		return tx.Article.Delete(article.ID)
+	})
+	if err != nil {
+		return err
+	}
+	invalidateTagStats()
+	return nil
 }
 
 // FavoriteArticle thêm article vào favorites
 func (s *ArticleService) FavoriteArticle(slug string, userID int) (*dto.ArticleResponse, error) {
 	// Lấy article
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, &userID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
-	}
 
-	// Favorite article
-	err = s.articleRepo.Favorite(userID, article.ID)
+	// Favorite article: insert favorites và tăng favorites_count cùng commit
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		return tx.Article.Favorite(userID, article.ID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -244,16 +466,15 @@ func (s *ArticleService) FavoriteArticle(slug string, userID int) (*dto.ArticleR
 // UnfavoriteArticle xóa article khỏi favorites
 func (s *ArticleService) UnfavoriteArticle(slug string, userID int) (*dto.ArticleResponse, error) {
 	// Lấy article
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, &userID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
-	}
 
-	// Unfavorite article
-	err = s.articleRepo.Unfavorite(userID, article.ID)
+	// Unfavorite article: xóa favorites và giảm favorites_count cùng commit
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		return tx.Article.Unfavorite(userID, article.ID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +483,348 @@ func (s *ArticleService) UnfavoriteArticle(slug string, userID int) (*dto.Articl
 	return s.buildArticleResponse(article.ID, &userID)
 }
 
+// PublishArticle publish article của author
+// published_at được giữ nguyên nếu article đã từng publish (unpublish rồi publish lại).
+func (s *ArticleService) PublishArticle(slug string, authorID int) (*dto.ArticleResponse, error) {
+	return s.setArticleStatus(slug, authorID, models.ArticleStatusPublished)
+}
+
+// UnpublishArticle chuyển article về draft (hủy lịch hẹn nếu có), article biến mất khỏi các danh sách công khai
+func (s *ArticleService) UnpublishArticle(slug string, authorID int) (*dto.ArticleResponse, error) {
+	return s.setArticleStatus(slug, authorID, models.ArticleStatusDraft)
+}
+
+// ArchiveArticle lưu trữ article, chỉ author còn xem được
+func (s *ArticleService) ArchiveArticle(slug string, authorID int) (*dto.ArticleResponse, error) {
+	return s.setArticleStatus(slug, authorID, models.ArticleStatusArchived)
+}
+
+// ScheduleArticle hẹn giờ publish article chưa publish, PublishScheduler sẽ publish khi tới publishAt
+// Hẹn lại giờ cho article đang scheduled sẽ ghi đè lịch cũ. Nội dung còn chờ xem xét mà content filter
+// vẫn giữ lại thì article chuyển sang pending, lịch hẹn được giữ tới khi moderator duyệt.
+func (s *ArticleService) ScheduleArticle(slug string, authorID int, req dto.ScheduleArticleRequest) (*dto.ArticleResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra quyền sở hữu
+	if article.AuthorID != authorID {
+		return nil, ErrPermissionDenied
+	}
+
+	if article.IsPending() {
+		return nil, ErrArticlePendingReview
+	}
+	if article.IsPublished() {
+		return nil, apperrors.FieldError("publishAt", "can't be set on a published article")
+	}
+	if err := validatePublishAt(req.Article.PublishAt); err != nil {
+		return nil, err
+	}
+	decision, err := s.recheckContent(article)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		if _, err := tx.Article.Schedule(article.ID, req.Article.PublishAt); err != nil {
+			return err
+		}
+		if decision.Verdict != ContentHold {
+			return nil
+		}
+		if err := tx.Article.Hold(article.ID); err != nil {
+			return err
+		}
+		reason := decision.Reason()
+		return tx.Article.SetReviewReason(article.ID, &reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildArticleResponse(article.ID, &authorID)
+}
+
+// PublishDueArticles publish tối đa limit articles đã tới giờ hẹn, trả về số articles đã publish
+// Chạy trong transaction để các dòng bị khóa tới khi commit (an toàn khi nhiều replica cùng chạy).
+func (s *ArticleService) PublishDueArticles(now time.Time, limit int) (int, error) {
+	var published int
+	err := s.uow.Do(func(tx *repositories.Repositories) error {
+		ids, err := tx.Article.PublishDue(now, limit)
+		published = len(ids)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if published > 0 {
+		invalidateTagStats()
+	}
+	return published, nil
+}
+
+// validatePublishAt kiểm tra thời điểm hẹn publish phải ở tương lai
+func validatePublishAt(publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return apperrors.FieldError("publishAt", "must be in the future")
+	}
+	return nil
+}
+
+// setArticleStatus đổi status của article, chỉ author được đổi
+// Article đang chờ moderator duyệt thì không đổi được status. Publish nội dung còn chờ xem xét mà
+// content filter vẫn giữ lại thì article chuyển sang pending thay vì published.
+func (s *ArticleService) setArticleStatus(slug string, authorID int, status string) (*dto.ArticleResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra quyền sở hữu
+	if article.AuthorID != authorID {
+		return nil, ErrPermissionDenied
+	}
+	if article.IsPending() {
+		return nil, ErrArticlePendingReview
+	}
+
+	publishedAt := article.PublishedAt
+	decision := &ContentDecision{Verdict: ContentAccept}
+	if status == models.ArticleStatusPublished {
+		if decision, err = s.recheckContent(article); err != nil {
+			return nil, err
+		}
+		if decision.Verdict == ContentHold {
+			status = models.ArticleStatusPending
+		} else if publishedAt == nil {
+			now := time.Now()
+			publishedAt = &now
+		}
+	}
+
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		if _, err := tx.Article.SetStatus(article.ID, status, publishedAt); err != nil {
+			return err
+		}
+		if decision.Verdict != ContentHold {
+			return nil
+		}
+		reason := decision.Reason()
+		return tx.Article.SetReviewReason(article.ID, &reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateTagStats()
+
+	return s.buildArticleResponse(article.ID, &authorID)
+}
+
+// checkArticleContent chạy content filter trên nội dung article của authorID
+// articleID là article đang sửa (0 khi tạo mới). Nội dung bị từ chối trả về lỗi validation.
+func (s *ArticleService) checkArticleContent(authorID, articleID int, title, description, body string) (*ContentDecision, error) {
+	author, err := s.userRepo.GetByID(authorID)
+	if err != nil {
+		return nil, err
+	}
+	return checkContent(s.filter, &Content{
+		Kind:   ContentKindArticle,
+		Author: author,
+		ID:     articleID,
+		Text:   title + "\n" + description + "\n" + body,
+		Body:   body,
+	})
+}
+
+// recheckContent chạy lại content filter trên nội dung article còn chờ moderators xem xét trước khi
+// article được publish hoặc hẹn giờ: draft bị giữ lại chỉ được gắn cờ nên phải kiểm tra lại khi sắp public.
+// Article không chờ xem xét (hoặc đã được duyệt) không cần kiểm tra.
+func (s *ArticleService) recheckContent(article *models.Article) (*ContentDecision, error) {
+	if article.ReviewReason == nil {
+		return &ContentDecision{Verdict: ContentAccept}, nil
+	}
+	return s.checkArticleContent(article.AuthorID, article.ID, article.Title, article.Description, article.Body)
+}
+
+// valueOr trả về *value nếu value khác nil, ngược lại trả về fallback
+func valueOr(value *string, fallback string) string {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
+
+// ResolveMovedSlug kiểm tra slug có phải slug cũ của một article đã đổi title không
+// Trả về slug hiện tại và moved = true nếu slug đã bị thay; slug không tồn tại thì moved = false.
+// Chỉ article đã publish mới được redirect để không lộ slug mới của draft.
+func (s *ArticleService) ResolveMovedSlug(slug string) (string, bool, error) {
+	article, err := s.articleRepo.GetBySlugHistory(slug)
+	if err != nil || article == nil || !article.IsPublished() {
+		return "", false, err
+	}
+	return article.Slug, true, nil
+}
+
+// findArticleBySlug lấy article theo slug hiện tại, nếu không có thì tìm trong lịch sử slug
+func findArticleBySlug(articleRepo repositories.ArticleRepository, slug string) (*models.Article, error) {
+	article, err := articleRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if article == nil {
+		article, err = articleRepo.GetBySlugHistory(slug)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if article == nil {
+		return nil, ErrArticleNotFound
+	}
+	return article, nil
+}
+
+// findVisibleArticle giống findArticleBySlug nhưng article chưa publish chỉ hiện với author
+// Với người khác, draft và archived được coi như không tồn tại.
+func findVisibleArticle(articleRepo repositories.ArticleRepository, slug string, currentUserID *int) (*models.Article, error) {
+	article, err := findArticleBySlug(articleRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsPublished() && (currentUserID == nil || *currentUserID != article.AuthorID) {
+		return nil, ErrArticleNotFound
+	}
+	return article, nil
+}
+
+// renderedBody trả về HTML đã render của body, render lại nếu chưa có (dữ liệu trước khi lưu body_html)
+func renderedBody(body, bodyHTML string) string {
+	if bodyHTML == "" {
+		return utils.RenderMarkdown(body)
+	}
+	return bodyHTML
+}
+
+// articleMeta tính số từ, thời gian đọc, đoạn trích và mục lục từ body
+func articleMeta(body string) models.ArticleMeta {
+	analyzed := utils.AnalyzeMarkdown(body)
+	meta := models.ArticleMeta{
+		WordCount:       analyzed.WordCount,
+		ReadingTime:     analyzed.ReadingTime,
+		Excerpt:         analyzed.Excerpt,
+		TableOfContents: make([]models.TOCEntry, 0, len(analyzed.Headings)),
+	}
+	for _, heading := range analyzed.Headings {
+		meta.TableOfContents = append(meta.TableOfContents, models.TOCEntry{
+			Level: heading.Level,
+			Text:  heading.Text,
+			ID:    heading.ID,
+		})
+	}
+	return meta
+}
+
+// canonicalTags chuẩn hóa tên tags (utils.NormalizeTag) và thay alias bằng tag gốc
+// Tên không hợp lệ trả về lỗi validation của field.
+func (s *ArticleService) canonicalTags(field string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		canonical, err := utils.NormalizeTag(name)
+		if err != nil {
+			return nil, apperrors.FieldError(field, err.Error())
+		}
+		tag, err := s.tagRepo.GetByName(canonical)
+		if err != nil {
+			return nil, err
+		}
+		if tag != nil {
+			canonical = tag.Name
+		}
+		result = append(result, canonical)
+	}
+	return result, nil
+}
+
+// resolveFilterTags chuẩn hóa tags trong filter như khi gắn tags, ?tag=Golang lọc được articles có tag "go"
+// Tên không hợp lệ được giữ nguyên (không khớp article nào).
+func (s *ArticleService) resolveFilterTags(filter *repositories.ArticleFilter) {
+	for _, tags := range [][]string{filter.Tags, filter.ExcludeTags} {
+		for i, name := range tags {
+			if canonical, err := s.canonicalTags("tag", []string{name}); err == nil {
+				tags[i] = canonical[0]
+			}
+		}
+	}
+}
+
+// setArticleTags thay thế tags của article bằng tagNames, dùng repositories của transaction tx
+func setArticleTags(tx *repositories.Repositories, articleID int, tagNames []string) error {
+	tagIDs := []int{}
+	seen := map[int]bool{}
+	for _, tagName := range tagNames {
+		tag, err := tx.Tag.GetOrCreate(tagName)
+		if err != nil {
+			return err
+		}
+		// Tên trùng nhau chỉ gắn một lần
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+	return tx.Tag.AddTagsToArticle(articleID, tagIDs)
+}
+
+// updateArticleTags áp dụng tagList, addTags và removeTags của req lên tags hiện tại của article
+// Tags bị bỏ khỏi article mà không còn article nào dùng thì bị xóa khỏi bảng tags.
+func updateArticleTags(tx *repositories.Repositories, articleID int, req dto.UpdateArticleRequest) error {
+	if req.Article.TagList == nil && len(req.Article.AddTags) == 0 && len(req.Article.RemoveTags) == 0 {
+		return nil
+	}
+
+	current, err := tx.Article.GetTagsByArticleID(articleID)
+	if err != nil {
+		return err
+	}
+
+	tagNames := current
+	if req.Article.TagList != nil {
+		tagNames = *req.Article.TagList
+	}
+	removed := map[string]bool{}
+	for _, name := range req.Article.RemoveTags {
+		removed[name] = true
+	}
+	keep := map[string]bool{}
+	newTags := []string{}
+	for _, name := range append(append([]string{}, tagNames...), req.Article.AddTags...) {
+		if !removed[name] && !keep[name] {
+			keep[name] = true
+			newTags = append(newTags, name)
+		}
+	}
+
+	// Không đổi gì thì không ghi
+	dropped := []string{}
+	for _, name := range current {
+		if !keep[name] {
+			dropped = append(dropped, name)
+		}
+	}
+	if len(dropped) == 0 && len(newTags) == len(current) {
+		return nil
+	}
+
+	if err := setArticleTags(tx, articleID, newTags); err != nil {
+		return err
+	}
+	return tx.Tag.DeleteUnused(dropped)
+}
+
 // buildArticleResponse build ArticleResponse từ article ID
 func (s *ArticleService) buildArticleResponse(articleID int, currentUserID *int) (*dto.ArticleResponse, error) {
 	// Lấy article
@@ -270,57 +833,148 @@ func (s *ArticleService) buildArticleResponse(articleID int, currentUserID *int)
 		return nil, err
 	}
 	if article == nil {
-		return nil, errors.New("article not found")
+		return nil, ErrArticleNotFound
+	}
+
+	responses, err := s.buildArticleResponses([]*models.Article{article}, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// buildArticleListResponse build response cho một trang articles
+// articles được lấy với limit+1 bản ghi theo thứ tự order; bản ghi thừa chỉ dùng để tính nextCursor.
+func (s *ArticleService) buildArticleListResponse(articles []*models.Article, count, limit int, order repositories.ArticleSort, currentUserID *int) (*dto.ArticleListResponse, error) {
+	fetched := len(articles)
+	if fetched > limit {
+		articles = articles[:limit]
 	}
 
-	// Lấy author
-	author, err := s.articleRepo.GetAuthorByID(article.AuthorID)
+	// Build response cho cả trang với số query cố định
+	articleResps, err := s.buildArticleResponses(articles, currentUserID)
 	if err != nil {
 		return nil, err
 	}
-	if author == nil {
-		return nil, errors.New("author not found")
+
+	response := &dto.ArticleListResponse{
+		Articles:      articleResps,
+		ArticlesCount: count,
+	}
+	if len(articles) > 0 {
+		response.NextCursor = nextCursor(fetched, limit, order.CursorFor(articles[len(articles)-1]))
+	}
+	return response, nil
+}
+
+// buildArticleResponses build ArticleResponse cho cả danh sách articles
+// Authors, tags, số comments, favorited và following được load theo lô cho toàn bộ danh sách
+// nên số query không phụ thuộc vào số articles (tối đa 5 query).
+func (s *ArticleService) buildArticleResponses(articles []*models.Article, currentUserID *int) ([]dto.ArticleResponse, error) {
+	responses := make([]dto.ArticleResponse, 0, len(articles))
+	if len(articles) == 0 {
+		return responses, nil
+	}
+
+	articleIDs := make([]int, 0, len(articles))
+	authorIDs := make([]int, 0, len(articles))
+	seenAuthors := map[int]bool{}
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+		if !seenAuthors[article.AuthorID] {
+			seenAuthors[article.AuthorID] = true
+			authorIDs = append(authorIDs, article.AuthorID)
+		}
+	}
+
+	// Lấy authors
+	authors, err := s.userRepo.GetByIDs(authorIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Lấy tags
-	tags, err := s.articleRepo.GetTagsByArticleID(article.ID)
+	tags, err := s.articleRepo.GetTagsByArticleIDs(articleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Đếm comments
+	commentsCounts, err := s.articleRepo.GetCommentsCounts(articleIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	// Kiểm tra favorited
-	favorited := false
+	// Kiểm tra favorited và following (nếu có currentUserID)
+	favorited := map[int]bool{}
+	following := map[int]bool{}
 	if currentUserID != nil {
-		favorited, err = s.articleRepo.IsFavorited(*currentUserID, article.ID)
+		favorited, err = s.articleRepo.GetFavoritedArticleIDs(*currentUserID, articleIDs)
 		if err != nil {
 			return nil, err
 		}
-	}
-
-	// Kiểm tra following (nếu có currentUserID)
-	following := false
-	if currentUserID != nil && *currentUserID != article.AuthorID {
-		following, err = s.followRepo.IsFollowing(*currentUserID, article.AuthorID)
+		following, err = s.followRepo.GetFollowingIDs(*currentUserID, authorIDs)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Build response
-	response := &dto.ArticleResponse{}
-	response.Article.Slug = article.Slug
-	response.Article.Title = article.Title
-	response.Article.Description = article.Description
-	response.Article.Body = article.Body
-	response.Article.TagList = tags
-	response.Article.CreatedAt = article.CreatedAt.Format("2006-01-02T15:04:05.000Z")
-	response.Article.UpdatedAt = article.UpdatedAt.Format("2006-01-02T15:04:05.000Z")
-	response.Article.Favorited = favorited
-	response.Article.FavoritesCount = article.FavoritesCount
-	response.Article.Author.Username = author.Username
-	response.Article.Author.Bio = author.Bio
-	response.Article.Author.Image = author.Image
-	response.Article.Author.Following = following
+	for _, article := range articles {
+		author, ok := authors[article.AuthorID]
+		if !ok {
+			return nil, errors.New("author not found")
+		}
 
-	return response, nil
+		// Build response
+		response := dto.ArticleResponse{}
+		response.Article.Slug = article.Slug
+		response.Article.Title = article.Title
+		response.Article.Description = article.Description
+		response.Article.Body = article.Body
+		response.Article.BodyHTML = renderedBody(article.Body, article.BodyHTML)
+
+		// Dữ liệu trước khi lưu meta được tính khi đọc
+		meta := article.Meta
+		if meta == nil {
+			computed := articleMeta(article.Body)
+			meta = &computed
+		}
+		response.Article.WordCount = meta.WordCount
+		response.Article.ReadingTime = meta.ReadingTime
+		response.Article.Excerpt = article.Description
+		if response.Article.Excerpt == "" {
+			response.Article.Excerpt = meta.Excerpt
+		}
+		response.Article.TableOfContents = make([]dto.TOCEntry, 0, len(meta.TableOfContents))
+		for _, entry := range meta.TableOfContents {
+			response.Article.TableOfContents = append(response.Article.TableOfContents, dto.TOCEntry{
+				Level: entry.Level,
+				Text:  entry.Text,
+				ID:    entry.ID,
+			})
+		}
+		response.Article.TagList = tags[article.ID]
+		response.Article.Status = article.Status
+		if article.PublishedAt != nil {
+			publishedAt := article.PublishedAt.Format("2006-01-02T15:04:05.000Z")
+			response.Article.PublishedAt = &publishedAt
+		}
+		if article.PublishAt != nil {
+			publishAt := article.PublishAt.Format("2006-01-02T15:04:05.000Z")
+			response.Article.PublishAt = &publishAt
+		}
+		response.Article.CreatedAt = article.CreatedAt.Format("2006-01-02T15:04:05.000Z")
+		response.Article.UpdatedAt = article.UpdatedAt.Format("2006-01-02T15:04:05.000Z")
+		response.Article.Favorited = favorited[article.ID]
+		response.Article.FavoritesCount = article.FavoritesCount
+		response.Article.CommentsCount = commentsCounts[article.ID]
+		response.Article.Author.Username = author.Username
+		response.Article.Author.Bio = author.Bio
+		response.Article.Author.Image = author.Image
+		response.Article.Author.Following = following[article.AuthorID] && *currentUserID != article.AuthorID
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
 }
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"fmt"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryLatency giả lập thời gian một round trip tới database cho benchmark
+const queryLatency = 50 * time.Microsecond
+
+// queryCounter đếm số lần gọi repository (mỗi lần tương đương một query MySQL)
+// và giả lập độ trễ round trip nếu latency > 0
+type queryCounter struct {
+	count   int64
+	latency time.Duration
+}
+
+func (c *queryCounter) hit() {
+	atomic.AddInt64(&c.count, 1)
+	if c.latency > 0 {
+		time.Sleep(c.latency)
+	}
+}
+
+func (c *queryCounter) reset() int64 {
+	return atomic.SwapInt64(&c.count, 0)
+}
+
+// countingArticleRepository đếm các query đọc dùng khi list articles
+type countingArticleRepository struct {
+	repositories.ArticleRepository
+	counter *queryCounter
+}
+
+func (r *countingArticleRepository) List(filter repositories.ArticleFilter, after *repositories.Cursor, limit, offset int) ([]*models.Article, error) {
+	r.counter.hit()
+	return r.ArticleRepository.List(filter, after, limit, offset)
+}
+
+func (r *countingArticleRepository) Count(filter repositories.ArticleFilter) (int, error) {
+	r.counter.hit()
+	return r.ArticleRepository.Count(filter)
+}
+
+func (r *countingArticleRepository) Feed(currentUserID int, after *repositories.Cursor, limit, offset int) ([]*models.Article, error) {
+	r.counter.hit()
+	return r.ArticleRepository.Feed(currentUserID, after, limit, offset)
+}
+
+func (r *countingArticleRepository) FeedCount(currentUserID int) (int, error) {
+	r.counter.hit()
+	return r.ArticleRepository.FeedCount(currentUserID)
+}
+
+func (r *countingArticleRepository) GetByID(id int) (*models.Article, error) {
+	r.counter.hit()
+	return r.ArticleRepository.GetByID(id)
+}
+
+func (r *countingArticleRepository) GetTagsByArticleID(articleID int) ([]string, error) {
+	r.counter.hit()
+	return r.ArticleRepository.GetTagsByArticleID(articleID)
+}
+
+func (r *countingArticleRepository) GetTagsByArticleIDs(articleIDs []int) (map[int][]string, error) {
+	r.counter.hit()
+	return r.ArticleRepository.GetTagsByArticleIDs(articleIDs)
+}
+
+func (r *countingArticleRepository) IsFavorited(userID, articleID int) (bool, error) {
+	r.counter.hit()
+	return r.ArticleRepository.IsFavorited(userID, articleID)
+}
+
+func (r *countingArticleRepository) GetFavoritedArticleIDs(userID int, articleIDs []int) (map[int]bool, error) {
+	r.counter.hit()
+	return r.ArticleRepository.GetFavoritedArticleIDs(userID, articleIDs)
+}
+
+func (r *countingArticleRepository) GetCommentsCounts(articleIDs []int) (map[int]int, error) {
+	r.counter.hit()
+	return r.ArticleRepository.GetCommentsCounts(articleIDs)
+}
+
+// countingUserRepository đếm các query lấy user
+type countingUserRepository struct {
+	repositories.UserRepository
+	counter *queryCounter
+}
+
+func (r *countingUserRepository) GetByID(id int) (*models.User, error) {
+	r.counter.hit()
+	return r.UserRepository.GetByID(id)
+}
+
+func (r *countingUserRepository) GetByIDs(ids []int) (map[int]*models.User, error) {
+	r.counter.hit()
+	return r.UserRepository.GetByIDs(ids)
+}
+
+// countingFollowRepository đếm các query kiểm tra follow
+type countingFollowRepository struct {
+	repositories.FollowRepository
+	counter *queryCounter
+}
+
+func (r *countingFollowRepository) IsFollowing(followerID, followingID int) (bool, error) {
+	r.counter.hit()
+	return r.FollowRepository.IsFollowing(followerID, followingID)
+}
+
+func (r *countingFollowRepository) GetFollowingIDs(followerID int, followingIDs []int) (map[int]bool, error) {
+	r.counter.hit()
+	return r.FollowRepository.GetFollowingIDs(followerID, followingIDs)
+}
+
+// newCountingArticleService tạo ArticleService với numArticles articles của numAuthors authors
+// Reader follow tất cả authors và favorite một nửa số articles. Trả về service, counter và ID của reader.
+func newCountingArticleService(t testing.TB, numAuthors, numArticles int) (*ArticleService, *queryCounter, int) {
+	repos := repositories.NewMemoryRepositories()
+	setup := NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, nil)
+
+	reader, err := repos.User.Create("reader", "reader@example.com", "hash")
+	require.NoError(t, err)
+
+	authorIDs := make([]int, numAuthors)
+	for i := range authorIDs {
+		author, err := repos.User.Create(fmt.Sprintf("author%d", i), fmt.Sprintf("author%d@example.com", i), "hash")
+		require.NoError(t, err)
+		authorIDs[i] = author.ID
+		require.NoError(t, repos.Follow.Follow(reader.ID, author.ID))
+	}
+
+	for i := 0; i < numArticles; i++ {
+		created, err := setup.CreateArticle(authorIDs[i%numAuthors], newCreateArticleRequest(fmt.Sprintf("Article %d", i), "go", fmt.Sprintf("tag%d", i%5)))
+		require.NoError(t, err)
+		if i%2 == 0 {
+			_, err = setup.FavoriteArticle(created.Article.Slug, reader.ID)
+			require.NoError(t, err)
+		}
+	}
+
+	counter := &queryCounter{}
+	service := NewArticleService(
+		repos.UnitOfWork,
+		&countingArticleRepository{ArticleRepository: repos.Article, counter: counter},
+		repos.Tag,
+		&countingUserRepository{UserRepository: repos.User, counter: counter},
+		&countingFollowRepository{FollowRepository: repos.Follow, counter: counter},
+		nil,
+	)
+	return service, counter, reader.ID
+}
+
+// TestArticleService_ListQueryCount kiểm tra số query khi list/feed không tăng theo số articles
+func TestArticleService_ListQueryCount(t *testing.T) {
+	service, counter, readerID := newCountingArticleService(t, 10, 100)
+
+	for _, limit := range []int{1, 20, 100} {
+		list, err := service.ListArticles(dto.ArticleListQuery{}, "", limit, 0, &readerID)
+		require.NoError(t, err)
+		assert.Len(t, list.Articles, limit)
+		// List + Count + authors + tags + comments count + favorited + following
+		assert.Equal(t, int64(7), counter.reset(), "list limit=%d", limit)
+
+		feed, err := service.FeedArticles(readerID, "", limit, 0)
+		require.NoError(t, err)
+		assert.Len(t, feed.Articles, limit)
+		assert.Equal(t, int64(7), counter.reset(), "feed limit=%d", limit)
+	}
+
+	// Không đăng nhập thì không cần query favorited/following
+	_, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), counter.reset())
+
+	// Dữ liệu load theo lô phải khớp với từng article
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 100, 0, &readerID)
+	require.NoError(t, err)
+	for _, article := range list.Articles {
+		single, err := service.GetArticle(article.Article.Slug, &readerID)
+		require.NoError(t, err)
+		assert.Equal(t, *single, article)
+	}
+}
+
+// benchmarkListing chạy list/feed với độ trễ query giả lập và báo cáo số query mỗi request
+// Trước khi load theo lô, mỗi article tốn 5 query (article, author, tags, favorited, following)
+// nên limit=100 mất khoảng 500 round trip; giờ cố định 7 query cho mọi limit.
+func benchmarkListing(b *testing.B, list func(service *ArticleService, readerID, limit int) error) {
+	for _, limit := range []int{20, 100} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			service, counter, readerID := newCountingArticleService(b, 10, limit)
+			counter.latency = queryLatency
+			counter.reset()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := list(service, readerID, limit); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(counter.reset())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+// BenchmarkArticleService_ListArticles đo GET /api/articles
+func BenchmarkArticleService_ListArticles(b *testing.B) {
+	benchmarkListing(b, func(service *ArticleService, readerID, limit int) error {
+		_, err := service.ListArticles(dto.ArticleListQuery{}, "", limit, 0, &readerID)
+		return err
+	})
+}
+
+// BenchmarkArticleService_FeedArticles đo GET /api/articles/feed
+func BenchmarkArticleService_FeedArticles(b *testing.B) {
+	benchmarkListing(b, func(service *ArticleService, readerID, limit int) error {
+		_, err := service.FeedArticles(readerID, "", limit, 0)
+		return err
+	})
+}
@@ -0,0 +1,713 @@
+package services
+
+import (
+	"errors"
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestArticleService tạo ArticleService với in-memory repositories
+func newTestArticleService() (*ArticleService, *repositories.Repositories) {
+	repos := repositories.NewMemoryRepositories()
+	return NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, nil), repos
+}
+
+// newCreateArticleRequest helper tạo CreateArticleRequest
+func newCreateArticleRequest(title string, tags ...string) dto.CreateArticleRequest {
+	var req dto.CreateArticleRequest
+	req.Article.Title = title
+	req.Article.Description = "description"
+	req.Article.Body = "body"
+	req.Article.TagList = tags
+	return req
+}
+
+// TestArticleService_CreateAndGet kiểm tra tạo article và lấy lại theo slug
+func TestArticleService_CreateAndGet(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, err := repos.User.Create("author", "author@example.com", "hash")
+	require.NoError(t, err)
+
+	created, err := service.CreateArticle(author.ID, newCreateArticleRequest("Hello World", "go", "gin"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello-world", created.Article.Slug)
+	assert.Equal(t, []string{"gin", "go"}, created.Article.TagList)
+	assert.Equal(t, "author", created.Article.Author.Username)
+
+	// Cùng title phải sinh slug khác
+	second, err := service.CreateArticle(author.ID, newCreateArticleRequest("Hello World"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello-world-1", second.Article.Slug)
+
+	got, err := service.GetArticle("hello-world", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello World", got.Article.Title)
+
+	_, err = service.GetArticle("missing", nil)
+	assert.EqualError(t, err, "article not found")
+}
+
+// TestArticleService_UpdatePermission kiểm tra chỉ author mới update được article
+func TestArticleService_UpdatePermission(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	other, _ := repos.User.Create("other", "other@example.com", "hash")
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Original"))
+	require.NoError(t, err)
+
+	newTitle := "Renamed"
+	var req dto.UpdateArticleRequest
+	req.Article.Title = &newTitle
+
+	_, err = service.UpdateArticle("original", other.ID, req)
+	assert.EqualError(t, err, "permission denied")
+
+	updated, err := service.UpdateArticle("original", author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Article.Slug)
+	assert.Equal(t, "Renamed", updated.Article.Title)
+}
+
+// TestArticleService_SlugHistory kiểm tra slug cũ vẫn resolve được sau khi đổi title
+func TestArticleService_SlugHistory(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Original"))
+	require.NoError(t, err)
+
+	rename := func(slug, title string) string {
+		var req dto.UpdateArticleRequest
+		req.Article.Title = &title
+		updated, err := service.UpdateArticle(slug, author.ID, req)
+		require.NoError(t, err)
+		return updated.Article.Slug
+	}
+
+	assert.Equal(t, "renamed", rename("original", "Renamed"))
+
+	// Slug cũ trả về article với slug hiện tại
+	got, err := service.GetArticle("original", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", got.Article.Slug)
+
+	canonical, moved, err := service.ResolveMovedSlug("original")
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, "renamed", canonical)
+
+	_, moved, err = service.ResolveMovedSlug("renamed")
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	// Article khác không được lấy slug cũ
+	other, err := service.CreateArticle(author.ID, newCreateArticleRequest("Original"))
+	require.NoError(t, err)
+	assert.Equal(t, "original-1", other.Article.Slug)
+
+	// Đổi lại title cũ thì dùng lại slug cũ của chính nó
+	assert.Equal(t, "original", rename("renamed", "Original"))
+	_, moved, err = service.ResolveMovedSlug("original")
+	require.NoError(t, err)
+	assert.False(t, moved)
+	canonical, moved, _ = service.ResolveMovedSlug("renamed")
+	assert.True(t, moved)
+	assert.Equal(t, "original", canonical)
+
+	// Thao tác ghi qua slug cũ vẫn áp dụng lên article hiện tại
+	favorited, err := service.FavoriteArticle("renamed", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "original", favorited.Article.Slug)
+	assert.Equal(t, 1, favorited.Article.FavoritesCount)
+}
+
+// TestArticleService_FavoriteAndFeed kiểm tra favorite count và feed theo follow
+func TestArticleService_FavoriteAndFeed(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Favorite Me"))
+	require.NoError(t, err)
+
+	favorited, err := service.FavoriteArticle("favorite-me", reader.ID)
+	require.NoError(t, err)
+	assert.True(t, favorited.Article.Favorited)
+	assert.Equal(t, 1, favorited.Article.FavoritesCount)
+
+	// Favorite lần 2 không tăng count
+	favorited, err = service.FavoriteArticle("favorite-me", reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, favorited.Article.FavoritesCount)
+
+	feed, err := service.FeedArticles(reader.ID, "", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, feed.ArticlesCount)
+
+	require.NoError(t, repos.Follow.Follow(reader.ID, author.ID))
+	feed, err = service.FeedArticles(reader.ID, "", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, feed.ArticlesCount)
+	assert.True(t, feed.Articles[0].Article.Author.Following)
+	assert.True(t, feed.Articles[0].Article.Favorited)
+
+	list, err := service.ListArticles(dto.ArticleListQuery{Favorited: "reader"}, "", 20, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.ArticlesCount)
+}
+
+// failingTagRepository giả lập lỗi khi tạo tag
+type failingTagRepository struct {
+	repositories.TagRepository
+}
+
+// GetOrCreate luôn trả về lỗi
+func (r failingTagRepository) GetOrCreate(name string) (*models.Tag, error) {
+	return nil, errors.New("tag storage unavailable")
+}
+
+// TestArticleService_CreateRollbackOnTagFailure kiểm tra article không được tạo khi gắn tags lỗi
+func TestArticleService_CreateRollbackOnTagFailure(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	// Repositories trong transaction lấy từ cùng bundle nên tag repository lỗi được dùng trong UnitOfWork
+	repos.Tag = failingTagRepository{repos.Tag}
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Broken Tags", "go"))
+	assert.Error(t, err)
+
+	exists, err := repos.Article.IsSlugExists("broken-tags")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// TestArticleService_UpdateTags kiểm tra thay thế, thêm và bớt tags khi sửa article
+func TestArticleService_UpdateTags(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Tagged", "go", "web"))
+	require.NoError(t, err)
+	_, err = service.CreateArticle(author.ID, newCreateArticleRequest("Other", "web"))
+	require.NoError(t, err)
+	goTag, _ := repos.Tag.GetOrCreate("go")
+	webTag, _ := repos.Tag.GetOrCreate("web")
+
+	// Thay thế toàn bộ, tên trùng chỉ gắn một lần
+	var update dto.UpdateArticleRequest
+	update.Article.TagList = &[]string{"rust", "web", "rust"}
+	updated, err := service.UpdateArticle("tagged", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rust", "web"}, updated.Article.TagList)
+
+	// Thêm và bớt từng tag
+	update = dto.UpdateArticleRequest{}
+	update.Article.AddTags = []string{"api"}
+	update.Article.RemoveTags = []string{"web"}
+	updated, err = service.UpdateArticle("tagged", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "rust"}, updated.Article.TagList)
+
+	// Không gửi tags thì giữ nguyên
+	title := "Tagged"
+	update = dto.UpdateArticleRequest{}
+	update.Article.Title = &title
+	updated, err = service.UpdateArticle("tagged", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "rust"}, updated.Article.TagList)
+
+	// "go" không còn article nào dùng nên bị xóa, "web" vẫn được "other" dùng
+	assert.False(t, tagDeleted(t, repos, "web", webTag.ID))
+	assert.True(t, tagDeleted(t, repos, "go", goTag.ID))
+
+	// tagList rỗng bỏ hết tags
+	rustTag, _ := repos.Tag.GetOrCreate("rust")
+	update = dto.UpdateArticleRequest{}
+	update.Article.TagList = &[]string{}
+	updated, err = service.UpdateArticle("tagged", author.ID, update)
+	require.NoError(t, err)
+	assert.Empty(t, updated.Article.TagList)
+	assert.True(t, tagDeleted(t, repos, "rust", rustTag.ID))
+}
+
+// TestArticleService_UpdateTagsRollback kiểm tra tags không đổi khi sửa article lỗi
+func TestArticleService_UpdateTagsRollback(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Tagged", "go"))
+	require.NoError(t, err)
+
+	// Tạo tag mới lỗi thì cả lần sửa bị rollback
+	repos.Tag = failingTagRepository{repos.Tag}
+
+	title := "Renamed"
+	var update dto.UpdateArticleRequest
+	update.Article.Title = &title
+	update.Article.AddTags = []string{"web"}
+	_, err = service.UpdateArticle("tagged", author.ID, update)
+	assert.Error(t, err)
+
+	article, err := service.GetArticle("tagged", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Tagged", article.Article.Title)
+	assert.Equal(t, []string{"go"}, article.Article.TagList)
+}
+
+// tagDeleted kiểm tra tag name đã bị xóa khỏi bảng tags: GetOrCreate phải tạo tag mới với ID khác
+func tagDeleted(t *testing.T, repos *repositories.Repositories, name string, oldID int) bool {
+	tag, err := repos.Tag.GetOrCreate(name)
+	require.NoError(t, err)
+	return tag.ID != oldID
+}
+
+// TestArticleService_CursorPagination kiểm tra phân trang bằng cursor không trùng/sót khi có article mới
+func TestArticleService_CursorPagination(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	for _, title := range []string{"One", "Two", "Three", "Four", "Five"} {
+		_, err := service.CreateArticle(author.ID, newCreateArticleRequest(title))
+		require.NoError(t, err)
+	}
+
+	var slugs []string
+	cursor := ""
+	for page := 0; ; page++ {
+		list, err := service.ListArticles(dto.ArticleListQuery{}, cursor, 2, 0, nil)
+		require.NoError(t, err)
+		for _, article := range list.Articles {
+			slugs = append(slugs, article.Article.Slug)
+		}
+
+		// Article mới được tạo trong lúc đang cuộn không làm lệch các trang sau
+		if page == 0 {
+			_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Six"))
+			require.NoError(t, err)
+		}
+
+		if list.NextCursor == nil {
+			break
+		}
+		cursor = *list.NextCursor
+	}
+	assert.Equal(t, []string{"five", "four", "three", "two", "one"}, slugs)
+
+	// Offset vẫn hoạt động như cũ và cũng trả về nextCursor
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 2, 3, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Articles, 2)
+	assert.Equal(t, "three", list.Articles[0].Article.Slug)
+	require.NotNil(t, list.NextCursor)
+
+	_, err = service.ListArticles(dto.ArticleListQuery{}, "not-a-cursor", 2, 0, nil)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"cursor": {"is invalid"}}, appErr.Fields)
+}
+
+// TestArticleService_Search kiểm tra tìm kiếm theo relevance, filter và snippet
+func TestArticleService_Search(t *testing.T) {
+	service, repos := newTestArticleService()
+	alice, _ := repos.User.Create("alice", "alice@example.com", "hash")
+	bob, _ := repos.User.Create("bob", "bob@example.com", "hash")
+
+	create := func(authorID int, title, body string, tags ...string) {
+		req := newCreateArticleRequest(title, tags...)
+		req.Article.Body = body
+		_, err := service.CreateArticle(authorID, req)
+		require.NoError(t, err)
+	}
+	create(alice.ID, "Cooking pasta", "A recipe that mentions golang once.", "food")
+	create(alice.ID, "Golang concurrency", "Goroutines and channels in golang.", "go")
+	create(bob.ID, "Tin tức thể thao", "Bóng đá hôm nay", "news")
+	create(bob.ID, "Unrelated", "Nothing to see here.")
+
+	// Khớp ở title được xếp trên khớp ở body
+	result, err := service.SearchArticles("golang", dto.ArticleListQuery{}, 20, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.ArticlesCount)
+	require.Len(t, result.Articles, 2)
+	assert.Equal(t, "golang-concurrency", result.Articles[0].Article.Slug)
+	assert.Equal(t, "cooking-pasta", result.Articles[1].Article.Slug)
+	assert.Greater(t, result.Articles[0].Score, result.Articles[1].Score)
+	assert.Equal(t, "Goroutines and channels in <mark>golang</mark>.", result.Articles[0].Snippet)
+
+	// Kết hợp với filter tag/author
+	result, err = service.SearchArticles("golang", dto.ArticleListQuery{Tag: []string{"food"}}, 20, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Articles, 1)
+	assert.Equal(t, "cooking-pasta", result.Articles[0].Article.Slug)
+
+	result, err = service.SearchArticles("golang", dto.ArticleListQuery{Author: []string{"bob"}}, 20, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Articles)
+
+	// Không phân biệt dấu tiếng Việt
+	result, err = service.SearchArticles("the thao", dto.ArticleListQuery{}, 20, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Articles, 1)
+	assert.Equal(t, "Tin tức <mark>thể</mark> <mark>thao</mark>", result.Articles[0].Snippet)
+
+	_, err = service.SearchArticles("  ", dto.ArticleListQuery{}, 20, 0, nil)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"q": {"can't be blank"}}, appErr.Fields)
+
+	// Slug trùng route tĩnh không được dùng
+	created, err := service.CreateArticle(alice.ID, newCreateArticleRequest("Search"))
+	require.NoError(t, err)
+	assert.Equal(t, "search-1", created.Article.Slug)
+}
+
+// TestArticleService_FilterAndSort kiểm tra filter nhiều giá trị và các kiểu sắp xếp
+func TestArticleService_FilterAndSort(t *testing.T) {
+	service, repos := newTestArticleService()
+	alice, _ := repos.User.Create("alice", "alice@example.com", "hash")
+	bob, _ := repos.User.Create("bob", "bob@example.com", "hash")
+	carol, _ := repos.User.Create("carol", "carol@example.com", "hash")
+
+	create := func(authorID int, title string, tags ...string) {
+		_, err := service.CreateArticle(authorID, newCreateArticleRequest(title, tags...))
+		require.NoError(t, err)
+	}
+	create(alice.ID, "Go Web", "go", "web")
+	create(alice.ID, "Go CLI", "go", "cli")
+	create(bob.ID, "Rust Web", "rust", "web")
+	create(carol.ID, "Plain")
+
+	slugs := func(query dto.ArticleListQuery) []string {
+		list, err := service.ListArticles(query, "", 20, 0, nil)
+		require.NoError(t, err)
+		result := []string{}
+		for _, article := range list.Articles {
+			result = append(result, article.Article.Slug)
+		}
+		assert.Equal(t, len(result), list.ArticlesCount)
+		return result
+	}
+
+	// Tags: any (mặc định), all, dạng phân cách bằng dấu phẩy và loại trừ
+	assert.Equal(t, []string{"rust-web", "go-cli", "go-web"}, slugs(dto.ArticleListQuery{Tag: []string{"go", "web"}}))
+	assert.Equal(t, []string{"go-web"}, slugs(dto.ArticleListQuery{Tag: []string{"go,web"}, TagMode: "all"}))
+	assert.Empty(t, slugs(dto.ArticleListQuery{Tag: []string{"go", "missing"}, TagMode: "all"}))
+	assert.Equal(t, []string{"go-cli"}, slugs(dto.ArticleListQuery{Tag: []string{"go"}, ExcludeTag: []string{"web"}}))
+	assert.Equal(t, []string{"plain", "go-cli"}, slugs(dto.ArticleListQuery{ExcludeTag: []string{"web", "rust"}}))
+
+	// Nhiều authors
+	assert.Equal(t, []string{"plain", "rust-web"}, slugs(dto.ArticleListQuery{Author: []string{"bob", "carol"}}))
+	assert.Equal(t, []string{"rust-web"}, slugs(dto.ArticleListQuery{Author: []string{"bob,nobody"}, Tag: []string{"web"}}))
+
+	// Khoảng thời gian tạo, ngày không có giờ của createdBefore tính cả ngày đó
+	today := time.Now().UTC().Format("2006-01-02")
+	assert.Len(t, slugs(dto.ArticleListQuery{CreatedAfter: today, CreatedBefore: today}), 4)
+	assert.Empty(t, slugs(dto.ArticleListQuery{CreatedAfter: time.Now().Add(time.Hour).Format(time.RFC3339)}))
+
+	// Sắp xếp
+	assert.Equal(t, []string{"go-web", "go-cli", "rust-web", "plain"}, slugs(dto.ArticleListQuery{Sort: "oldest"}))
+
+	_, err := service.FavoriteArticle("rust-web", alice.ID)
+	require.NoError(t, err)
+	_, err = service.FavoriteArticle("rust-web", carol.ID)
+	require.NoError(t, err)
+	_, err = service.FavoriteArticle("go-web", carol.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rust-web", "go-web", "plain", "go-cli"}, slugs(dto.ArticleListQuery{Sort: "mostFavorited"}))
+
+	var update dto.UpdateArticleRequest
+	body := "updated"
+	update.Article.Body = &body
+	_, err = service.UpdateArticle("go-cli", alice.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, "go-cli", slugs(dto.ArticleListQuery{Sort: "recentlyUpdated"})[0])
+
+	// Cursor theo sort không phải created_at
+	first, err := service.ListArticles(dto.ArticleListQuery{Sort: "mostFavorited"}, "", 2, 0, nil)
+	require.NoError(t, err)
+	require.NotNil(t, first.NextCursor)
+	second, err := service.ListArticles(dto.ArticleListQuery{Sort: "mostFavorited"}, *first.NextCursor, 2, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, second.Articles, 2)
+	assert.Equal(t, "plain", second.Articles[0].Article.Slug)
+	assert.Equal(t, "go-cli", second.Articles[1].Article.Slug)
+	assert.Nil(t, second.NextCursor)
+
+	// Giá trị không hợp lệ trả về lỗi theo từng field
+	_, err = service.ListArticles(dto.ArticleListQuery{
+		Sort:          "popular",
+		TagMode:       "some",
+		CreatedAfter:  "2024-02-01",
+		CreatedBefore: "2024-01-01",
+	}, "", 20, 0, nil)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{
+		"sort":          {"is invalid"},
+		"tagMode":       {"is invalid"},
+		"createdBefore": {"must be after createdAfter"},
+	}, appErr.Fields)
+
+	_, err = service.ListArticles(dto.ArticleListQuery{CreatedAfter: "yesterday"}, "", 20, 0, nil)
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"createdAfter": {"is invalid"}}, appErr.Fields)
+}
+
+// TestArticleService_Drafts kiểm tra draft chỉ author thấy và publish/unpublish/archive
+func TestArticleService_Drafts(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	tagService := NewTagService(repos.UnitOfWork, repos.Tag, repos.User, 0)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	require.NoError(t, repos.Follow.Follow(reader.ID, author.ID))
+
+	req := newCreateArticleRequest("Secret Plan", "secret")
+	req.Article.Status = models.ArticleStatusDraft
+	draft, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusDraft, draft.Article.Status)
+	assert.Nil(t, draft.Article.PublishedAt)
+
+	// Mặc định vẫn publish ngay như RealWorld spec
+	public, err := service.CreateArticle(author.ID, newCreateArticleRequest("Public", "public"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, public.Article.Status)
+	assert.NotNil(t, public.Article.PublishedAt)
+
+	// Chỉ author xem được draft
+	_, err = service.GetArticle("secret-plan", &author.ID)
+	assert.NoError(t, err)
+	_, err = service.GetArticle("secret-plan", &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	_, err = service.GetArticle("secret-plan", nil)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	_, err = service.FavoriteArticle("secret-plan", reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	_, err = commentService.GetComments("secret-plan", dto.CommentListQuery{}, "", 0, &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+
+	// Draft không có trong list, feed, search và tags
+	visible := func() []string {
+		list, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, &author.ID)
+		require.NoError(t, err)
+		feed, err := service.FeedArticles(reader.ID, "", 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, list.ArticlesCount, feed.ArticlesCount)
+
+		var slugs []string
+		for _, article := range list.Articles {
+			slugs = append(slugs, article.Article.Slug)
+		}
+		return slugs
+	}
+	assert.Equal(t, []string{"public"}, visible())
+	search, err := service.SearchArticles("plan", dto.ArticleListQuery{}, 20, 0, nil)
+	require.NoError(t, err)
+	assert.Zero(t, search.ArticlesCount)
+	tags, err := tagService.GetAllTags(dto.TagListQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"public"}, tags.Tags)
+
+	// Chỉ author được publish
+	_, err = service.PublishArticle("secret-plan", reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	_, err = service.UnpublishArticle("public", reader.ID)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	published, err := service.PublishArticle("secret-plan", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, published.Article.Status)
+	require.NotNil(t, published.Article.PublishedAt)
+	assert.Equal(t, []string{"secret-plan", "public"}, visible())
+	search, err = service.SearchArticles("plan", dto.ArticleListQuery{}, 20, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, search.ArticlesCount)
+	tags, err = tagService.GetAllTags(dto.TagListQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"public", "secret"}, tags.Tags)
+
+	// Unpublish rồi publish lại giữ nguyên published_at
+	unpublished, err := service.UnpublishArticle("secret-plan", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusDraft, unpublished.Article.Status)
+	assert.Equal(t, []string{"public"}, visible())
+	republished, err := service.PublishArticle("secret-plan", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, published.Article.PublishedAt, republished.Article.PublishedAt)
+
+	archived, err := service.ArchiveArticle("secret-plan", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusArchived, archived.Article.Status)
+	assert.Equal(t, []string{"public"}, visible())
+	_, err = service.GetArticle("secret-plan", &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+}
+
+// TestArticleService_ScheduledPublish kiểm tra hẹn giờ publish và worker publish articles tới hạn
+func TestArticleService_ScheduledPublish(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+
+	publishAt := time.Now().Add(time.Hour)
+	req := newCreateArticleRequest("Tomorrow News")
+	req.Article.PublishAt = &publishAt
+	scheduled, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusScheduled, scheduled.Article.Status)
+	require.NotNil(t, scheduled.Article.PublishAt)
+	assert.Nil(t, scheduled.Article.PublishedAt)
+
+	// Chưa tới giờ: chưa hiện với người khác, worker không publish
+	_, err = service.GetArticle("tomorrow-news", &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	published, err := service.PublishDueArticles(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	// Tới giờ: worker publish, published_at là giờ hẹn
+	published, err = service.PublishDueArticles(publishAt.Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	article, err := service.GetArticle("tomorrow-news", &reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, article.Article.Status)
+	assert.Nil(t, article.Article.PublishAt)
+	require.NotNil(t, article.Article.PublishedAt)
+	assert.Equal(t, publishAt.Format("2006-01-02T15:04:05.000Z"), *article.Article.PublishedAt)
+
+	// Hẹn giờ cho draft có sẵn, unpublish hủy lịch hẹn
+	draftReq := newCreateArticleRequest("Later")
+	draftReq.Article.Status = models.ArticleStatusDraft
+	_, err = service.CreateArticle(author.ID, draftReq)
+	require.NoError(t, err)
+	var schedule dto.ScheduleArticleRequest
+	schedule.Article.PublishAt = publishAt
+	_, err = service.ScheduleArticle("later", reader.ID, schedule)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	later, err := service.ScheduleArticle("later", author.ID, schedule)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusScheduled, later.Article.Status)
+	later, err = service.UnpublishArticle("later", author.ID)
+	require.NoError(t, err)
+	assert.Nil(t, later.Article.PublishAt)
+	published, err = service.PublishDueArticles(publishAt.Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	// Giờ hẹn phải ở tương lai và article chưa publish
+	past := time.Now().Add(-time.Minute)
+	req.Article.PublishAt = &past
+	_, err = service.CreateArticle(author.ID, req)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"publishAt": {"must be in the future"}}, appErr.Fields)
+
+	_, err = service.ScheduleArticle("tomorrow-news", author.ID, schedule)
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"publishAt": {"can't be set on a published article"}}, appErr.Fields)
+}
+
+// TestArticleService_BodyHTML kiểm tra body được render Markdown khi ghi và trả về trong bodyHtml
+func TestArticleService_BodyHTML(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	req := newCreateArticleRequest("Markdown")
+	req.Article.Body = "# Hello\n\n<script>alert(1)</script>"
+	created, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	// Khối raw HTML bị bỏ hẳn
+	assert.Equal(t, "<h1 id=\"hello\">Hello</h1>\n\n", created.Article.BodyHTML)
+
+	// HTML được lưu cùng article, list không phải render lại
+	stored, err := repos.Article.GetBySlug("markdown")
+	require.NoError(t, err)
+	assert.Equal(t, created.Article.BodyHTML, stored.BodyHTML)
+
+	body := "*updated*"
+	var update dto.UpdateArticleRequest
+	update.Article.Body = &body
+	updated, err := service.UpdateArticle("markdown", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><em>updated</em></p>\n", updated.Article.BodyHTML)
+
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Articles, 1)
+	assert.Equal(t, "<p><em>updated</em></p>\n", list.Articles[0].Article.BodyHTML)
+
+	// Dữ liệu cũ chưa có body_html được render khi đọc
+	_, err = repos.Article.Create("legacy", "Legacy", "desc", "**old**", "", author.ID, models.ArticleStatusPublished)
+	require.NoError(t, err)
+	legacy, err := service.GetArticle("legacy", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "<p><strong>old</strong></p>\n", legacy.Article.BodyHTML)
+
+	var commentReq dto.CreateCommentRequest
+	commentReq.Comment.Body = "see https://example.com"
+	comment, err := commentService.AddComment("markdown", author.ID, commentReq)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>see <a href=\"https://example.com\" rel=\"nofollow\">https://example.com</a></p>\n", comment.Comment.BodyHTML)
+}
+
+// TestArticleService_Meta kiểm tra số từ, thời gian đọc, excerpt và mục lục được tính khi ghi và trả về
+func TestArticleService_Meta(t *testing.T) {
+	service, repos := newTestArticleService()
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	req := newCreateArticleRequest("Guide")
+	req.Article.Description = ""
+	req.Article.Body = "# Setup\n\nInstall **Go** first.\n\n## Run it\n\nThen run."
+	created, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 8, created.Article.WordCount)
+	assert.Equal(t, 1, created.Article.ReadingTime)
+	// Không có description thì excerpt lấy từ body
+	assert.Equal(t, "Install Go first. Then run.", created.Article.Excerpt)
+	assert.Equal(t, []dto.TOCEntry{
+		{Level: 1, Text: "Setup", ID: "setup"},
+		{Level: 2, Text: "Run it", ID: "run-it"},
+	}, created.Article.TableOfContents)
+	assert.Contains(t, created.Article.BodyHTML, `<h2 id="run-it">`)
+
+	// Meta được lưu cùng article
+	stored, err := repos.Article.GetBySlug("guide")
+	require.NoError(t, err)
+	require.NotNil(t, stored.Meta)
+	assert.Equal(t, 8, stored.Meta.WordCount)
+
+	// Sửa body thì meta được tính lại, description có thì excerpt là description
+	body := strings.Repeat("word ", 250)
+	description := "Short summary"
+	var update dto.UpdateArticleRequest
+	update.Article.Body = &body
+	update.Article.Description = &description
+	updated, err := service.UpdateArticle("guide", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, 250, updated.Article.WordCount)
+	assert.Equal(t, 2, updated.Article.ReadingTime)
+	assert.Equal(t, "Short summary", updated.Article.Excerpt)
+	assert.Empty(t, updated.Article.TableOfContents)
+
+	// Dữ liệu cũ chưa có meta được tính khi đọc
+	_, err = repos.Article.Create("legacy", "Legacy", "", "## Old\n\nold text", "", author.ID, models.ArticleStatusPublished)
+	require.NoError(t, err)
+	legacy, err := service.GetArticle("legacy", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, legacy.Article.WordCount)
+	assert.Equal(t, "old text", legacy.Article.Excerpt)
+	assert.Equal(t, []dto.TOCEntry{{Level: 2, Text: "Old", ID: "old"}}, legacy.Article.TableOfContents)
+}
@@ -1,7 +1,7 @@
 package services
 
 import (
-	"errors"
+	"news/apperrors"
 	"news/config"
 	"news/dto"
 	"news/repositories"
@@ -10,36 +10,23 @@ import (
 
 // AuthService chứa business logic cho authentication
 type AuthService struct {
-	userRepo *repositories.UserRepository
+	userRepo repositories.UserRepository
 }
 
 // NewAuthService tạo instance mới của AuthService
-func NewAuthService() *AuthService {
+func NewAuthService(userRepo repositories.UserRepository) *AuthService {
 	return &AuthService{
-		userRepo: repositories.NewUserRepository(),
+		userRepo: userRepo,
 	}
 }
 
 // Register đăng ký user mới
 // Trả về UserResponse với JWT token
 func (s *AuthService) Register(req dto.RegisterRequest) (*dto.UserResponse, error) {
-	// Kiểm tra email đã tồn tại chưa
-	existingUser, err := s.userRepo.GetByEmail(req.User.Email)
-	if err != nil {
-		return nil, err
-	}
-	if existingUser != nil {
-		return nil, errors.New("email already exists")
-	}
-
-	// Kiểm tra username đã tồn tại chưa
-	existingUser, err = s.userRepo.GetByUsername(req.User.Username)
-	if err != nil {
+	// Kiểm tra email và username đã tồn tại chưa (báo lỗi cho tất cả field bị trùng)
+	if err := s.checkTaken(&req.User.Email, &req.User.Username, 0); err != nil {
 		return nil, err
 	}
-	if existingUser != nil {
-		return nil, errors.New("username already exists")
-	}
 
 	// Hash password
 	passwordHash, err := utils.HashPassword(req.User.Password)
@@ -50,6 +37,12 @@ func (s *AuthService) Register(req dto.RegisterRequest) (*dto.UserResponse, erro
 	// Tạo user mới
 	user, err := s.userRepo.Create(req.User.Username, req.User.Email, passwordHash)
 	if err != nil {
+		// Request khác vừa đăng ký cùng email/username giữa lúc kiểm tra và insert
+		if repositories.IsDuplicateEntry(err) {
+			if takenErr := s.checkTaken(&req.User.Email, &req.User.Username, 0); takenErr != nil {
+				return nil, takenErr
+			}
+		}
 		return nil, err
 	}
 
@@ -80,12 +73,12 @@ func (s *AuthService) Login(req dto.LoginRequest) (*dto.UserResponse, error) {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Kiểm tra password
 	if !utils.CheckPassword(req.User.Password, user.PasswordHash) {
-		return nil, errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Tạo JWT token
@@ -114,7 +107,7 @@ func (s *AuthService) GetCurrentUser(userID int) (*dto.UserResponse, error) {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Tạo JWT token mới (refresh token)
@@ -143,36 +136,19 @@ func (s *AuthService) UpdateUser(userID int, req dto.UpdateUserRequest) (*dto.Us
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Chuẩn bị các giá trị để update
 	var email, username, passwordHash *string
 	var bio, image *string
 
-	if req.User.Email != nil {
-		// Kiểm tra email mới có trùng với email của user khác không
-		existingUser, err := s.userRepo.GetByEmail(*req.User.Email)
-		if err != nil {
-			return nil, err
-		}
-		if existingUser != nil && existingUser.ID != userID {
-			return nil, errors.New("email already exists")
-		}
-		email = req.User.Email
-	}
-
-	if req.User.Username != nil {
-		// Kiểm tra username mới có trùng với username của user khác không
-		existingUser, err := s.userRepo.GetByUsername(*req.User.Username)
-		if err != nil {
-			return nil, err
-		}
-		if existingUser != nil && existingUser.ID != userID {
-			return nil, errors.New("username already exists")
-		}
-		username = req.User.Username
+	// Kiểm tra email/username mới có trùng với user khác không
+	if err := s.checkTaken(req.User.Email, req.User.Username, userID); err != nil {
+		return nil, err
 	}
+	email = req.User.Email
+	username = req.User.Username
 
 	if req.User.Password != nil {
 		// Hash password mới
@@ -194,6 +170,11 @@ func (s *AuthService) UpdateUser(userID int, req dto.UpdateUserRequest) (*dto.Us
 	// Update user
 	updatedUser, err := s.userRepo.Update(userID, email, username, passwordHash, bio, image)
 	if err != nil {
+		if repositories.IsDuplicateEntry(err) {
+			if takenErr := s.checkTaken(email, username, userID); takenErr != nil {
+				return nil, takenErr
+			}
+		}
 		return nil, err
 	}
 
@@ -214,3 +195,34 @@ func (s *AuthService) UpdateUser(userID int, req dto.UpdateUserRequest) (*dto.Us
 
 	return response, nil
 }
+
+// checkTaken kiểm tra email/username (nếu khác nil) đã được user khác (ID khác userID) sử dụng chưa
+// Trả về lỗi validation liệt kê mọi field bị trùng, ví dụ {"email": ["has already been taken"]}
+func (s *AuthService) checkTaken(email, username *string, userID int) error {
+	fields := map[string][]string{}
+
+	if email != nil {
+		existingUser, err := s.userRepo.GetByEmail(*email)
+		if err != nil {
+			return err
+		}
+		if existingUser != nil && existingUser.ID != userID {
+			fields["email"] = []string{MsgAlreadyTaken}
+		}
+	}
+
+	if username != nil {
+		existingUser, err := s.userRepo.GetByUsername(*username)
+		if err != nil {
+			return err
+		}
+		if existingUser != nil && existingUser.ID != userID {
+			fields["username"] = []string{MsgAlreadyTaken}
+		}
+	}
+
+	if len(fields) > 0 {
+		return apperrors.Validation(fields)
+	}
+	return nil
+}
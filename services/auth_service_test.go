@@ -1,7 +1,9 @@
 package services
 
 import (
+	"news/apperrors"
 	"news/dto"
+	"news/repositories"
 	"news/utils"
 	"testing"
 
@@ -115,3 +117,71 @@ func TestRegisterRequestValidation(t *testing.T) {
 	}
 }
 
+
+// TestAuthService_RegisterAndLogin kiểm tra register/login với in-memory repository
+func TestAuthService_RegisterAndLogin(t *testing.T) {
+	service := NewAuthService(repositories.NewMemoryRepositories().User)
+
+	var registerReq dto.RegisterRequest
+	registerReq.User.Username = "testuser"
+	registerReq.User.Email = "test@example.com"
+	registerReq.User.Password = "password123"
+
+	registered, err := service.Register(registerReq)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, registered.User.Token)
+
+	// Trùng cả email và username: báo lỗi cho cả 2 field
+	_, err = service.Register(registerReq)
+	appErr := apperrors.As(err)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, apperrors.KindValidation, appErr.Kind)
+		assert.Equal(t, map[string][]string{
+			"email":    {MsgAlreadyTaken},
+			"username": {MsgAlreadyTaken},
+		}, appErr.Fields)
+	}
+
+	// Chỉ trùng username
+	registerReq.User.Email = "other@example.com"
+	_, err = service.Register(registerReq)
+	appErr = apperrors.As(err)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, map[string][]string{"username": {MsgAlreadyTaken}}, appErr.Fields)
+	}
+
+	var loginReq dto.LoginRequest
+	loginReq.User.Email = "test@example.com"
+	loginReq.User.Password = "password123"
+	loggedIn, err := service.Login(loginReq)
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", loggedIn.User.Username)
+
+	loginReq.User.Password = "wrong"
+	_, err = service.Login(loginReq)
+	assert.EqualError(t, err, "invalid email or password")
+}
+
+// TestAuthService_UpdateUserTaken kiểm tra update email trùng user khác trả lỗi theo field
+func TestAuthService_UpdateUserTaken(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	service := NewAuthService(repos.User)
+	first, _ := repos.User.Create("first", "first@example.com", "hash")
+	_, _ = repos.User.Create("second", "second@example.com", "hash")
+
+	var req dto.UpdateUserRequest
+	ownEmail := "first@example.com"
+	req.User.Email = &ownEmail
+
+	// Giữ nguyên email của chính mình không bị tính là trùng
+	_, err := service.UpdateUser(first.ID, req)
+	assert.NoError(t, err)
+
+	takenEmail := "second@example.com"
+	req.User.Email = &takenEmail
+	_, err = service.UpdateUser(first.ID, req)
+	appErr := apperrors.As(err)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, map[string][]string{"email": {MsgAlreadyTaken}}, appErr.Fields)
+	}
+}
@@ -2,41 +2,98 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"news/apperrors"
 	"news/dto"
+	"news/models"
 	"news/repositories"
+	"news/utils"
+	"strings"
+	"time"
 )
 
+// DeletedCommentBody là body hiển thị thay cho comment đã xóa còn replies
+const DeletedCommentBody = "[deleted]"
+
+// HiddenCommentBody là body hiển thị thay cho comment bị ẩn còn replies
+const HiddenCommentBody = "[hidden]"
+
 // CommentService chứa business logic cho comments
 type CommentService struct {
-	commentRepo *repositories.CommentRepository
-	articleRepo *repositories.ArticleRepository
-	userRepo    *repositories.UserRepository
-	followRepo  *repositories.FollowRepository
+	uow         repositories.UnitOfWork
+	commentRepo repositories.CommentRepository
+	articleRepo repositories.ArticleRepository
+	userRepo    repositories.UserRepository
+	followRepo  repositories.FollowRepository
+	maxDepth    int
+	editWindow  time.Duration
+	filter      *ContentFilterPipeline
 }
 
 // NewCommentService tạo instance mới của CommentService
-func NewCommentService() *CommentService {
+// maxDepth là số cấp reply tối đa, 0 là không cho trả lời comment.
+// editWindow là thời gian sau khi đăng mà author còn sửa được comment.
+// filter kiểm tra body trước khi lưu, nil là không kiểm tra.
+func NewCommentService(uow repositories.UnitOfWork, commentRepo repositories.CommentRepository, articleRepo repositories.ArticleRepository, userRepo repositories.UserRepository, followRepo repositories.FollowRepository, maxDepth int, editWindow time.Duration, filter *ContentFilterPipeline) *CommentService {
 	return &CommentService{
-		commentRepo: repositories.NewCommentRepository(),
-		articleRepo: repositories.NewArticleRepository(),
-		userRepo:    repositories.NewUserRepository(),
-		followRepo:  repositories.NewFollowRepository(),
+		uow:         uow,
+		commentRepo: commentRepo,
+		articleRepo: articleRepo,
+		userRepo:    userRepo,
+		followRepo:  followRepo,
+		maxDepth:    maxDepth,
+		editWindow:  editWindow,
+		filter:      filter,
 	}
 }
 
 // AddComment thêm comment vào article
+// req.Comment.ParentID khác nil thì comment là reply của một comment chưa xóa, không bị ẩn trong cùng article.
+// User bị moderator ban không bình luận được. Body bị content filter từ chối thì không lưu,
+// bị giữ lại thì comment được lưu ở trạng thái ẩn và gửi vào hàng đợi kiểm duyệt.
 func (s *CommentService) AddComment(slug string, authorID int, req dto.CreateCommentRequest) (*dto.CommentResponse, error) {
-	// Lấy article theo slug
-	article, err := s.articleRepo.GetBySlug(slug)
+	author, err := s.requireNotBanned(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy article theo slug, draft chỉ author bình luận được
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
+
+	// Lấy comment được trả lời
+	var parent *models.Comment
+	if req.Comment.ParentID != nil {
+		parent, err = s.commentRepo.GetByID(*req.Comment.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.ArticleID != article.ID || parent.IsHidden() {
+			return nil, apperrors.FieldError("parentId", "is invalid")
+		}
+		if parent.Depth+1 > s.maxDepth {
+			return nil, apperrors.FieldError("parentId", fmt.Sprintf("is too deep (maximum reply depth is %d)", s.maxDepth))
+		}
+	}
+
+	// Kiểm tra nội dung
+	decision, err := checkContent(s.filter, &Content{Kind: ContentKindComment, Author: author, Text: req.Comment.Body, Body: req.Comment.Body})
+	if err != nil {
+		return nil, err
 	}
 
-	// Tạo comment
-	comment, err := s.commentRepo.Create(article.ID, authorID, req.Comment.Body)
+	// Tạo comment và áp dụng kết quả kiểm tra cùng commit
+	var comment *models.Comment
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		var err error
+		comment, err = tx.Comment.Create(article.ID, authorID, parent, req.Comment.Body, utils.RenderMarkdown(req.Comment.Body))
+		if err != nil {
+			return err
+		}
+		return applyCommentDecision(tx, comment.ID, decision, time.Now())
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -45,71 +102,249 @@ func (s *CommentService) AddComment(slug string, authorID int, req dto.CreateCom
 	return s.buildCommentResponse(comment.ID, &authorID)
 }
 
-// GetComments lấy tất cả comments của article
-func (s *CommentService) GetComments(slug string, currentUserID *int) (*dto.CommentListResponse, error) {
+// GetComments lấy comments của article theo query.Sort (mặc định mới nhất trước)
+// Replies nằm cùng danh sách với parentId và depth để client dựng cây; comment đã xóa hoặc bị ẩn
+// còn replies được trả về như placeholder "[deleted]" / "[hidden]".
+// limit <= 0 là lấy tất cả (giống RealWorld spec); cursor là nextCursor của trang trước với cùng sort.
+func (s *CommentService) GetComments(slug string, query dto.CommentListQuery, cursor string, limit int, currentUserID *int) (*dto.CommentListResponse, error) {
+	order := repositories.CommentSort(query.Sort)
+	if !order.IsValid() {
+		return nil, apperrors.FieldError("sort", "is invalid")
+	}
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	// Lấy article theo slug
-	article, err := s.articleRepo.GetBySlug(slug)
+	article, err := findVisibleArticle(s.articleRepo, slug, currentUserID)
 	if err != nil {
 		return nil, err
 	}
-	if article == nil {
-		return nil, errors.New("article not found")
+
+	// Lấy comments, thêm 1 comment để biết còn trang sau không
+	fetchLimit := 0
+	if limit > 0 {
+		fetchLimit = limit + 1
+	}
+	comments, err := s.commentRepo.GetByArticleID(article.ID, order, after, fetchLimit)
+	if err != nil {
+		return nil, err
 	}
 
-	// Lấy comments
-	comments, err := s.commentRepo.GetByArticleID(article.ID)
+	fetched := len(comments)
+	if limit > 0 && fetched > limit {
+		comments = comments[:limit]
+	}
+
+	// Build response cho cả trang với số query cố định
+	commentResps, err := s.buildCommentResponses(comments, currentUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build response
 	response := &dto.CommentListResponse{
-		Comments: []dto.CommentResponse{},
+		Comments: commentResps,
+	}
+	if len(comments) > 0 {
+		response.NextCursor = nextCursor(fetched, limit, order.CursorFor(comments[len(comments)-1]))
 	}
+	return response, nil
+}
 
-	for _, comment := range comments {
-		commentResp, err := s.buildCommentResponse(comment.ID, currentUserID)
+// UpdateComment sửa body của comment, chỉ author sửa được và chỉ trong editWindow sau khi đăng
+// Body cũ được lưu vào lịch sử chỉnh sửa (chỉ moderators xem được), comment giữ nguyên vị trí trong thread.
+// Comment bị ẩn không sửa được, user bị ban không sửa được comment nào. Body mới cũng qua content filter như khi đăng.
+func (s *CommentService) UpdateComment(slug string, commentID, userID int, req dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	_, comment, err := s.findComment(slug, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra quyền sở hữu (chỉ author của comment mới được sửa)
+	if comment.AuthorID != userID {
+		return nil, ErrPermissionDenied
+	}
+	if comment.IsHidden() {
+		return nil, apperrors.Forbidden("comment has been hidden")
+	}
+	if time.Since(comment.CreatedAt) > s.editWindow {
+		return nil, apperrors.Forbidden("comment can no longer be edited")
+	}
+	author, err := s.requireNotBanned(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Comment.Body != comment.Body {
+		// Kiểm tra nội dung mới
+		decision, err := checkContent(s.filter, &Content{Kind: ContentKindComment, Author: author, ID: comment.ID, Text: req.Comment.Body, Body: req.Comment.Body})
+		if err != nil {
+			return nil, err
+		}
+
+		// Lưu body cũ, sửa comment và áp dụng kết quả kiểm tra cùng commit
+		now := time.Now()
+		err = s.uow.Do(func(tx *repositories.Repositories) error {
+			if err := tx.Comment.CreateEdit(comment.ID, comment.Body, comment.BodyHTML, now); err != nil {
+				return err
+			}
+			if err := tx.Comment.Update(comment.ID, req.Comment.Body, utils.RenderMarkdown(req.Comment.Body), now); err != nil {
+				return err
+			}
+			return applyCommentDecision(tx, comment.ID, decision, now)
+		})
 		if err != nil {
 			return nil, err
 		}
-		response.Comments = append(response.Comments, *commentResp)
 	}
 
+	return s.buildCommentResponse(comment.ID, &userID)
+}
+
+// ListCommentEdits lấy lịch sử chỉnh sửa của comment, lần sửa mới nhất trước, chỉ moderators
+func (s *CommentService) ListCommentEdits(slug string, commentID, userID int) (*dto.CommentEditListResponse, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	_, comment, err := s.findComment(slug, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := s.commentRepo.ListEdits(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CommentEditListResponse{
+		Edits:      make([]dto.CommentEdit, 0, len(edits)),
+		EditsCount: len(edits),
+	}
+	for _, edit := range edits {
+		response.Edits = append(response.Edits, dto.CommentEdit{
+			Body:     edit.Body,
+			BodyHTML: renderedBody(edit.Body, edit.BodyHTML),
+			EditedAt: edit.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		})
+	}
 	return response, nil
 }
 
 // DeleteComment xóa comment
+// Replies của comment vẫn hiển thị, comment bị thay bằng placeholder "[deleted]".
 func (s *CommentService) DeleteComment(slug string, commentID, userID int) error {
-	// Lấy article theo slug
-	article, err := s.articleRepo.GetBySlug(slug)
+	_, comment, err := s.findComment(slug, commentID, userID)
 	if err != nil {
 		return err
 	}
-	if article == nil {
-		return errors.New("article not found")
+
+	// Kiểm tra quyền sở hữu (chỉ author của comment mới được xóa)
+	if comment.AuthorID != userID {
+		return ErrPermissionDenied
 	}
 
-	// Lấy comment
-	comment, err := s.commentRepo.GetByID(commentID)
+	// Chuyển comment vào thùng rác, author có thể khôi phục trong thời gian lưu giữ
+	// Số replies của các comments cha được cập nhật trong cùng transaction.
+	return s.uow.Do(func(tx *repositories.Repositories) error {
+		return tx.Comment.Delete(commentID)
+	})
+}
+
+// ReportComment lưu report của userID về comment kèm lý do để moderators xem trong hàng đợi kiểm duyệt
+// Mỗi user report một comment một lần, report lại được bỏ qua; không report được comment của chính mình.
+func (s *CommentService) ReportComment(slug string, commentID, userID int, req dto.ReportCommentRequest) error {
+	_, comment, err := s.findComment(slug, commentID, userID)
 	if err != nil {
 		return err
 	}
-	if comment == nil {
-		return errors.New("comment not found")
+	if comment.AuthorID == userID {
+		return apperrors.BadRequest("cannot report your own comment")
 	}
 
-	// Kiểm tra comment thuộc về article
-	if comment.ArticleID != article.ID {
-		return errors.New("comment not found")
+	reason := strings.TrimSpace(req.Report.Reason)
+	if reason == "" {
+		return apperrors.FieldError("reason", "can't be blank")
 	}
+	return s.commentRepo.CreateReport(comment.ID, &userID, reason)
+}
 
-	// Kiểm tra quyền sở hữu (chỉ author của comment mới được xóa)
-	if comment.AuthorID != userID {
-		return errors.New("permission denied")
+// HideComment ẩn comment trên article của userID, chỉ author của article ẩn được
+// Comment bị ẩn biến mất khỏi danh sách comments (còn replies thì thành placeholder "[hidden]");
+// hành động được ghi vào lịch sử kiểm duyệt, reports của comment vẫn chờ moderators xử lý.
+func (s *CommentService) HideComment(slug string, commentID, userID int) error {
+	article, comment, err := s.findComment(slug, commentID, userID)
+	if err != nil {
+		return err
+	}
+	if article.AuthorID != userID {
+		return ErrPermissionDenied
+	}
+	if comment.IsHidden() {
+		return nil
+	}
+
+	// Ẩn comment và ghi lịch sử cùng commit
+	now := time.Now()
+	return s.uow.Do(func(tx *repositories.Repositories) error {
+		if err := tx.Comment.Hide(comment.ID, now); err != nil {
+			return err
+		}
+		return tx.Comment.CreateAction(comment.ID, userID, models.CommentActionHide, now)
+	})
+}
+
+// requireNotBanned lấy user userID, trả về lỗi Forbidden nếu user bị moderator cấm bình luận
+func (s *CommentService) requireNotBanned(userID int) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
 	}
+	if user != nil && user.IsBanned() {
+		return nil, apperrors.Forbidden("you are banned from commenting")
+	}
+	return user, nil
+}
 
-	// Xóa comment
-	return s.commentRepo.Delete(commentID)
+// applyCommentDecision áp dụng kết quả content filter cho comment vừa lưu trong tx
+// Flag gửi comment vào hàng đợi kiểm duyệt, hold còn ẩn comment cho tới khi moderator duyệt.
+func applyCommentDecision(tx *repositories.Repositories, commentID int, decision *ContentDecision, now time.Time) error {
+	switch decision.Verdict {
+	case ContentHold:
+		// Bị giữ lại: ẩn comment và report để moderators duyệt
+		if err := tx.Comment.Hide(commentID, now); err != nil {
+			return err
+		}
+		return tx.Comment.CreateReport(commentID, nil, decision.Reason())
+	case ContentFlag:
+		// Bị gắn cờ: comment vẫn hiển thị, chỉ report để moderators xem
+		return tx.Comment.CreateReport(commentID, nil, decision.Reason())
+	default:
+		return nil
+	}
+}
+
+// findComment lấy comment chưa xóa commentID của article slug mà userID xem được, kèm article
+// Comment không tồn tại hoặc thuộc article khác trả về ErrCommentNotFound.
+func (s *CommentService) findComment(slug string, commentID, userID int) (*models.Article, *models.Comment, error) {
+	// Lấy article theo slug
+	article, err := findVisibleArticle(s.articleRepo, slug, &userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Lấy comment
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Kiểm tra comment thuộc về article
+	if comment == nil || comment.ArticleID != article.ID {
+		return nil, nil, ErrCommentNotFound
+	}
+	return article, comment, nil
 }
 
 // buildCommentResponse build CommentResponse từ comment ID
@@ -120,37 +355,92 @@ func (s *CommentService) buildCommentResponse(commentID int, currentUserID *int)
 		return nil, err
 	}
 	if comment == nil {
-		return nil, errors.New("comment not found")
+		return nil, ErrCommentNotFound
 	}
 
-	// Lấy author
-	author, err := s.commentRepo.GetAuthorByID(comment.AuthorID)
+	responses, err := s.buildCommentResponses([]*models.Comment{comment}, currentUserID)
 	if err != nil {
 		return nil, err
 	}
-	if author == nil {
-		return nil, errors.New("author not found")
+	return &responses[0], nil
+}
+
+// buildCommentResponses build CommentResponse cho cả danh sách comments
+// Authors và following được load theo lô cho toàn bộ danh sách nên số query không phụ thuộc
+// vào số comments (tối đa 2 query). Comment đã xóa hoặc bị ẩn chỉ còn vị trí trong thread, không có author;
+// riêng author vẫn thấy nội dung comment bị ẩn của mình.
+func (s *CommentService) buildCommentResponses(comments []*models.Comment, currentUserID *int) ([]dto.CommentResponse, error) {
+	responses := make([]dto.CommentResponse, 0, len(comments))
+	if len(comments) == 0 {
+		return responses, nil
+	}
+
+	authorIDs := []int{}
+	seenAuthors := map[int]bool{}
+	for _, comment := range comments {
+		if !comment.IsDeleted() && (!comment.IsHidden() || isOwnComment(comment, currentUserID)) && !seenAuthors[comment.AuthorID] {
+			seenAuthors[comment.AuthorID] = true
+			authorIDs = append(authorIDs, comment.AuthorID)
+		}
+	}
+
+	// Lấy authors
+	authors, err := s.userRepo.GetByIDs(authorIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	// Kiểm tra following (nếu có currentUserID)
-	following := false
-	if currentUserID != nil && *currentUserID != comment.AuthorID {
-		following, err = s.followRepo.IsFollowing(*currentUserID, comment.AuthorID)
+	following := map[int]bool{}
+	if currentUserID != nil {
+		following, err = s.followRepo.GetFollowingIDs(*currentUserID, authorIDs)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Build response
-	response := &dto.CommentResponse{}
-	response.Comment.ID = comment.ID
-	response.Comment.Body = comment.Body
-	response.Comment.CreatedAt = comment.CreatedAt.Format("2006-01-02T15:04:05.000Z")
-	response.Comment.UpdatedAt = comment.UpdatedAt.Format("2006-01-02T15:04:05.000Z")
-	response.Comment.Author.Username = author.Username
-	response.Comment.Author.Bio = author.Bio
-	response.Comment.Author.Image = author.Image
-	response.Comment.Author.Following = following
+	for _, comment := range comments {
+		// Build response
+		response := dto.CommentResponse{}
+		response.Comment.ID = comment.ID
+		response.Comment.ParentID = comment.ParentID
+		response.Comment.Depth = comment.Depth
+		response.Comment.RepliesCount = comment.RepliesCount
+		response.Comment.CreatedAt = comment.CreatedAt.Format("2006-01-02T15:04:05.000Z")
+		response.Comment.UpdatedAt = comment.UpdatedAt.Format("2006-01-02T15:04:05.000Z")
+		if comment.IsDeleted() {
+			response.Comment.Deleted = true
+			response.Comment.Body = DeletedCommentBody
+			responses = append(responses, response)
+			continue
+		}
+		if comment.IsHidden() {
+			response.Comment.Hidden = true
+			if !isOwnComment(comment, currentUserID) {
+				response.Comment.Body = HiddenCommentBody
+				responses = append(responses, response)
+				continue
+			}
+		}
 
-	return response, nil
+		author, ok := authors[comment.AuthorID]
+		if !ok {
+			return nil, errors.New("author not found")
+		}
+		response.Comment.Edited = comment.IsEdited()
+		response.Comment.Body = comment.Body
+		response.Comment.BodyHTML = renderedBody(comment.Body, comment.BodyHTML)
+		response.Comment.Author.Username = author.Username
+		response.Comment.Author.Bio = author.Bio
+		response.Comment.Author.Image = author.Image
+		response.Comment.Author.Following = following[comment.AuthorID]
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// isOwnComment kiểm tra currentUserID có phải author của comment không
+func isOwnComment(comment *models.Comment, currentUserID *int) bool {
+	return currentUserID != nil && *currentUserID == comment.AuthorID
 }
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"fmt"
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCommentService tạo CommentService với in-memory repositories: replies tối đa 5 cấp, sửa được
+// trong 1 giờ, không có content filter; test cần cấu hình khác thì sửa field của service trả về
+func newTestCommentService(repos *repositories.Repositories) *CommentService {
+	return NewCommentService(repos.UnitOfWork, repos.Comment, repos.Article, repos.User, repos.Follow, 5, time.Hour, nil)
+}
+
+// TestCommentService_CursorPagination kiểm tra phân trang comments bằng cursor
+func TestCommentService_CursorPagination(t *testing.T) {
+	articleService, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	_, err := articleService.CreateArticle(author.ID, newCreateArticleRequest("Commented"))
+	require.NoError(t, err)
+	for _, body := range []string{"first", "second", "third"} {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		_, err := commentService.AddComment("commented", author.ID, req)
+		require.NoError(t, err)
+	}
+
+	// Không có limit: trả về tất cả, không có nextCursor
+	all, err := commentService.GetComments("commented", dto.CommentListQuery{}, "", 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, all.Comments, 3)
+	assert.Nil(t, all.NextCursor)
+
+	first, err := commentService.GetComments("commented", dto.CommentListQuery{}, "", 2, nil)
+	require.NoError(t, err)
+	require.Len(t, first.Comments, 2)
+	assert.Equal(t, "third", first.Comments[0].Comment.Body)
+	require.NotNil(t, first.NextCursor)
+
+	second, err := commentService.GetComments("commented", dto.CommentListQuery{}, *first.NextCursor, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, second.Comments, 1)
+	assert.Equal(t, "first", second.Comments[0].Comment.Body)
+	assert.Nil(t, second.NextCursor)
+}
+
+// countingCommentRepository đếm các query lấy comments
+type countingCommentRepository struct {
+	repositories.CommentRepository
+	counter *queryCounter
+}
+
+func (r *countingCommentRepository) GetByID(id int) (*models.Comment, error) {
+	r.counter.hit()
+	return r.CommentRepository.GetByID(id)
+}
+
+func (r *countingCommentRepository) GetByArticleID(articleID int, order repositories.CommentSort, after *repositories.Cursor, limit int) ([]*models.Comment, error) {
+	r.counter.hit()
+	return r.CommentRepository.GetByArticleID(articleID, order, after, limit)
+}
+
+// TestCommentService_ListQueryCount kiểm tra số query khi list comments không tăng theo số comments
+func TestCommentService_ListQueryCount(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	articleService := NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, nil)
+	setup := newTestCommentService(repos)
+	reader, err := repos.User.Create("reader", "reader@example.com", "hash")
+	require.NoError(t, err)
+	_, err = articleService.CreateArticle(reader.ID, newCreateArticleRequest("Popular"))
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		author, err := repos.User.Create(fmt.Sprintf("author%d", i), fmt.Sprintf("author%d@example.com", i), "hash")
+		require.NoError(t, err)
+		if i%2 == 0 {
+			require.NoError(t, repos.Follow.Follow(reader.ID, author.ID))
+		}
+		var req dto.CreateCommentRequest
+		req.Comment.Body = fmt.Sprintf("comment %d", i)
+		_, err = setup.AddComment("popular", author.ID, req)
+		require.NoError(t, err)
+	}
+
+	counter := &queryCounter{}
+	service := newTestCommentService(repos)
+	service.commentRepo = &countingCommentRepository{CommentRepository: repos.Comment, counter: counter}
+	service.userRepo = &countingUserRepository{UserRepository: repos.User, counter: counter}
+	service.followRepo = &countingFollowRepository{FollowRepository: repos.Follow, counter: counter}
+	for _, limit := range []int{1, 20, 0} {
+		list, err := service.GetComments("popular", dto.CommentListQuery{}, "", limit, &reader.ID)
+		require.NoError(t, err)
+		if limit > 0 {
+			assert.Len(t, list.Comments, limit)
+		} else {
+			assert.Len(t, list.Comments, 100)
+		}
+		// Comments + authors + following
+		assert.Equal(t, int64(3), counter.reset(), "limit=%d", limit)
+	}
+
+	// Dữ liệu load theo lô phải khớp với từng comment
+	list, err := service.GetComments("popular", dto.CommentListQuery{}, "", 0, &reader.ID)
+	require.NoError(t, err)
+	for _, comment := range list.Comments {
+		single, err := service.buildCommentResponse(comment.Comment.ID, &reader.ID)
+		require.NoError(t, err)
+		assert.Equal(t, *single, comment)
+	}
+}
+
+// TestCommentService_Replies kiểm tra trả lời comments, giới hạn độ sâu và placeholder của comment đã xóa
+func TestCommentService_Replies(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	commentService.maxDepth = 2
+	trash := NewTrashService(repos.UnitOfWork, service, commentService, repos.Article, repos.Comment, time.Hour)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Thread", "go"))
+	require.NoError(t, err)
+	_, err = service.CreateArticle(author.ID, newCreateArticleRequest("Other"))
+	require.NoError(t, err)
+
+	// reply tạo comment trả lời parentID (nil là comment gốc)
+	reply := func(slug string, userID int, parentID *int, body string) (*dto.CommentResponse, error) {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		req.Comment.ParentID = parentID
+		return commentService.AddComment(slug, userID, req)
+	}
+	root, err := reply("thread", author.ID, nil, "root")
+	require.NoError(t, err)
+	assert.Nil(t, root.Comment.ParentID)
+	child, err := reply("thread", reader.ID, &root.Comment.ID, "child")
+	require.NoError(t, err)
+	assert.Equal(t, root.Comment.ID, *child.Comment.ParentID)
+	assert.Equal(t, 1, child.Comment.Depth)
+	grandchild, err := reply("thread", author.ID, &child.Comment.ID, "grandchild")
+	require.NoError(t, err)
+	assert.Equal(t, 2, grandchild.Comment.Depth)
+	sibling, err := reply("thread", reader.ID, &root.Comment.ID, "sibling")
+	require.NoError(t, err)
+
+	// Vượt quá độ sâu tối đa hoặc parent thuộc article khác bị từ chối
+	_, err = reply("thread", reader.ID, &grandchild.Comment.ID, "too deep")
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"parentId": {"is too deep (maximum reply depth is 2)"}}, appErr.Fields)
+	_, err = reply("other", reader.ID, &root.Comment.ID, "wrong article")
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"parentId": {"is invalid"}}, appErr.Fields)
+
+	// thread trả về body và repliesCount theo id
+	thread := func() (map[int]string, map[int]int) {
+		list, err := commentService.GetComments("thread", dto.CommentListQuery{}, "", 0, nil)
+		require.NoError(t, err)
+		bodies, replies := map[int]string{}, map[int]int{}
+		for _, comment := range list.Comments {
+			bodies[comment.Comment.ID] = comment.Comment.Body
+			replies[comment.Comment.ID] = comment.Comment.RepliesCount
+		}
+		return bodies, replies
+	}
+	bodies, replies := thread()
+	assert.Len(t, bodies, 4)
+	assert.Equal(t, 2, replies[root.Comment.ID])
+	assert.Equal(t, 1, replies[child.Comment.ID])
+
+	// Xóa parent: replies vẫn hiển thị, parent thành placeholder
+	require.NoError(t, commentService.DeleteComment("thread", root.Comment.ID, author.ID))
+	require.NoError(t, commentService.DeleteComment("thread", child.Comment.ID, reader.ID))
+	list, err := commentService.GetComments("thread", dto.CommentListQuery{}, "", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Comments, 4)
+	for _, comment := range list.Comments {
+		if comment.Comment.ID == root.Comment.ID {
+			assert.True(t, comment.Comment.Deleted)
+			assert.Equal(t, DeletedCommentBody, comment.Comment.Body)
+			assert.Empty(t, comment.Comment.Author.Username)
+		}
+	}
+	_, err = reply("thread", reader.ID, &root.Comment.ID, "reply to deleted")
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"parentId": {"is invalid"}}, appErr.Fields)
+
+	// Comments đã xóa còn replies không bị xóa hẳn; xóa hết replies thì placeholder biến mất
+	purged, err := trash.PurgeTrash(time.Now().Add(2*time.Hour), 100)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	require.NoError(t, commentService.DeleteComment("thread", grandchild.Comment.ID, author.ID))
+	bodies, replies = thread()
+	assert.Equal(t, map[int]string{root.Comment.ID: DeletedCommentBody, sibling.Comment.ID: "sibling"}, bodies)
+	assert.Equal(t, 1, replies[root.Comment.ID])
+
+	// Mỗi lượt purge xóa lá của cây trước, root còn sibling nên được giữ
+	for _, expected := range []int{1, 1, 0} {
+		purged, err = trash.PurgeTrash(time.Now().Add(2*time.Hour), 100)
+		require.NoError(t, err)
+		assert.Equal(t, expected, purged)
+	}
+	bodies, _ = thread()
+	assert.Len(t, bodies, 2)
+}
+
+// TestCommentService_Edits kiểm tra sửa comment, thời gian cho phép sửa và lịch sử chỉnh sửa
+func TestCommentService_Edits(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Edits"))
+	require.NoError(t, err)
+
+	var req dto.CreateCommentRequest
+	req.Comment.Body = "frist"
+	comment, err := commentService.AddComment("edits", reader.ID, req)
+	require.NoError(t, err)
+	assert.False(t, comment.Comment.Edited)
+
+	// update sửa comment thành body
+	update := func(userID int, body string) (*dto.CommentResponse, error) {
+		var req dto.UpdateCommentRequest
+		req.Comment.Body = body
+		return commentService.UpdateComment("edits", comment.Comment.ID, userID, req)
+	}
+
+	// Chỉ author được sửa
+	_, err = update(author.ID, "hacked")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	// Body không đổi thì không tính là sửa
+	unchanged, err := update(reader.ID, "frist")
+	require.NoError(t, err)
+	assert.False(t, unchanged.Comment.Edited)
+
+	updated, err := update(reader.ID, "*first*")
+	require.NoError(t, err)
+	assert.True(t, updated.Comment.Edited)
+	assert.Equal(t, "*first*", updated.Comment.Body)
+	assert.Equal(t, "<p><em>first</em></p>\n", updated.Comment.BodyHTML)
+	_, err = update(reader.ID, "first!")
+	require.NoError(t, err)
+
+	list, err := commentService.GetComments("edits", dto.CommentListQuery{}, "", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Comments, 1)
+	assert.True(t, list.Comments[0].Comment.Edited)
+	assert.Equal(t, "first!", list.Comments[0].Comment.Body)
+
+	// Lịch sử chỉnh sửa chỉ moderators xem được, lần sửa mới nhất trước
+	_, err = commentService.ListCommentEdits("edits", comment.Comment.ID, reader.ID)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	edits, err := commentService.ListCommentEdits("edits", comment.Comment.ID, moderator.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, edits.EditsCount)
+	assert.Equal(t, "*first*", edits.Edits[0].Body)
+	assert.Equal(t, "frist", edits.Edits[1].Body)
+	assert.Equal(t, "<p>frist</p>\n", edits.Edits[1].BodyHTML)
+	_, err = commentService.ListCommentEdits("edits", comment.Comment.ID+1, moderator.ID)
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+
+	// Quá thời gian cho phép sửa
+	expired := newTestCommentService(repos)
+	expired.editWindow = time.Nanosecond
+	var late dto.UpdateCommentRequest
+	late.Comment.Body = "too late"
+	_, err = expired.UpdateComment("edits", comment.Comment.ID, reader.ID, late)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, apperrors.KindForbidden, appErr.Kind)
+}
+
+// TestCommentService_RepliesCountInResponses kiểm tra repliesCount của comment đọc theo ID (sửa comment)
+// khớp với danh sách comments: chỉ đếm replies đang hiển thị
+func TestCommentService_RepliesCountInResponses(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Counted"))
+	require.NoError(t, err)
+
+	// add tạo comment trả lời parentID (nil là comment gốc)
+	add := func(parentID *int, body string) int {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		req.Comment.ParentID = parentID
+		comment, err := commentService.AddComment("counted", author.ID, req)
+		require.NoError(t, err)
+		return comment.Comment.ID
+	}
+	root := add(nil, "root")
+	add(&root, "reply 1")
+	reply2 := add(&root, "reply 2")
+	require.NoError(t, commentService.DeleteComment("counted", reply2, author.ID))
+
+	var edit dto.UpdateCommentRequest
+	edit.Comment.Body = "root, edited"
+	updated, err := commentService.UpdateComment("counted", root, author.ID, edit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated.Comment.RepliesCount)
+
+	list, err := commentService.GetComments("counted", dto.CommentListQuery{Sort: "oldest"}, "", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Comments, 2)
+	assert.Equal(t, root, list.Comments[0].Comment.ID)
+	assert.Equal(t, updated.Comment.RepliesCount, list.Comments[0].Comment.RepliesCount)
+}
+
+// TestCommentService_Sort kiểm tra các kiểu sắp xếp comments và commentsCount của articles
+func TestCommentService_Sort(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Sorted"))
+	require.NoError(t, err)
+
+	// add tạo comment trả lời parentID (nil là comment gốc)
+	add := func(parentID *int, body string) int {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		req.Comment.ParentID = parentID
+		comment, err := commentService.AddComment("sorted", author.ID, req)
+		require.NoError(t, err)
+		return comment.Comment.ID
+	}
+	first := add(nil, "first")
+	second := add(nil, "second")
+	third := add(nil, "third")
+	reply1 := add(&second, "reply 1")
+	reply2 := add(&second, "reply 2")
+	reply3 := add(&first, "reply 3")
+
+	// ids lấy toàn bộ comments theo sort, mỗi trang 2 comments
+	ids := func(sort string) []int {
+		var result []int
+		cursor := ""
+		for {
+			list, err := commentService.GetComments("sorted", dto.CommentListQuery{Sort: sort}, cursor, 2, nil)
+			require.NoError(t, err)
+			for _, comment := range list.Comments {
+				result = append(result, comment.Comment.ID)
+			}
+			if list.NextCursor == nil {
+				return result
+			}
+			cursor = *list.NextCursor
+		}
+	}
+	assert.Equal(t, []int{reply3, reply2, reply1, third, second, first}, ids(""))
+	assert.Equal(t, []int{first, second, third, reply1, reply2, reply3}, ids("oldest"))
+	// Nhiều replies trực tiếp nhất trước, cùng số replies thì mới nhất trước
+	assert.Equal(t, []int{second, first, reply3, reply2, reply1, third}, ids("top"))
+
+	_, err = commentService.GetComments("sorted", dto.CommentListQuery{Sort: "popular"}, "", 0, nil)
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"sort": {"is invalid"}}, appErr.Fields)
+
+	// commentsCount không tính comments đã xóa
+	require.NoError(t, commentService.DeleteComment("sorted", reply1, author.ID))
+	article, err := service.GetArticle("sorted", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, article.Article.CommentsCount)
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Articles, 1)
+	assert.Equal(t, 5, list.Articles[0].Article.CommentsCount)
+}
+
+// TestCommentService_Moderation kiểm tra report, hàng đợi kiểm duyệt, ẩn/xóa/ban và comment bị ẩn với author
+func TestCommentService_Moderation(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	moderation := NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	trash := NewTrashService(repos.UnitOfWork, service, commentService, repos.Article, repos.Comment, time.Hour)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	troll, _ := repos.User.Create("troll", "troll@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	other, _ := repos.User.Create("other", "other@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Moderated"))
+	require.NoError(t, err)
+
+	// add tạo comment trả lời parentID (nil là comment gốc)
+	add := func(userID int, parentID *int, body string) (*dto.CommentResponse, error) {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		req.Comment.ParentID = parentID
+		return commentService.AddComment("moderated", userID, req)
+	}
+	// report report comment với reason
+	report := func(commentID, userID int, reason string) error {
+		var req dto.ReportCommentRequest
+		req.Report.Reason = reason
+		return commentService.ReportComment("moderated", commentID, userID, req)
+	}
+	// moderate xử lý comment bằng action
+	moderate := func(commentID, userID int, action string) error {
+		var req dto.ModerateCommentRequest
+		req.Moderation.Action = action
+		return moderation.ModerateComment(commentID, userID, req)
+	}
+	// queue lấy hàng đợi kiểm duyệt
+	queue := func() *dto.ReportedCommentListResponse {
+		list, err := moderation.ListReportedComments(moderator.ID, dto.ModerationQueueQuery{})
+		require.NoError(t, err)
+		return list
+	}
+
+	spam, err := add(troll.ID, nil, "buy now")
+	require.NoError(t, err)
+	rude, err := add(troll.ID, nil, "rude")
+	require.NoError(t, err)
+	reply, err := add(reader.ID, &rude.Comment.ID, "please be nice")
+	require.NoError(t, err)
+
+	// Report: không report được comment của chính mình, reason không được rỗng, report lại bị bỏ qua
+	err = report(spam.Comment.ID, troll.ID, "mine")
+	assert.ErrorIs(t, err, apperrors.ErrBadRequest)
+	appErr := apperrors.As(report(spam.Comment.ID, reader.ID, "  "))
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"reason": {"can't be blank"}}, appErr.Fields)
+	require.NoError(t, report(spam.Comment.ID, reader.ID, "spam"))
+	require.NoError(t, report(spam.Comment.ID, reader.ID, "spam again"))
+	require.NoError(t, report(spam.Comment.ID, other.ID, "advertising"))
+	require.NoError(t, report(rude.Comment.ID, reader.ID, "harassment"))
+
+	// Chỉ moderators xem và xử lý được hàng đợi
+	_, err = moderation.ListReportedComments(reader.ID, dto.ModerationQueueQuery{})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.ErrorIs(t, moderate(spam.Comment.ID, author.ID, models.CommentActionHide), ErrPermissionDenied)
+
+	list := queue()
+	assert.Equal(t, 2, list.CommentsCount)
+	require.Len(t, list.Comments, 2)
+	first := list.Comments[0]
+	assert.Equal(t, spam.Comment.ID, first.ID)
+	assert.Equal(t, "moderated", first.ArticleSlug)
+	assert.Equal(t, "troll", first.Author.Username)
+	assert.Equal(t, 2, first.ReportsCount)
+	require.Len(t, first.Reports, 2)
+	require.NotNil(t, first.Reports[0].Reporter)
+	require.NotNil(t, first.Reports[1].Reporter)
+	assert.Equal(t, "reader", *first.Reports[0].Reporter)
+	assert.Equal(t, "spam", first.Reports[0].Reason)
+	assert.Equal(t, "other", *first.Reports[1].Reporter)
+	assert.Empty(t, first.Actions)
+
+	// Author của article ẩn comment: comment còn reply thành placeholder, reports vẫn chờ moderator
+	assert.ErrorIs(t, commentService.HideComment("moderated", rude.Comment.ID, reader.ID), ErrPermissionDenied)
+	require.NoError(t, commentService.HideComment("moderated", rude.Comment.ID, author.ID))
+	comments, err := commentService.GetComments("moderated", dto.CommentListQuery{Sort: "oldest"}, "", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, comments.Comments, 3)
+	placeholder := comments.Comments[1].Comment
+	assert.Equal(t, rude.Comment.ID, placeholder.ID)
+	assert.True(t, placeholder.Hidden)
+	assert.Equal(t, HiddenCommentBody, placeholder.Body)
+	assert.Empty(t, placeholder.Author.Username)
+	article, err := service.GetArticle("moderated", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, article.Article.CommentsCount)
+	_, err = add(reader.ID, &rude.Comment.ID, "reply to hidden")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	list = queue()
+	require.Len(t, list.Comments, 2)
+	assert.True(t, list.Comments[1].Hidden)
+	require.Len(t, list.Comments[1].Actions, 1)
+	assert.Equal(t, models.CommentActionHide, list.Comments[1].Actions[0].Action)
+	assert.Equal(t, "author", list.Comments[1].Actions[0].Actor)
+
+	// Dismiss: comment giữ nguyên, rời hàng đợi; report mới đưa comment quay lại kèm lịch sử
+	require.NoError(t, moderate(spam.Comment.ID, moderator.ID, models.CommentActionDismiss))
+	list = queue()
+	require.Len(t, list.Comments, 1)
+	assert.Equal(t, rude.Comment.ID, list.Comments[0].ID)
+	late, _ := repos.User.Create("late", "late@example.com", "hash")
+	require.NoError(t, report(spam.Comment.ID, late.ID, "still spam"))
+	list = queue()
+	require.Len(t, list.Comments, 2)
+	assert.Equal(t, spam.Comment.ID, list.Comments[1].ID)
+	assert.Equal(t, 1, list.Comments[1].ReportsCount)
+	require.Len(t, list.Comments[1].Actions, 1)
+	assert.Equal(t, models.CommentActionDismiss, list.Comments[1].Actions[0].Action)
+	assert.Equal(t, "moderator", list.Comments[1].Actions[0].Actor)
+
+	// Delete: comment vào thùng rác của author, khôi phục lại vẫn bị ẩn
+	require.NoError(t, moderate(spam.Comment.ID, moderator.ID, models.CommentActionDelete))
+	restored, err := trash.RestoreComment(spam.Comment.ID, troll.ID)
+	require.NoError(t, err)
+	assert.True(t, restored.Comment.Hidden)
+	comments, err = commentService.GetComments("moderated", dto.CommentListQuery{}, "", 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, comments.Comments, 2)
+
+	// Ban: author không bình luận hay sửa comment được nữa
+	require.NoError(t, moderate(rude.Comment.ID, moderator.ID, models.CommentActionBan))
+	assert.Empty(t, queue().Comments)
+	banned, _ := repos.User.GetByID(troll.ID)
+	assert.True(t, banned.IsBanned())
+	_, err = add(troll.ID, nil, "I'm back")
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	actions, err := repos.Comment.GetActions([]int{rude.Comment.ID})
+	require.NoError(t, err)
+	require.Len(t, actions[rude.Comment.ID], 2)
+	assert.Equal(t, models.CommentActionBan, actions[rude.Comment.ID][0].Action)
+	assert.Equal(t, moderator.ID, actions[rude.Comment.ID][0].ActorID)
+
+	// Comment không tồn tại
+	assert.ErrorIs(t, moderate(reply.Comment.ID+100, moderator.ID, models.CommentActionHide), ErrCommentNotFound)
+}
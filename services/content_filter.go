@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"news/apperrors"
+	"news/config"
+	"news/models"
+	"news/repositories"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ContentVerdict là kết quả kiểm tra nội dung, xếp theo mức độ tăng dần
+type ContentVerdict int
+
+const (
+	// ContentAccept cho lưu nội dung bình thường
+	ContentAccept ContentVerdict = iota
+	// ContentFlag cho lưu nội dung nhưng gửi moderators xem xét
+	ContentFlag
+	// ContentHold lưu nội dung nhưng giữ lại (không hiển thị công khai) chờ moderator duyệt
+	ContentHold
+	// ContentReject từ chối, nội dung không được lưu
+	ContentReject
+)
+
+// contentVerdicts map action trong config sang ContentVerdict
+var contentVerdicts = map[string]ContentVerdict{
+	config.ContentActionFlag:   ContentFlag,
+	config.ContentActionHold:   ContentHold,
+	config.ContentActionReject: ContentReject,
+}
+
+// Loại nội dung được kiểm tra
+const (
+	ContentKindArticle = "article"
+	ContentKindComment = "comment"
+)
+
+// Content là nội dung cần kiểm tra trước khi lưu
+type Content struct {
+	Kind   string       // ContentKindArticle hoặc ContentKindComment
+	Author *models.User // user đang lưu nội dung
+	// ID là article/comment đang sửa (0 khi tạo mới), không tính là trùng với chính nó
+	ID int
+	// Text là toàn bộ văn bản hiển thị: title, description và body của article hoặc body của comment
+	Text string
+	// Body là body của article/comment, dùng để phát hiện nội dung trùng lặp
+	Body string
+}
+
+// ContentDecision là kết quả của cả pipeline: verdict nặng nhất cùng lý do của các filters không cho qua
+type ContentDecision struct {
+	Verdict ContentVerdict
+	Reasons []string
+}
+
+// Reason trả về các lý do gộp thành một chuỗi, dùng để ghi lại cho moderators
+func (d *ContentDecision) Reason() string {
+	return "content filter: " + strings.Join(d.Reasons, "; ")
+}
+
+// RejectError trả về lỗi validation của field body kèm các lý do từ chối
+func (d *ContentDecision) RejectError() error {
+	return apperrors.Validation(map[string][]string{"body": d.Reasons})
+}
+
+// ContentFilter là một bước kiểm tra của pipeline
+// Check trả về ContentAccept nếu nội dung qua được, ngược lại trả về verdict kèm lý do.
+type ContentFilter interface {
+	Check(content *Content) (ContentVerdict, string, error)
+}
+
+// ContentFilterPipeline chạy lần lượt các filters trước khi lưu articles và comments
+// Pipeline nil cho qua mọi nội dung.
+type ContentFilterPipeline struct {
+	filters []ContentFilter
+}
+
+// NewContentFilterPipeline tạo pipeline chạy filters theo thứ tự
+func NewContentFilterPipeline(filters ...ContentFilter) *ContentFilterPipeline {
+	return &ContentFilterPipeline{filters: filters}
+}
+
+// NewContentFilterFromConfig tạo pipeline với các rule trong cfg: từ cấm, giới hạn links của account mới
+// và nội dung trùng lặp của cùng user
+func NewContentFilterFromConfig(cfg *config.Config, articleRepo repositories.ArticleRepository, commentRepo repositories.CommentRepository) *ContentFilterPipeline {
+	return NewContentFilterPipeline(
+		NewBannedWordsFilter(cfg.ContentBannedWords, contentVerdicts[cfg.ContentBannedWordsAction]),
+		NewLinkLimitFilter(cfg.ContentNewAccountMaxLinks, cfg.ContentNewAccountAge, contentVerdicts[cfg.ContentLinksAction]),
+		NewDuplicateContentFilter(articleRepo, commentRepo, cfg.ContentDuplicateWindow, contentVerdicts[cfg.ContentDuplicateAction]),
+	)
+}
+
+// Run kiểm tra content qua mọi filters và trả về verdict nặng nhất
+// Dừng ngay khi có filter từ chối nội dung.
+func (p *ContentFilterPipeline) Run(content *Content) (*ContentDecision, error) {
+	decision := &ContentDecision{Verdict: ContentAccept}
+	if p == nil {
+		return decision, nil
+	}
+
+	for _, filter := range p.filters {
+		verdict, reason, err := filter.Check(content)
+		if err != nil {
+			return nil, err
+		}
+		if verdict == ContentAccept {
+			continue
+		}
+		if verdict > decision.Verdict {
+			decision.Verdict = verdict
+		}
+		decision.Reasons = append(decision.Reasons, reason)
+		if verdict == ContentReject {
+			break
+		}
+	}
+	return decision, nil
+}
+
+// checkContent chạy content qua filter của ArticleService/CommentService, filter nil chấp nhận mọi nội dung
+// Nội dung bị từ chối trả về lỗi validation thay cho decision.
+func checkContent(filter *ContentFilterPipeline, content *Content) (*ContentDecision, error) {
+	decision, err := filter.Run(content)
+	if err != nil {
+		return nil, err
+	}
+	if decision.Verdict == ContentReject {
+		return nil, decision.RejectError()
+	}
+	return decision, nil
+}
+
+// bannedWordsFilter chặn nội dung chứa từ bị cấm, so khớp nguyên từ và không phân biệt hoa thường
+type bannedWordsFilter struct {
+	words   map[string]bool
+	verdict ContentVerdict
+}
+
+// NewBannedWordsFilter tạo filter trả về verdict khi nội dung chứa một trong words
+func NewBannedWordsFilter(words []string, verdict ContentVerdict) ContentFilter {
+	filter := &bannedWordsFilter{words: map[string]bool{}, verdict: verdict}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			filter.words[word] = true
+		}
+	}
+	return filter
+}
+
+// Check tách nội dung thành các từ (chữ và số) rồi so với danh sách từ cấm
+func (f *bannedWordsFilter) Check(content *Content) (ContentVerdict, string, error) {
+	if len(f.words) == 0 {
+		return ContentAccept, "", nil
+	}
+	words := strings.FieldsFunc(strings.ToLower(content.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if f.words[word] {
+			return f.verdict, "contains banned words", nil
+		}
+	}
+	return ContentAccept, "", nil
+}
+
+// linkPattern khớp với mỗi link trong nội dung (URL http/https hoặc bắt đầu bằng www.)
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// linkLimitFilter giới hạn số links trong nội dung của account mới đăng ký
+type linkLimitFilter struct {
+	maxLinks      int
+	newAccountAge time.Duration
+	verdict       ContentVerdict
+}
+
+// NewLinkLimitFilter tạo filter trả về verdict khi account đăng ký chưa quá newAccountAge
+// đăng nội dung có nhiều hơn maxLinks links
+func NewLinkLimitFilter(maxLinks int, newAccountAge time.Duration, verdict ContentVerdict) ContentFilter {
+	return &linkLimitFilter{maxLinks: maxLinks, newAccountAge: newAccountAge, verdict: verdict}
+}
+
+// Check đếm links nếu author là account mới
+func (f *linkLimitFilter) Check(content *Content) (ContentVerdict, string, error) {
+	if content.Author == nil || time.Since(content.Author.CreatedAt) >= f.newAccountAge {
+		return ContentAccept, "", nil
+	}
+	if len(linkPattern.FindAllStringIndex(content.Text, -1)) > f.maxLinks {
+		return f.verdict, fmt.Sprintf("contains too many links (maximum is %d for new accounts)", f.maxLinks), nil
+	}
+	return ContentAccept, "", nil
+}
+
+// duplicateContentFilter phát hiện user đăng lại đúng body đã đăng gần đây
+type duplicateContentFilter struct {
+	articleRepo repositories.ArticleRepository
+	commentRepo repositories.CommentRepository
+	window      time.Duration
+	verdict     ContentVerdict
+}
+
+// NewDuplicateContentFilter tạo filter trả về verdict khi author đã lưu article (với article)
+// hoặc comment (với comment) có cùng body trong window gần nhất
+func NewDuplicateContentFilter(articleRepo repositories.ArticleRepository, commentRepo repositories.CommentRepository, window time.Duration, verdict ContentVerdict) ContentFilter {
+	return &duplicateContentFilter{articleRepo: articleRepo, commentRepo: commentRepo, window: window, verdict: verdict}
+}
+
+// Check so body với nội dung cùng loại của author, body rỗng thì bỏ qua
+func (f *duplicateContentFilter) Check(content *Content) (ContentVerdict, string, error) {
+	if content.Author == nil || strings.TrimSpace(content.Body) == "" {
+		return ContentAccept, "", nil
+	}
+
+	since := time.Now().Add(-f.window)
+	var duplicate bool
+	var err error
+	switch content.Kind {
+	case ContentKindArticle:
+		duplicate, err = f.articleRepo.HasRecentBody(content.Author.ID, content.Body, since, content.ID)
+	case ContentKindComment:
+		duplicate, err = f.commentRepo.HasRecentBody(content.Author.ID, content.Body, since, content.ID)
+	}
+	if err != nil {
+		return ContentAccept, "", err
+	}
+	if duplicate {
+		return f.verdict, "duplicates content you posted recently", nil
+	}
+	return ContentAccept, "", nil
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentFilterPipeline kiểm tra từng filter và verdict nặng nhất của pipeline
+func TestContentFilterPipeline(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	newbie, _ := repos.User.Create("newbie", "newbie@example.com", "hash")
+	article, err := repos.Article.Create("posted", "Posted", "description", "body", "", newbie.ID, models.ArticleStatusPublished)
+	require.NoError(t, err)
+	comment, err := repos.Comment.Create(article.ID, newbie.ID, nil, "same", "")
+	require.NoError(t, err)
+
+	// check chạy một filter, lỗi làm test dừng
+	check := func(filter ContentFilter, content *Content) (ContentVerdict, string) {
+		verdict, reason, err := filter.Check(content)
+		require.NoError(t, err)
+		return verdict, reason
+	}
+	twoLinks := "see https://a.example and www.b.example"
+
+	// Từ cấm: so khớp nguyên từ, không phân biệt hoa thường, bỏ qua từ rỗng
+	banned := NewBannedWordsFilter([]string{" Casino ", ""}, ContentReject)
+	verdict, reason := check(banned, &Content{Text: "Best CASINO, online!"})
+	assert.Equal(t, ContentReject, verdict)
+	assert.Equal(t, "contains banned words", reason)
+	verdict, _ = check(banned, &Content{Text: "casinos nearby"})
+	assert.Equal(t, ContentAccept, verdict)
+	verdict, _ = check(NewBannedWordsFilter(nil, ContentReject), &Content{Text: "casino"})
+	assert.Equal(t, ContentAccept, verdict)
+
+	// Links: chỉ giới hạn account mới
+	links := NewLinkLimitFilter(1, time.Hour, ContentHold)
+	verdict, reason = check(links, &Content{Author: newbie, Text: twoLinks})
+	assert.Equal(t, ContentHold, verdict)
+	assert.Equal(t, "contains too many links (maximum is 1 for new accounts)", reason)
+	verdict, _ = check(links, &Content{Author: newbie, Text: "see https://a.example"})
+	assert.Equal(t, ContentAccept, verdict)
+	verdict, _ = check(NewLinkLimitFilter(1, time.Nanosecond, ContentHold), &Content{Author: newbie, Text: twoLinks})
+	assert.Equal(t, ContentAccept, verdict)
+
+	// Trùng lặp: so với nội dung cùng loại của author, không tính chính nội dung đang sửa
+	duplicate := NewDuplicateContentFilter(repos.Article, repos.Comment, time.Hour, ContentFlag)
+	verdict, reason = check(duplicate, &Content{Kind: ContentKindComment, Author: newbie, Body: "same"})
+	assert.Equal(t, ContentFlag, verdict)
+	assert.Equal(t, "duplicates content you posted recently", reason)
+	verdict, _ = check(duplicate, &Content{Kind: ContentKindComment, Author: newbie, ID: comment.ID, Body: "same"})
+	assert.Equal(t, ContentAccept, verdict)
+	verdict, _ = check(duplicate, &Content{Kind: ContentKindArticle, Author: newbie, Body: "same"})
+	assert.Equal(t, ContentAccept, verdict)
+	verdict, _ = check(duplicate, &Content{Kind: ContentKindArticle, Author: newbie, Body: "body"})
+	assert.Equal(t, ContentFlag, verdict)
+	verdict, _ = check(duplicate, &Content{Kind: ContentKindArticle, Author: newbie, ID: article.ID, Body: "body"})
+	assert.Equal(t, ContentAccept, verdict)
+
+	// Pipeline trả về verdict nặng nhất kèm lý do của mọi filters, dừng khi bị từ chối
+	pipeline := NewContentFilterPipeline(duplicate, links, banned)
+	decision, err := pipeline.Run(&Content{Kind: ContentKindComment, Author: newbie, Text: "hello", Body: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, ContentAccept, decision.Verdict)
+	assert.Empty(t, decision.Reasons)
+	decision, err = pipeline.Run(&Content{Kind: ContentKindComment, Author: newbie, Text: "same " + twoLinks, Body: "same"})
+	require.NoError(t, err)
+	assert.Equal(t, ContentHold, decision.Verdict)
+	assert.Equal(t, "content filter: duplicates content you posted recently; contains too many links (maximum is 1 for new accounts)", decision.Reason())
+	decision, err = pipeline.Run(&Content{Kind: ContentKindComment, Author: newbie, Text: "casino", Body: "casino"})
+	require.NoError(t, err)
+	assert.Equal(t, ContentReject, decision.Verdict)
+	assert.ErrorIs(t, decision.RejectError(), apperrors.ErrValidation)
+
+	// Pipeline nil cho qua mọi nội dung
+	var none *ContentFilterPipeline
+	decision, err = none.Run(&Content{Text: "casino"})
+	require.NoError(t, err)
+	assert.Equal(t, ContentAccept, decision.Verdict)
+
+	// checkContent dùng chung cho articles và comments: nil cho qua, bị từ chối trả về lỗi validation
+	decision, err = checkContent(none, &Content{Text: "casino"})
+	require.NoError(t, err)
+	assert.Equal(t, ContentAccept, decision.Verdict)
+	_, err = checkContent(pipeline, &Content{Kind: ContentKindComment, Author: newbie, Text: "casino", Body: "casino"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+// newTestContentFilter tạo pipeline dùng trong tests: từ cấm "casino" bị từ chối, account mới đăng
+// quá 1 link bị giữ lại, nội dung trùng lặp trong 1 giờ bị gắn cờ
+func newTestContentFilter(repos *repositories.Repositories) *ContentFilterPipeline {
+	return NewContentFilterPipeline(
+		NewBannedWordsFilter([]string{"casino"}, ContentReject),
+		NewLinkLimitFilter(1, time.Hour, ContentHold),
+		NewDuplicateContentFilter(repos.Article, repos.Comment, time.Hour, ContentFlag),
+	)
+}
+
+// TestArticleService_ContentFilter kiểm tra reject, hold và flag khi tạo/sửa article và hàng đợi duyệt articles
+func TestArticleService_ContentFilter(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	service := NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, newTestContentFilter(repos))
+	moderation := NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	twoLinks := "see https://a.example and https://b.example"
+
+	// moderate xử lý article bằng action
+	moderate := func(slug string, action string) error {
+		var req dto.ModerateArticleRequest
+		req.Moderation.Action = action
+		return moderation.ModerateArticle(slug, moderator.ID, req)
+	}
+	// queue lấy slugs trong hàng đợi duyệt articles
+	queue := func() []string {
+		list, err := moderation.ListArticlesForReview(moderator.ID, dto.ModerationQueueQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, len(list.Articles), list.ArticlesCount)
+		slugs := []string{}
+		for _, article := range list.Articles {
+			slugs = append(slugs, article.Slug)
+		}
+		return slugs
+	}
+
+	// Reject: article không được lưu, từ cấm trong title cũng bị chặn
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Casino Night"))
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"body": {"contains banned words"}}, appErr.Fields)
+	exists, err := repos.Article.IsSlugExists("casino-night")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Hold: article sẽ publish được lưu pending, chỉ author xem được và không tự publish được
+	req := newCreateArticleRequest("Link Dump")
+	req.Article.Body = twoLinks
+	held, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, held.Article.Status)
+	assert.Nil(t, held.Article.PublishedAt)
+	_, err = service.GetArticle("link-dump", &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	_, err = service.PublishArticle("link-dump", author.ID)
+	assert.ErrorIs(t, err, ErrArticlePendingReview)
+	var schedule dto.ScheduleArticleRequest
+	schedule.Article.PublishAt = time.Now().Add(time.Hour)
+	_, err = service.ScheduleArticle("link-dump", author.ID, schedule)
+	assert.ErrorIs(t, err, ErrArticlePendingReview)
+
+	// Hold với draft chỉ gắn cờ, draft vẫn là draft
+	req = newCreateArticleRequest("Draft Links")
+	req.Article.Body = twoLinks
+	req.Article.Status = models.ArticleStatusDraft
+	draft, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusDraft, draft.Article.Status)
+
+	// Flag: body trùng lặp vẫn được publish nhưng vào hàng đợi duyệt
+	_, err = service.CreateArticle(author.ID, newCreateArticleRequest("Original"))
+	require.NoError(t, err)
+	copied, err := service.CreateArticle(author.ID, newCreateArticleRequest("Copy"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, copied.Article.Status)
+	assert.Equal(t, []string{"link-dump", "draft-links", "copy"}, queue())
+	list, err := moderation.ListArticlesForReview(moderator.ID, dto.ModerationQueueQuery{Limit: 1, Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, list.Articles, 1)
+	assert.Equal(t, "content filter: duplicates content you posted recently", list.Articles[0].ReviewReason)
+	assert.Equal(t, "author", list.Articles[0].Author.Username)
+	_, err = moderation.ListArticlesForReview(reader.ID, dto.ModerationQueueQuery{})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	// Sửa article đã publish: nội dung bị giữ lại thì article chuyển về pending, bị từ chối thì không sửa
+	var update dto.UpdateArticleRequest
+	update.Article.Body = &twoLinks
+	updated, err := service.UpdateArticle("original", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, updated.Article.Status)
+	assert.NotNil(t, updated.Article.PublishedAt)
+	banned := "casino"
+	update.Article.Body = &banned
+	_, err = service.UpdateArticle("copy", author.ID, update)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, []string{"link-dump", "draft-links", "copy", "original"}, queue())
+
+	// Approve: article pending được publish, article khác giữ nguyên status; cả hai rời hàng đợi
+	var action dto.ModerateArticleRequest
+	action.Moderation.Action = models.ArticleActionApprove
+	assert.ErrorIs(t, moderation.ModerateArticle("link-dump", reader.ID, action), ErrPermissionDenied)
+	require.NoError(t, moderate("link-dump", models.ArticleActionApprove))
+	approved, err := service.GetArticle("link-dump", &reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, approved.Article.Status)
+	assert.NotNil(t, approved.Article.PublishedAt)
+	require.NoError(t, moderate("draft-links", models.ArticleActionApprove))
+	draft, err = service.GetArticle("draft-links", &author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusDraft, draft.Article.Status)
+	assert.ErrorIs(t, moderate("link-dump", models.ArticleActionApprove), apperrors.ErrBadRequest)
+
+	// Delete: article vào thùng rác của author
+	require.NoError(t, moderate("original", models.ArticleActionDelete))
+	_, err = service.GetArticle("original", &author.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	assert.Equal(t, []string{"copy"}, queue())
+}
+
+// TestArticleService_ContentFilterScheduled kiểm tra article hẹn giờ bị giữ lại vẫn giữ lịch publish_at,
+// được duyệt thì trở lại scheduled nếu lịch còn ở tương lai, ngược lại được publish ngay
+func TestArticleService_ContentFilterScheduled(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	service := NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, newTestContentFilter(repos))
+	moderation := NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	twoLinks := "see https://a.example and https://b.example"
+	var approve dto.ModerateArticleRequest
+	approve.Moderation.Action = models.ArticleActionApprove
+
+	// Tạo article hẹn giờ bị giữ lại: pending nhưng vẫn giữ publishAt, scheduler không publish
+	publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	req := newCreateArticleRequest("Scheduled Links")
+	req.Article.Body = twoLinks
+	req.Article.PublishAt = &publishAt
+	held, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, held.Article.Status)
+	require.NotNil(t, held.Article.PublishAt)
+	due, err := repos.Article.PublishDue(publishAt.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	// Approve khi lịch còn ở tương lai: article trở lại scheduled với lịch cũ, chưa publish
+	require.NoError(t, moderation.ModerateArticle("scheduled-links", moderator.ID, approve))
+	approved, err := service.GetArticle("scheduled-links", &author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusScheduled, approved.Article.Status)
+	assert.Equal(t, held.Article.PublishAt, approved.Article.PublishAt)
+	assert.Nil(t, approved.Article.PublishedAt)
+
+	// Sửa article scheduled bị giữ lại: pending nhưng lịch không bị xóa
+	var update dto.UpdateArticleRequest
+	body := twoLinks + " and more"
+	update.Article.Body = &body
+	updated, err := service.UpdateArticle("scheduled-links", author.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, updated.Article.Status)
+	assert.Equal(t, held.Article.PublishAt, updated.Article.PublishAt)
+
+	// Approve khi lịch đã qua: article được publish ngay
+	article, err := repos.Article.GetBySlug("scheduled-links")
+	require.NoError(t, err)
+	_, err = repos.Article.Schedule(article.ID, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, repos.Article.Hold(article.ID))
+	require.NoError(t, moderation.ModerateArticle("scheduled-links", moderator.ID, approve))
+	published, err := service.GetArticle("scheduled-links", &author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, published.Article.Status)
+	assert.NotNil(t, published.Article.PublishedAt)
+	assert.Nil(t, published.Article.PublishAt)
+}
+
+// TestArticleService_ContentFilterHeldDraft kiểm tra draft có nội dung bị giữ lại không được publish hay hẹn giờ
+// khi chưa được duyệt: article chuyển sang pending chờ moderator, duyệt xong thì publish như bình thường
+func TestArticleService_ContentFilterHeldDraft(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	service := NewArticleService(repos.UnitOfWork, repos.Article, repos.Tag, repos.User, repos.Follow, newTestContentFilter(repos))
+	moderation := NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	var approve dto.ModerateArticleRequest
+	approve.Moderation.Action = models.ArticleActionApprove
+
+	// createDraft tạo draft có quá nhiều links cho account mới
+	createDraft := func(title string) {
+		req := newCreateArticleRequest(title)
+		req.Article.Body = "see https://a.example and https://b.example"
+		req.Article.Status = models.ArticleStatusDraft
+		draft, err := service.CreateArticle(author.ID, req)
+		require.NoError(t, err)
+		require.Equal(t, models.ArticleStatusDraft, draft.Article.Status)
+	}
+
+	// Publish draft bị giữ lại: article chuyển sang pending, không xuất hiện công khai
+	createDraft("Held Draft")
+	held, err := service.PublishArticle("held-draft", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, held.Article.Status)
+	assert.Nil(t, held.Article.PublishedAt)
+	_, err = service.GetArticle("held-draft", &reader.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list.Articles)
+
+	// Duyệt xong thì article được publish
+	require.NoError(t, moderation.ModerateArticle("held-draft", moderator.ID, approve))
+	approved, err := service.GetArticle("held-draft", &reader.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, approved.Article.Status)
+
+	// Hẹn giờ draft bị giữ lại: article chuyển sang pending nhưng giữ lịch hẹn
+	createDraft("Held Schedule")
+	var schedule dto.ScheduleArticleRequest
+	schedule.Article.PublishAt = time.Now().Add(time.Hour)
+	scheduled, err := service.ScheduleArticle("held-schedule", author.ID, schedule)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPending, scheduled.Article.Status)
+	assert.NotNil(t, scheduled.Article.PublishAt)
+
+	// Draft đã được duyệt thì publish bình thường
+	createDraft("Approved Draft")
+	require.NoError(t, moderation.ModerateArticle("approved-draft", moderator.ID, approve))
+	published, err := service.PublishArticle("approved-draft", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ArticleStatusPublished, published.Article.Status)
+}
+
+// TestCommentService_ContentFilter kiểm tra reject, hold và flag khi đăng/sửa comment và duyệt comment bị giữ lại
+func TestCommentService_ContentFilter(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	commentService.filter = newTestContentFilter(repos)
+	moderation := NewModerationService(repos.UnitOfWork, repos.Article, repos.Comment, repos.User)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	spammer, _ := repos.User.Create("spammer", "spammer@example.com", "hash")
+	reader, _ := repos.User.Create("reader", "reader@example.com", "hash")
+	moderator, _ := repos.User.Create("moderator", "moderator@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(moderator.ID, models.UserRoleModerator))
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Discussed"))
+	require.NoError(t, err)
+
+	// add đăng comment gốc
+	add := func(userID int, body string) (*dto.CommentResponse, error) {
+		var req dto.CreateCommentRequest
+		req.Comment.Body = body
+		return commentService.AddComment("discussed", userID, req)
+	}
+	// visible lấy bodies của comments mà reader thấy
+	visible := func() []string {
+		list, err := commentService.GetComments("discussed", dto.CommentListQuery{Sort: "oldest"}, "", 0, &reader.ID)
+		require.NoError(t, err)
+		bodies := []string{}
+		for _, comment := range list.Comments {
+			bodies = append(bodies, comment.Comment.Body)
+		}
+		return bodies
+	}
+	// queue lấy hàng đợi kiểm duyệt comments
+	queue := func() []dto.ReportedComment {
+		list, err := moderation.ListReportedComments(moderator.ID, dto.ModerationQueueQuery{})
+		require.NoError(t, err)
+		return list.Comments
+	}
+
+	// Reject: comment không được lưu
+	_, err = add(spammer.ID, "Visit my CASINO")
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"body": {"contains banned words"}}, appErr.Fields)
+	assert.Empty(t, visible())
+
+	// Hold: comment được lưu ở trạng thái ẩn, author vẫn thấy nội dung, vào hàng đợi với report của content filter
+	held, err := add(spammer.ID, "see https://a.example and https://b.example")
+	require.NoError(t, err)
+	assert.True(t, held.Comment.Hidden)
+	assert.Equal(t, "see https://a.example and https://b.example", held.Comment.Body)
+	assert.Equal(t, "spammer", held.Comment.Author.Username)
+	assert.Empty(t, visible())
+	reported := queue()
+	require.Len(t, reported, 1)
+	assert.Equal(t, held.Comment.ID, reported[0].ID)
+	assert.True(t, reported[0].Hidden)
+	require.Len(t, reported[0].Reports, 1)
+	assert.Nil(t, reported[0].Reports[0].Reporter)
+	assert.Equal(t, "content filter: contains too many links (maximum is 1 for new accounts)", reported[0].Reports[0].Reason)
+
+	// Flag: comment trùng lặp vẫn hiển thị nhưng vào hàng đợi
+	first, err := add(spammer.ID, "first!")
+	require.NoError(t, err)
+	flagged, err := add(spammer.ID, "first!")
+	require.NoError(t, err)
+	assert.False(t, flagged.Comment.Hidden)
+	assert.Equal(t, []string{"first!", "first!"}, visible())
+	reported = queue()
+	require.Len(t, reported, 2)
+	assert.Equal(t, flagged.Comment.ID, reported[1].ID)
+	assert.Equal(t, "content filter: duplicates content you posted recently", reported[1].Reports[0].Reason)
+
+	// Sửa comment cũng qua content filter
+	var edit dto.UpdateCommentRequest
+	edit.Comment.Body = "casino"
+	_, err = commentService.UpdateComment("discussed", first.Comment.ID, spammer.ID, edit)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	edit.Comment.Body = "first! https://a.example https://b.example"
+	edited, err := commentService.UpdateComment("discussed", first.Comment.ID, spammer.ID, edit)
+	require.NoError(t, err)
+	assert.True(t, edited.Comment.Hidden)
+	assert.Equal(t, []string{"first!"}, visible())
+
+	// Approve: comment bị giữ lại hiện trở lại và rời hàng đợi
+	var req dto.ModerateCommentRequest
+	req.Moderation.Action = models.CommentActionApprove
+	require.NoError(t, moderation.ModerateComment(held.Comment.ID, moderator.ID, req))
+	assert.Equal(t, []string{"see https://a.example and https://b.example", "first!"}, visible())
+	reported = queue()
+	require.Len(t, reported, 2)
+	assert.Equal(t, flagged.Comment.ID, reported[0].ID)
+	actions, err := repos.Comment.GetActions([]int{held.Comment.ID})
+	require.NoError(t, err)
+	require.Len(t, actions[held.Comment.ID], 1)
+	assert.Equal(t, models.CommentActionApprove, actions[held.Comment.ID][0].Action)
+}
@@ -0,0 +1,21 @@
+package services
+
+import "news/apperrors"
+
+// MsgAlreadyTaken là message cho field bị trùng với dữ liệu đã có (email, username)
+const MsgAlreadyTaken = "has already been taken"
+
+// Các lỗi nghiệp vụ mà services trả về
+// Controllers không cần so sánh message, middlewares.ErrorHandler map Kind sang HTTP status.
+var (
+	ErrArticleNotFound      = apperrors.NotFound("article not found")
+	ErrCommentNotFound      = apperrors.NotFound("comment not found")
+	ErrRevisionNotFound     = apperrors.NotFound("revision not found")
+	ErrUserNotFound         = apperrors.NotFound("user not found")
+	ErrTagNotFound          = apperrors.NotFound("tag not found")
+	ErrTagAliasNotFound     = apperrors.NotFound("tag alias not found")
+	ErrPermissionDenied     = apperrors.Forbidden("permission denied")
+	ErrArticlePendingReview = apperrors.Forbidden("article is pending review")
+	ErrInvalidCredentials   = apperrors.Unauthorized("invalid email or password")
+	ErrCannotFollowSelf     = apperrors.BadRequest("cannot follow yourself")
+)
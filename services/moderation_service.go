@@ -0,0 +1,298 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"time"
+)
+
+// defaultModerationQueueLimit là số comments/articles mặc định mỗi trang của hàng đợi kiểm duyệt
+const defaultModerationQueueLimit = 20
+
+// ModerationService chứa business logic cho kiểm duyệt comments bị report và articles bị content filter
+// gửi lên, chỉ dành cho moderators
+// Reports được tạo bởi CommentService.ReportComment và content filter; service này liệt kê hàng đợi và xử lý từng mục.
+type ModerationService struct {
+	uow         repositories.UnitOfWork
+	articleRepo repositories.ArticleRepository
+	commentRepo repositories.CommentRepository
+	userRepo    repositories.UserRepository
+}
+
+// NewModerationService tạo instance mới của ModerationService
+func NewModerationService(uow repositories.UnitOfWork, articleRepo repositories.ArticleRepository, commentRepo repositories.CommentRepository, userRepo repositories.UserRepository) *ModerationService {
+	return &ModerationService{
+		uow:         uow,
+		articleRepo: articleRepo,
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ListReportedComments lấy hàng đợi kiểm duyệt: comments còn reports chưa xử lý, report sớm nhất trước
+// Mỗi comment kèm các reports chưa xử lý và lịch sử kiểm duyệt; số query không phụ thuộc vào số comments.
+func (s *ModerationService) ListReportedComments(userID int, query dto.ModerationQueueQuery) (*dto.ReportedCommentListResponse, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultModerationQueueLimit
+	}
+	reported, err := s.commentRepo.ListReported(limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.commentRepo.CountReported()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReportedCommentListResponse{
+		Comments:      make([]dto.ReportedComment, 0, len(reported)),
+		CommentsCount: count,
+	}
+	if len(reported) == 0 {
+		return response, nil
+	}
+
+	// Lấy reports và lịch sử kiểm duyệt theo lô
+	commentIDs := make([]int, 0, len(reported))
+	for _, item := range reported {
+		commentIDs = append(commentIDs, item.ID)
+	}
+	reports, err := s.commentRepo.GetOpenReports(commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := s.commentRepo.GetActions(commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy authors, reporters và người kiểm duyệt trong một query
+	userIDs := []int{}
+	for _, item := range reported {
+		userIDs = append(userIDs, item.AuthorID)
+		for _, report := range reports[item.ID] {
+			if report.ReporterID != nil {
+				userIDs = append(userIDs, *report.ReporterID)
+			}
+		}
+		for _, action := range actions[item.ID] {
+			userIDs = append(userIDs, action.ActorID)
+		}
+	}
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	// username trả về username của user, rỗng nếu user không còn tồn tại
+	username := func(id int) string {
+		if user, ok := users[id]; ok {
+			return user.Username
+		}
+		return ""
+	}
+
+	for _, item := range reported {
+		comment := dto.ReportedComment{
+			ID:              item.ID,
+			ArticleSlug:     item.ArticleSlug,
+			Body:            item.Body,
+			BodyHTML:        renderedBody(item.Body, item.BodyHTML),
+			Hidden:          item.IsHidden(),
+			CreatedAt:       item.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			FirstReportedAt: item.FirstReportedAt.Format("2006-01-02T15:04:05.000Z"),
+			ReportsCount:    item.ReportsCount,
+			Reports:         make([]dto.CommentReport, 0, len(reports[item.ID])),
+			Actions:         make([]dto.CommentModerationAction, 0, len(actions[item.ID])),
+		}
+		comment.Author.Username = username(item.AuthorID)
+		if author, ok := users[item.AuthorID]; ok {
+			comment.Author.Banned = author.IsBanned()
+		}
+		for _, report := range reports[item.ID] {
+			commentReport := dto.CommentReport{
+				Reason:    report.Reason,
+				CreatedAt: report.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			}
+			if report.ReporterID != nil {
+				reporter := username(*report.ReporterID)
+				commentReport.Reporter = &reporter
+			}
+			comment.Reports = append(comment.Reports, commentReport)
+		}
+		for _, action := range actions[item.ID] {
+			comment.Actions = append(comment.Actions, dto.CommentModerationAction{
+				Action:    action.Action,
+				Actor:     username(action.ActorID),
+				CreatedAt: action.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			})
+		}
+		response.Comments = append(response.Comments, comment)
+	}
+	return response, nil
+}
+
+// ModerateComment xử lý comment commentID bằng req.Moderation.Action, chỉ moderators
+//   - dismiss: giữ nguyên comment
+//   - approve: bỏ ẩn comment (ví dụ comment bị content filter giữ lại)
+//   - hide: ẩn comment khỏi danh sách comments của article
+//   - delete: ẩn comment và chuyển vào thùng rác của author (khôi phục lại vẫn bị ẩn)
+//   - ban: cấm author bình luận và ẩn comment
+//
+// Mọi reports chưa xử lý của comment được đánh dấu đã xử lý và hành động được ghi vào lịch sử kiểm duyệt
+// (ai, lúc nào) trong cùng transaction.
+func (s *ModerationService) ModerateComment(commentID, userID int, req dto.ModerateCommentRequest) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+	action := req.Moderation.Action
+	if !models.IsValidCommentAction(action) {
+		return apperrors.FieldError("action", "is invalid")
+	}
+
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return err
+	}
+	if comment == nil {
+		return ErrCommentNotFound
+	}
+
+	now := time.Now()
+	return s.uow.Do(func(tx *repositories.Repositories) error {
+		switch action {
+		case models.CommentActionDismiss:
+		case models.CommentActionApprove:
+			if err := tx.Comment.Unhide(comment.ID); err != nil {
+				return err
+			}
+		default:
+			if err := tx.Comment.Hide(comment.ID, now); err != nil {
+				return err
+			}
+		}
+		switch action {
+		case models.CommentActionDelete:
+			if err := tx.Comment.Delete(comment.ID); err != nil {
+				return err
+			}
+		case models.CommentActionBan:
+			if err := tx.User.SetBanned(comment.AuthorID, &now); err != nil {
+				return err
+			}
+		}
+		if err := tx.Comment.ResolveReports(comment.ID, now); err != nil {
+			return err
+		}
+		return tx.Comment.CreateAction(comment.ID, userID, action, now)
+	})
+}
+
+// ListArticlesForReview lấy hàng đợi articles bị content filter gửi lên, sửa sớm nhất trước, chỉ moderators
+// Article pending chỉ được publish sau khi moderator duyệt; article khác vẫn hiển thị bình thường.
+func (s *ModerationService) ListArticlesForReview(userID int, query dto.ModerationQueueQuery) (*dto.ReviewArticleListResponse, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultModerationQueueLimit
+	}
+	articles, err := s.articleRepo.ListForReview(limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.articleRepo.CountForReview()
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy authors trong một query
+	authorIDs := make([]int, 0, len(articles))
+	for _, article := range articles {
+		authorIDs = append(authorIDs, article.AuthorID)
+	}
+	authors, err := s.userRepo.GetByIDs(authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReviewArticleListResponse{
+		Articles:      make([]dto.ReviewArticle, 0, len(articles)),
+		ArticlesCount: count,
+	}
+	for _, article := range articles {
+		item := dto.ReviewArticle{
+			Slug:        article.Slug,
+			Title:       article.Title,
+			Description: article.Description,
+			Body:        article.Body,
+			Status:      article.Status,
+			UpdatedAt:   article.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
+		}
+		if article.ReviewReason != nil {
+			item.ReviewReason = *article.ReviewReason
+		}
+		if author, ok := authors[article.AuthorID]; ok {
+			item.Author.Username = author.Username
+			item.Author.Banned = author.IsBanned()
+		}
+		response.Articles = append(response.Articles, item)
+	}
+	return response, nil
+}
+
+// ModerateArticle xử lý article slug đang chờ xem xét bằng req.Moderation.Action, chỉ moderators
+//   - approve: article pending được publish, hoặc trở lại scheduled nếu lịch hẹn publish_at còn ở tương lai;
+//     article khác giữ nguyên status; article rời hàng đợi
+//   - delete: chuyển article vào thùng rác của author (khôi phục lại thì vẫn chờ xem xét)
+func (s *ModerationService) ModerateArticle(slug string, userID int, req dto.ModerateArticleRequest) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+	action := req.Moderation.Action
+	if action != models.ArticleActionApprove && action != models.ArticleActionDelete {
+		return apperrors.FieldError("action", "is invalid")
+	}
+
+	article, err := findArticleBySlug(s.articleRepo, slug)
+	if err != nil {
+		return err
+	}
+	if article.ReviewReason == nil {
+		return apperrors.BadRequest("article is not awaiting review")
+	}
+
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		if action == models.ArticleActionDelete {
+			return tx.Article.Delete(article.ID)
+		}
+		if article.IsPending() && article.PublishAt != nil && article.PublishAt.After(time.Now()) {
+			if _, err := tx.Article.Schedule(article.ID, *article.PublishAt); err != nil {
+				return err
+			}
+		} else if article.IsPending() {
+			publishedAt := article.PublishedAt
+			if publishedAt == nil {
+				now := time.Now()
+				publishedAt = &now
+			}
+			if _, err := tx.Article.SetStatus(article.ID, models.ArticleStatusPublished, publishedAt); err != nil {
+				return err
+			}
+		}
+		return tx.Article.SetReviewReason(article.ID, nil)
+	})
+	if err != nil {
+		return err
+	}
+	invalidateTagStats()
+	return nil
+}
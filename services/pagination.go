@@ -0,0 +1,29 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/repositories"
+)
+
+// decodeCursor giải mã cursor client gửi lên, chuỗi rỗng nghĩa là không dùng cursor
+func decodeCursor(cursor string) (*repositories.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	after, err := repositories.DecodeCursor(cursor)
+	if err != nil {
+		return nil, apperrors.FieldError("cursor", "is invalid")
+	}
+	return after, nil
+}
+
+// nextCursor trả về cursor của trang tiếp theo
+// Repository được gọi với limit+1 bản ghi: nếu nhận đủ fetched > limit thì còn trang sau,
+// last là cursor của bản ghi cuối cùng trong trang hiện tại.
+func nextCursor(fetched, limit int, last repositories.Cursor) *string {
+	if limit <= 0 || fetched <= limit {
+		return nil
+	}
+	cursor := last.Encode()
+	return &cursor
+}
@@ -0,0 +1,28 @@
+package services
+
+import "news/repositories"
+
+// requireAdmin kiểm tra user userID có quyền admin, ngược lại trả về ErrPermissionDenied
+// Role được đọc lại từ database mỗi lần nên thu hồi quyền có hiệu lực ngay, không chờ token hết hạn.
+func requireAdmin(userRepo repositories.UserRepository, userID int) error {
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsAdmin() {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// requireModerator kiểm tra user userID có quyền moderator (hoặc admin), ngược lại trả về ErrPermissionDenied
+func requireModerator(userRepo repositories.UserRepository, userID int) error {
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsModerator() {
+		return ErrPermissionDenied
+	}
+	return nil
+}
@@ -1,22 +1,21 @@
 package services
 
 import (
-	"errors"
 	"news/dto"
 	"news/repositories"
 )
 
 // ProfileService chứa business logic cho profiles
 type ProfileService struct {
-	userRepo   *repositories.UserRepository
-	followRepo *repositories.FollowRepository
+	userRepo   repositories.UserRepository
+	followRepo repositories.FollowRepository
 }
 
 // NewProfileService tạo instance mới của ProfileService
-func NewProfileService() *ProfileService {
+func NewProfileService(userRepo repositories.UserRepository, followRepo repositories.FollowRepository) *ProfileService {
 	return &ProfileService{
-		userRepo:   repositories.NewUserRepository(),
-		followRepo: repositories.NewFollowRepository(),
+		userRepo:   userRepo,
+		followRepo: followRepo,
 	}
 }
 
@@ -29,7 +28,7 @@ func (s *ProfileService) GetProfile(username string, currentUserID *int) (*dto.P
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Tạo response
@@ -63,12 +62,12 @@ func (s *ProfileService) FollowUser(followerID int, username string) (*dto.Profi
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Không thể follow chính mình
 	if followerID == user.ID {
-		return nil, errors.New("cannot follow yourself")
+		return nil, ErrCannotFollowSelf
 	}
 
 	// Kiểm tra xem đã follow chưa
@@ -103,7 +102,7 @@ func (s *ProfileService) UnfollowUser(followerID int, username string) (*dto.Pro
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Xóa relationship follow
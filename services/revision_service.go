@@ -0,0 +1,209 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"news/utils"
+)
+
+// RevisionService chứa business logic cho lịch sử chỉnh sửa articles
+// Revisions được ghi bởi ArticleService khi tạo/sửa article; service này chỉ đọc, so sánh và khôi phục.
+type RevisionService struct {
+	articleService *ArticleService
+	articleRepo    repositories.ArticleRepository
+	revisionRepo   repositories.RevisionRepository
+	userRepo       repositories.UserRepository
+}
+
+// NewRevisionService tạo instance mới của RevisionService
+// articleService dùng để khôi phục revision như một lần sửa article bình thường.
+func NewRevisionService(articleService *ArticleService, articleRepo repositories.ArticleRepository, revisionRepo repositories.RevisionRepository, userRepo repositories.UserRepository) *RevisionService {
+	return &RevisionService{
+		articleService: articleService,
+		articleRepo:    articleRepo,
+		revisionRepo:   revisionRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// ListRevisions lấy danh sách revisions của article, mới nhất trước
+// Ai xem được article thì xem được lịch sử của nó.
+func (s *RevisionService) ListRevisions(slug string, currentUserID *int) (*dto.RevisionListResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.ListByArticleID(article.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy editors theo lô
+	editorIDs := []int{}
+	seen := map[int]bool{}
+	for _, revision := range revisions {
+		if !seen[revision.EditorID] {
+			seen[revision.EditorID] = true
+			editorIDs = append(editorIDs, revision.EditorID)
+		}
+	}
+	editors, err := s.userRepo.GetByIDs(editorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RevisionListResponse{
+		Revisions:      make([]dto.RevisionSummary, 0, len(revisions)),
+		RevisionsCount: len(revisions),
+	}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, revisionSummary(revision, editors[revision.EditorID]))
+	}
+	return response, nil
+}
+
+// GetRevision lấy toàn bộ nội dung của revision number
+func (s *RevisionService) GetRevision(slug string, number int, currentUserID *int) (*dto.RevisionResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.findRevision(article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	editor, err := s.userRepo.GetByID(revision.EditorID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RevisionResponse{}
+	response.Revision.RevisionSummary = revisionSummary(revision, editor)
+	response.Revision.Description = revision.Description
+	response.Revision.Body = revision.Body
+	response.Revision.BodyHTML = renderedBody(revision.Body, revision.BodyHTML)
+	return response, nil
+}
+
+// DiffRevisions so sánh từng dòng title, description và body giữa hai revisions
+// query.To mặc định là revision mới nhất, query.From mặc định là revision ngay trước To.
+// Revision 1 không có revision trước nên được so với nội dung rỗng.
+func (s *RevisionService) DiffRevisions(slug string, query dto.RevisionDiffQuery, currentUserID *int) (*dto.RevisionDiffResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := query.To
+	if to == 0 {
+		revisions, err := s.revisionRepo.ListByArticleID(article.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			return nil, ErrRevisionNotFound
+		}
+		to = revisions[0].Number
+	}
+	toRevision, err := s.findRevision(article.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	from := query.From
+	if from == 0 {
+		from = to - 1
+	}
+	fromRevision := &models.ArticleRevision{}
+	if from > 0 {
+		fromRevision, err = s.findRevision(article.ID, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &dto.RevisionDiffResponse{}
+	response.Diff.From = from
+	response.Diff.To = to
+	response.Diff.Title = diffLines(fromRevision.Title, toRevision.Title, response)
+	response.Diff.Description = diffLines(fromRevision.Description, toRevision.Description, response)
+	response.Diff.Body = diffLines(fromRevision.Body, toRevision.Body, response)
+	return response, nil
+}
+
+// RestoreRevision khôi phục nội dung article về revision number, chỉ author được khôi phục
+// Khôi phục là một lần sửa article bình thường nên tạo revision mới, lịch sử không bị mất;
+// title thay đổi thì slug cũng đổi theo như UpdateArticle.
+func (s *RevisionService) RestoreRevision(slug string, number, authorID int) (*dto.ArticleResponse, error) {
+	article, err := findVisibleArticle(s.articleRepo, slug, &authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra quyền sở hữu
+	if article.AuthorID != authorID {
+		return nil, ErrPermissionDenied
+	}
+
+	revision, err := s.findRevision(article.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.UpdateArticleRequest
+	req.Article.Title = &revision.Title
+	req.Article.Description = &revision.Description
+	req.Article.Body = &revision.Body
+	return s.articleService.UpdateArticle(article.Slug, authorID, req)
+}
+
+// findRevision lấy revision number của article, không tồn tại thì trả về ErrRevisionNotFound
+func (s *RevisionService) findRevision(articleID, number int) (*models.ArticleRevision, error) {
+	if number < 1 {
+		return nil, apperrors.BadRequest("invalid revision number")
+	}
+	revision, err := s.revisionRepo.GetByNumber(articleID, number)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// revisionSummary build RevisionSummary từ revision và editor (nil nếu không tìm thấy)
+func revisionSummary(revision *models.ArticleRevision, editor *models.User) dto.RevisionSummary {
+	summary := dto.RevisionSummary{
+		Number:    revision.Number,
+		Title:     revision.Title,
+		CreatedAt: revision.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+	}
+	if editor != nil {
+		summary.Editor.Username = editor.Username
+		summary.Editor.Bio = editor.Bio
+		summary.Editor.Image = editor.Image
+	}
+	return summary
+}
+
+// diffLines diff oldText và newText, cộng số dòng thêm/xóa vào response
+func diffLines(oldText, newText string, response *dto.RevisionDiffResponse) []dto.DiffLine {
+	lines := utils.DiffLines(oldText, newText)
+	result := make([]dto.DiffLine, 0, len(lines))
+	for _, line := range lines {
+		switch line.Op {
+		case utils.DiffInsert:
+			response.Diff.Additions++
+		case utils.DiffDelete:
+			response.Diff.Deletions++
+		}
+		result = append(result, dto.DiffLine{Op: string(line.Op), Text: line.Text})
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"news/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRevisionService_HistoryDiffAndRestore kiểm tra lưu revision khi sửa, diff và khôi phục revision cũ
+func TestRevisionService_HistoryDiffAndRestore(t *testing.T) {
+	service, repos := newTestArticleService()
+	revisions := NewRevisionService(service, repos.Article, repos.Revision, repos.User)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	other, _ := repos.User.Create("other", "other@example.com", "hash")
+
+	req := newCreateArticleRequest("Original")
+	req.Article.Body = "line one\nline two"
+	_, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+
+	// Sửa body tạo revision 2, sửa không đổi nội dung thì không tạo revision
+	body := "line one\nline 2\nline three"
+	var update dto.UpdateArticleRequest
+	update.Article.Body = &body
+	_, err = service.UpdateArticle("original", author.ID, update)
+	require.NoError(t, err)
+	_, err = service.UpdateArticle("original", author.ID, update)
+	require.NoError(t, err)
+
+	list, err := revisions.ListRevisions("original", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, list.RevisionsCount)
+	assert.Equal(t, 2, list.Revisions[0].Number)
+	assert.Equal(t, 1, list.Revisions[1].Number)
+	assert.Equal(t, "author", list.Revisions[0].Editor.Username)
+
+	first, err := revisions.GetRevision("original", 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\nline two", first.Revision.Body)
+
+	_, err = revisions.GetRevision("original", 5, nil)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+
+	// Mặc định so revision mới nhất với revision trước nó
+	diff, err := revisions.DiffRevisions("original", dto.RevisionDiffQuery{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.Diff.From)
+	assert.Equal(t, 2, diff.Diff.To)
+	assert.Equal(t, []dto.DiffLine{
+		{Op: "equal", Text: "line one"},
+		{Op: "delete", Text: "line two"},
+		{Op: "insert", Text: "line 2"},
+		{Op: "insert", Text: "line three"},
+	}, diff.Diff.Body)
+	assert.Equal(t, 2, diff.Diff.Additions)
+	assert.Equal(t, 1, diff.Diff.Deletions)
+
+	// Chỉ author được khôi phục
+	_, err = revisions.RestoreRevision("original", 1, other.ID)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	restored, err := revisions.RestoreRevision("original", 1, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\nline two", restored.Article.Body)
+
+	// Khôi phục tạo revision mới, lịch sử cũ vẫn còn
+	list, err = revisions.ListRevisions("original", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.RevisionsCount)
+	latest, err := revisions.GetRevision("original", 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\nline two", latest.Revision.Body)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tagStatsGeneration tăng mỗi khi articles thay đổi theo cách ảnh hưởng tới số articles của tags
+// (tạo, sửa tags, publish/unpublish, xóa, khôi phục). Là biến của package để mọi service trong
+// process (API và background jobs) cùng làm mất hiệu lực cache; giữa nhiều replica thì dựa vào TTL.
+var tagStatsGeneration atomic.Uint64
+
+// invalidateTagStats làm mất hiệu lực mọi kết quả tag stats đã cache
+func invalidateTagStats() {
+	tagStatsGeneration.Add(1)
+}
+
+// maxTagStatsEntries giới hạn số keys trong cache, đầy thì xóa hết (keys phụ thuộc query params của client)
+const maxTagStatsEntries = 256
+
+// tagStatsCache cache kết quả list/trending tags theo key trong ttl
+// Entry hết hiệu lực khi quá ttl hoặc khi articles thay đổi (invalidateTagStats).
+type tagStatsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]tagStatsEntry
+}
+
+// tagStatsEntry là một kết quả đã cache
+type tagStatsEntry struct {
+	value      interface{}
+	generation uint64
+	expiresAt  time.Time
+}
+
+// newTagStatsCache tạo cache rỗng, ttl <= 0 là không cache
+func newTagStatsCache(ttl time.Duration) *tagStatsCache {
+	return &tagStatsCache{
+		ttl:     ttl,
+		entries: map[string]tagStatsEntry{},
+	}
+}
+
+// get lấy kết quả đã cache của key nếu còn hiệu lực
+func (c *tagStatsCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.generation != tagStatsGeneration.Load() || !time.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set lưu kết quả của key, generation là tagStatsGeneration đọc trước khi query
+// để thay đổi xảy ra trong lúc query không bị che bởi kết quả cũ.
+func (c *tagStatsCache) set(key string, value interface{}, generation uint64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxTagStatsEntries {
+		c.entries = map[string]tagStatsEntry{}
+	}
+	c.entries[key] = tagStatsEntry{
+		value:      value,
+		generation: generation,
+		expiresAt:  time.Now().Add(c.ttl),
+	}
+}
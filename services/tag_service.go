@@ -1,40 +1,317 @@
 package services
 
 import (
+	"fmt"
+	"news/apperrors"
 	"news/dto"
+	"news/models"
 	"news/repositories"
+	"news/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Giới hạn mặc định của danh sách tags
+const (
+	defaultPopularTagsLimit  = 20
+	defaultTrendingTagsLimit = 10
+	defaultTrendingWindow    = 7 * 24 * time.Hour
+	maxTrendingWindow        = 90 * 24 * time.Hour
 )
 
 // TagService chứa business logic cho tags
+// Quản lý aliases và gộp tags chỉ dành cho admin.
 type TagService struct {
-	tagRepo *repositories.TagRepository
+	uow      repositories.UnitOfWork
+	tagRepo  repositories.TagRepository
+	userRepo repositories.UserRepository
+	cache    *tagStatsCache
 }
 
 // NewTagService tạo instance mới của TagService
-func NewTagService() *TagService {
+// uow dùng để gộp tags trong một transaction; danh sách tags kèm số articles được cache trong cacheTTL
+// (<= 0 là không cache) và bị xóa khỏi cache ngay khi articles thay đổi.
+func NewTagService(uow repositories.UnitOfWork, tagRepo repositories.TagRepository, userRepo repositories.UserRepository, cacheTTL time.Duration) *TagService {
 	return &TagService{
-		tagRepo: repositories.NewTagRepository(),
+		uow:      uow,
+		tagRepo:  tagRepo,
+		userRepo: userRepo,
+		cache:    newTagStatsCache(cacheTTL),
 	}
 }
 
-// GetAllTags lấy tất cả tags
-func (s *TagService) GetAllTags() (*dto.TagListResponse, error) {
-	// Lấy tất cả tags từ repository
-	tags, err := s.tagRepo.GetAll()
-	if err != nil {
-		return nil, err
+// GetAllTags lấy tags đang dùng bởi articles đã publish kèm số articles
+// query.Sort = "popular" sắp xếp nhiều articles nhất trước và mặc định chỉ lấy 20 tags.
+func (s *TagService) GetAllTags(query dto.TagListQuery) (*dto.TagListResponse, error) {
+	sort := repositories.TagSort(query.Sort)
+	if !sort.IsValid() {
+		return nil, apperrors.FieldError("sort", "is invalid")
+	}
+	limit := query.Limit
+	if limit == 0 && sort == repositories.TagSortPopular {
+		limit = defaultPopularTagsLimit
 	}
 
-	// Convert sang string array
-	tagNames := make([]string, len(tags))
-	for i, tag := range tags {
-		tagNames[i] = tag.Name
+	key := fmt.Sprintf("tags:%s:%d", sort, limit)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(*dto.TagListResponse), nil
+	}
+	generation := tagStatsGeneration.Load()
+
+	// Lấy tags kèm số articles từ repository
+	tags, err := s.tagRepo.GetCounts(sort, limit)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build response
 	response := &dto.TagListResponse{
-		Tags: tagNames,
+		Tags:      make([]string, 0, len(tags)),
+		TagCounts: make([]dto.TagCount, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, tag.Name)
+		response.TagCounts = append(response.TagCounts, dto.TagCount{Name: tag.Name, ArticlesCount: tag.ArticlesCount})
+	}
+
+	s.cache.set(key, response, generation)
+	return response, nil
+}
+
+// GetTrendingTags xếp hạng tags theo mức tăng số articles publish trong window gần nhất
+// so với window liền trước (mặc định 7 ngày), chỉ lấy tags có article trong window gần nhất.
+func (s *TagService) GetTrendingTags(query dto.TrendingTagsQuery) (*dto.TrendingTagsResponse, error) {
+	window, err := parseTrendingWindow(query.Window)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultTrendingTagsLimit
+	}
+
+	key := fmt.Sprintf("trending:%d:%d", window, limit)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(*dto.TrendingTagsResponse), nil
+	}
+	generation := tagStatsGeneration.Load()
+
+	tags, err := s.tagRepo.GetTrending(time.Now(), window, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TrendingTagsResponse{
+		Window: formatTrendingWindow(window),
+		Tags:   make([]dto.TrendingTag, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, dto.TrendingTag{
+			Name:          tag.Name,
+			ArticlesCount: tag.CurrentCount,
+			PreviousCount: tag.PreviousCount,
+			Growth:        tag.CurrentCount - tag.PreviousCount,
+		})
+	}
+
+	s.cache.set(key, response, generation)
+	return response, nil
+}
+
+// parseTrendingWindow parse window dạng "7d" hoặc duration của Go ("12h"), rỗng là 7 ngày
+// Window phải từ 1 giờ tới 90 ngày.
+func parseTrendingWindow(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultTrendingWindow, nil
+	}
+
+	var window time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		window, err = time.ParseDuration(value)
+	}
+	if err != nil || window < time.Hour || window > maxTrendingWindow {
+		return 0, apperrors.FieldError("window", "is invalid")
+	}
+	return window, nil
+}
+
+// formatTrendingWindow định dạng window như client gửi: số ngày ("7d") nếu chẵn ngày, ngược lại duration của Go
+func formatTrendingWindow(window time.Duration) string {
+	day := 24 * time.Hour
+	if window%day == 0 {
+		return strconv.Itoa(int(window/day)) + "d"
+	}
+	return window.String()
+}
+
+// ListAliases lấy tất cả aliases, chỉ admin
+func (s *TagService) ListAliases(userID int) (*dto.TagAliasListResponse, error) {
+	if err := requireAdmin(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	aliases, err := s.tagRepo.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TagAliasListResponse{
+		Aliases: make([]dto.TagAlias, 0, len(aliases)),
+	}
+	for _, alias := range aliases {
+		response.Aliases = append(response.Aliases, tagAliasResponse(alias))
+	}
+	return response, nil
+}
+
+// CreateAlias tạo alias cho tag, chỉ admin
+// Alias không được trùng với một tag đang có (khi đó cần gộp tags) hay một alias khác.
+// Tag đích là alias thì alias mới trỏ tới tag gốc của nó.
+func (s *TagService) CreateAlias(userID int, req dto.CreateTagAliasRequest) (*dto.TagAliasResponse, error) {
+	if err := requireAdmin(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	alias, err := utils.NormalizeTag(req.Alias.Alias)
+	if err != nil {
+		return nil, apperrors.FieldError("alias", err.Error())
+	}
+	tag, err := s.findTag("tag", req.Alias.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.tagRepo.GetByName(alias)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Name == alias {
+			return nil, apperrors.FieldError("alias", "is an existing tag, merge it instead")
+		}
+		return nil, apperrors.FieldError("alias", MsgAlreadyTaken)
+	}
+
+	if err := s.tagRepo.CreateAlias(alias, tag.ID); err != nil {
+		if repositories.IsDuplicateEntry(err) {
+			return nil, apperrors.FieldError("alias", MsgAlreadyTaken)
+		}
+		return nil, err
+	}
+
+	created, err := s.findAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TagAliasResponse{Alias: tagAliasResponse(created)}, nil
+}
+
+// DeleteAlias xóa alias, chỉ admin
+// Articles đã gắn tag qua alias vẫn giữ tag gốc.
+func (s *TagService) DeleteAlias(userID int, alias string) error {
+	if err := requireAdmin(s.userRepo, userID); err != nil {
+		return err
+	}
+
+	alias, err := utils.NormalizeTag(alias)
+	if err != nil {
+		return ErrTagAliasNotFound
 	}
+	deleted, err := s.tagRepo.DeleteAlias(alias)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTagAliasNotFound
+	}
+	return nil
+}
 
+// MergeTags gộp tag from vào tag to, chỉ admin
+// Articles của from được gắn sang to, aliases của from chuyển sang to, from bị xóa và
+// tên của nó trở thành alias của to để các lần gắn tag sau vẫn ra to.
+func (s *TagService) MergeTags(userID int, req dto.MergeTagsRequest) (*dto.MergeTagsResponse, error) {
+	if err := requireAdmin(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findTag("from", req.Merge.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findTag("to", req.Merge.To)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return nil, apperrors.FieldError("to", "must be different from from")
+	}
+
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		if err := tx.Tag.Merge(from.ID, to.ID); err != nil {
+			return err
+		}
+		return tx.Tag.CreateAlias(from.Name, to.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateTagStats()
+
+	aliases, err := s.tagRepo.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+	response := &dto.MergeTagsResponse{Tag: to.Name, Aliases: []string{}}
+	for _, alias := range aliases {
+		if alias.TagID == to.ID {
+			response.Aliases = append(response.Aliases, alias.Alias)
+		}
+	}
 	return response, nil
 }
+
+// findTag chuẩn hóa name rồi tìm tag (name hoặc alias), không tồn tại thì trả về ErrTagNotFound
+func (s *TagService) findTag(field, name string) (*models.Tag, error) {
+	canonical, err := utils.NormalizeTag(name)
+	if err != nil {
+		return nil, apperrors.FieldError(field, err.Error())
+	}
+	tag, err := s.tagRepo.GetByName(canonical)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// findAlias lấy alias vừa tạo kèm tên tag gốc
+func (s *TagService) findAlias(name string) (*models.TagAlias, error) {
+	aliases, err := s.tagRepo.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if alias.Alias == name {
+			return alias, nil
+		}
+	}
+	return nil, ErrTagAliasNotFound
+}
+
+// tagAliasResponse build dto.TagAlias từ alias
+func tagAliasResponse(alias *models.TagAlias) dto.TagAlias {
+	return dto.TagAlias{
+		Alias:     alias.Alias,
+		Tag:       alias.TagName,
+		CreatedAt: alias.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"news/apperrors"
+	"news/dto"
+	"news/models"
+	"news/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTagService_NormalizeAliasesAndMerge kiểm tra chuẩn hóa tên tags, aliases và gộp tags
+func TestTagService_NormalizeAliasesAndMerge(t *testing.T) {
+	service, repos := newTestArticleService()
+	tagService := NewTagService(repos.UnitOfWork, repos.Tag, repos.User, 0)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	admin, _ := repos.User.Create("admin", "admin@example.com", "hash")
+	require.NoError(t, repos.User.SetRole(admin.ID, models.UserRoleAdmin))
+
+	// Các cách viết khác nhau thành cùng một tag
+	created, err := service.CreateArticle(author.ID, newCreateArticleRequest("Go Intro", "Go", " go ", "Web Dev"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "web-dev"}, created.Article.TagList)
+
+	_, err = service.CreateArticle(author.ID, newCreateArticleRequest("Bad Tag", "go/web"))
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"tagList": {"may only contain letters, digits, spaces and - _ . + #"}}, appErr.Fields)
+
+	// Chỉ admin quản lý aliases
+	var aliasReq dto.CreateTagAliasRequest
+	aliasReq.Alias.Alias = "Golang"
+	aliasReq.Alias.Tag = "go"
+	_, err = tagService.CreateAlias(author.ID, aliasReq)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	alias, err := tagService.CreateAlias(admin.ID, aliasReq)
+	require.NoError(t, err)
+	assert.Equal(t, "golang", alias.Alias.Alias)
+	assert.Equal(t, "go", alias.Alias.Tag)
+
+	// Alias trùng tag đang có hoặc alias khác bị từ chối
+	aliasReq.Alias.Alias = "web-dev"
+	_, err = tagService.CreateAlias(admin.ID, aliasReq)
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"alias": {"is an existing tag, merge it instead"}}, appErr.Fields)
+	aliasReq.Alias.Alias = "golang"
+	_, err = tagService.CreateAlias(admin.ID, aliasReq)
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"alias": {MsgAlreadyTaken}}, appErr.Fields)
+
+	// Gắn tag bằng alias được lưu thành tag gốc, lọc theo alias cũng ra tag gốc
+	aliased, err := service.CreateArticle(author.ID, newCreateArticleRequest("Golang Tips", "golang"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, aliased.Article.TagList)
+	list, err := service.ListArticles(dto.ArticleListQuery{Tag: []string{"Golang"}}, "", 20, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.ArticlesCount)
+
+	// Gộp "web-dev" vào "go": articles chuyển sang "go", "web-dev" thành alias
+	_, err = service.CreateArticle(author.ID, newCreateArticleRequest("Web Only", "web dev"))
+	require.NoError(t, err)
+	var mergeReq dto.MergeTagsRequest
+	mergeReq.Merge.From = "Web Dev"
+	mergeReq.Merge.To = "golang"
+	_, err = tagService.MergeTags(author.ID, mergeReq)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	merged, err := tagService.MergeTags(admin.ID, mergeReq)
+	require.NoError(t, err)
+	assert.Equal(t, "go", merged.Tag)
+	assert.Equal(t, []string{"golang", "web-dev"}, merged.Aliases)
+
+	article, err := service.GetArticle("go-intro", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, article.Article.TagList)
+	article, err = service.GetArticle("web-only", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, article.Article.TagList)
+	tags, err := tagService.GetAllTags(dto.TagListQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, tags.Tags)
+
+	mergeReq.Merge.From = "go"
+	_, err = tagService.MergeTags(admin.ID, mergeReq)
+	appErr = apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"to": {"must be different from from"}}, appErr.Fields)
+
+	// Xóa alias, tag gốc giữ nguyên
+	require.NoError(t, tagService.DeleteAlias(admin.ID, "golang"))
+	assert.ErrorIs(t, tagService.DeleteAlias(admin.ID, "golang"), ErrTagAliasNotFound)
+	aliases, err := tagService.ListAliases(admin.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases.Aliases, 1)
+	assert.Equal(t, "web-dev", aliases.Aliases[0].Alias)
+}
+
+// TestTagService_PopularAndTrending kiểm tra số articles của tags, trending tags và cache
+func TestTagService_PopularAndTrending(t *testing.T) {
+	service, repos := newTestArticleService()
+	tagService := NewTagService(repos.UnitOfWork, repos.Tag, repos.User, time.Hour)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+
+	// publishedDaysAgo tạo article đã publish từ days ngày trước
+	publishedDaysAgo := func(title string, days int, tags ...string) {
+		created, err := service.CreateArticle(author.ID, newCreateArticleRequest(title, tags...))
+		require.NoError(t, err)
+		article, err := repos.Article.GetBySlug(created.Article.Slug)
+		require.NoError(t, err)
+		publishedAt := time.Now().Add(-time.Duration(days)*24*time.Hour - time.Minute)
+		_, err = repos.Article.SetStatus(article.ID, models.ArticleStatusPublished, &publishedAt)
+		require.NoError(t, err)
+	}
+	publishedDaysAgo("Go One", 1, "go", "web")
+	publishedDaysAgo("Go Two", 2, "go")
+	publishedDaysAgo("Rust One", 3, "rust")
+	publishedDaysAgo("Rust Two", 8, "rust")
+	publishedDaysAgo("Rust Three", 9, "rust")
+	publishedDaysAgo("Web Old", 10, "web")
+	req := newCreateArticleRequest("Draft", "go")
+	req.Article.Status = models.ArticleStatusDraft
+	_, err := service.CreateArticle(author.ID, req)
+	require.NoError(t, err)
+
+	// Mặc định sắp xếp theo tên, draft không được tính
+	tags, err := tagService.GetAllTags(dto.TagListQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "rust", "web"}, tags.Tags)
+	assert.Equal(t, dto.TagCount{Name: "go", ArticlesCount: 2}, tags.TagCounts[0])
+
+	popular, err := tagService.GetAllTags(dto.TagListQuery{Sort: "popular", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.TagCount{{Name: "rust", ArticlesCount: 3}, {Name: "go", ArticlesCount: 2}}, popular.TagCounts)
+
+	_, err = tagService.GetAllTags(dto.TagListQuery{Sort: "random"})
+	appErr := apperrors.As(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, map[string][]string{"sort": {"is invalid"}}, appErr.Fields)
+
+	// Trending xếp theo mức tăng so với window trước, tag không có article gần đây bị bỏ qua
+	trending, err := tagService.GetTrendingTags(dto.TrendingTagsQuery{Window: "7d"})
+	require.NoError(t, err)
+	assert.Equal(t, "7d", trending.Window)
+	assert.Equal(t, []dto.TrendingTag{
+		{Name: "go", ArticlesCount: 2, PreviousCount: 0, Growth: 2},
+		{Name: "web", ArticlesCount: 1, PreviousCount: 1, Growth: 0},
+		{Name: "rust", ArticlesCount: 1, PreviousCount: 2, Growth: -1},
+	}, trending.Tags)
+
+	short, err := tagService.GetTrendingTags(dto.TrendingTagsQuery{Window: "36h", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "36h0m0s", short.Window)
+	assert.Equal(t, []dto.TrendingTag{{Name: "web", ArticlesCount: 1, PreviousCount: 0, Growth: 1}}, short.Tags)
+
+	for _, window := range []string{"0d", "30m", "91d", "week"} {
+		_, err = tagService.GetTrendingTags(dto.TrendingTagsQuery{Window: window})
+		appErr = apperrors.As(err)
+		require.NotNil(t, appErr, window)
+		assert.Equal(t, map[string][]string{"window": {"is invalid"}}, appErr.Fields)
+	}
+
+	// Kết quả được cache tới khi articles thay đổi
+	require.NoError(t, repos.Article.Delete(mustArticleID(t, repos, "go-one")))
+	cached, err := tagService.GetAllTags(dto.TagListQuery{Sort: "popular", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, popular, cached)
+
+	_, err = service.PublishArticle("draft", author.ID)
+	require.NoError(t, err)
+	fresh, err := tagService.GetAllTags(dto.TagListQuery{Sort: "popular", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.TagCount{{Name: "rust", ArticlesCount: 3}, {Name: "go", ArticlesCount: 2}}, fresh.TagCounts)
+	trending, err = tagService.GetTrendingTags(dto.TrendingTagsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, dto.TrendingTag{Name: "go", ArticlesCount: 2, PreviousCount: 0, Growth: 2}, trending.Tags[0])
+	assert.Equal(t, "rust", trending.Tags[1].Name)
+}
+
+// mustArticleID lấy id của article theo slug
+func mustArticleID(t *testing.T, repos *repositories.Repositories, slug string) int {
+	t.Helper()
+	article, err := repos.Article.GetBySlug(slug)
+	require.NoError(t, err)
+	require.NotNil(t, article)
+	return article.ID
+}
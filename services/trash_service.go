@@ -0,0 +1,163 @@
+package services
+
+import (
+	"news/dto"
+	"news/repositories"
+	"time"
+)
+
+// TrashService chứa business logic cho thùng rác: liệt kê, khôi phục và xóa hẳn articles/comments đã xóa
+// Mỗi user chỉ thấy và khôi phục được những gì mình đã viết.
+type TrashService struct {
+	uow            repositories.UnitOfWork
+	articleService *ArticleService
+	commentService *CommentService
+	articleRepo    repositories.ArticleRepository
+	commentRepo    repositories.CommentRepository
+	retention      time.Duration
+}
+
+// NewTrashService tạo instance mới của TrashService
+// retention là thời gian một dòng nằm trong thùng rác trước khi bị PurgeTrash xóa hẳn.
+func NewTrashService(uow repositories.UnitOfWork, articleService *ArticleService, commentService *CommentService, articleRepo repositories.ArticleRepository, commentRepo repositories.CommentRepository, retention time.Duration) *TrashService {
+	return &TrashService{
+		uow:            uow,
+		articleService: articleService,
+		commentService: commentService,
+		articleRepo:    articleRepo,
+		commentRepo:    commentRepo,
+		retention:      retention,
+	}
+}
+
+// GetTrash lấy articles và comments của user còn khôi phục được, mới xóa trước
+// Comments thuộc article cũng đang bị xóa không được liệt kê vì phải khôi phục article trước.
+func (s *TrashService) GetTrash(userID int) (*dto.TrashResponse, error) {
+	cutoff := time.Now().Add(-s.retention)
+
+	articles, err := s.articleRepo.ListDeleted(userID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.commentRepo.ListDeleted(userID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TrashResponse{
+		Articles: make([]dto.TrashArticle, 0, len(articles)),
+		Comments: make([]dto.TrashComment, 0, len(comments)),
+	}
+	for _, article := range articles {
+		response.Articles = append(response.Articles, dto.TrashArticle{
+			Slug:        article.Slug,
+			Title:       article.Title,
+			Description: article.Description,
+			DeletedAt:   article.DeletedAt.Format("2006-01-02T15:04:05.000Z"),
+			ExpiresAt:   article.DeletedAt.Add(s.retention).Format("2006-01-02T15:04:05.000Z"),
+		})
+	}
+
+	// Slug của article chứa comment, "" nếu article không còn
+	slugs := map[int]string{}
+	for _, comment := range comments {
+		slug, ok := slugs[comment.ArticleID]
+		if !ok {
+			article, err := s.articleRepo.GetByID(comment.ArticleID)
+			if err != nil {
+				return nil, err
+			}
+			if article != nil {
+				slug = article.Slug
+			}
+			slugs[comment.ArticleID] = slug
+		}
+		if slug == "" {
+			continue
+		}
+		response.Comments = append(response.Comments, dto.TrashComment{
+			ID:          comment.ID,
+			Body:        comment.Body,
+			ArticleSlug: slug,
+			DeletedAt:   comment.DeletedAt.Format("2006-01-02T15:04:05.000Z"),
+			ExpiresAt:   comment.DeletedAt.Add(s.retention).Format("2006-01-02T15:04:05.000Z"),
+		})
+	}
+
+	return response, nil
+}
+
+// RestoreArticle khôi phục article trong thùng rác của author
+// Article đã quá thời gian lưu giữ hoặc không thuộc về author được coi như không tồn tại.
+func (s *TrashService) RestoreArticle(slug string, authorID int) (*dto.ArticleResponse, error) {
+	article, err := s.articleRepo.GetDeletedBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if article == nil || article.AuthorID != authorID || s.expired(*article.DeletedAt) {
+		return nil, ErrArticleNotFound
+	}
+
+	if err := s.articleRepo.Restore(article.ID); err != nil {
+		return nil, err
+	}
+	invalidateTagStats()
+
+	return s.articleService.buildArticleResponse(article.ID, &authorID)
+}
+
+// RestoreComment khôi phục comment trong thùng rác của author
+// Article chứa comment phải còn tồn tại (không nằm trong thùng rác).
+func (s *TrashService) RestoreComment(commentID, authorID int) (*dto.CommentResponse, error) {
+	comment, err := s.commentRepo.GetDeletedByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.AuthorID != authorID || s.expired(*comment.DeletedAt) {
+		return nil, ErrCommentNotFound
+	}
+
+	article, err := s.articleRepo.GetByID(comment.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if article == nil {
+		return nil, ErrArticleNotFound
+	}
+
+	// Số replies của các comments cha được cập nhật trong cùng transaction
+	err = s.uow.Do(func(tx *repositories.Repositories) error {
+		return tx.Comment.Restore(comment.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentService.buildCommentResponse(comment.ID, &authorID)
+}
+
+// PurgeTrash xóa hẳn tối đa limit dòng đã nằm trong thùng rác quá thời gian lưu giữ tính tới now
+// Articles được xóa trước (kéo theo comments, favorites, tags của chúng), phần limit còn lại dành cho comments.
+// Trả về tổng số articles và comments đã xóa.
+func (s *TrashService) PurgeTrash(now time.Time, limit int) (int, error) {
+	cutoff := now.Add(-s.retention)
+
+	articles, err := s.articleRepo.PurgeDeleted(cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	if articles >= limit {
+		return articles, nil
+	}
+
+	comments, err := s.commentRepo.PurgeDeleted(cutoff, limit-articles)
+	if err != nil {
+		return articles, err
+	}
+	return articles + comments, nil
+}
+
+// expired kiểm tra một dòng xóa lúc deletedAt đã quá thời gian lưu giữ chưa
+func (s *TrashService) expired(deletedAt time.Time) bool {
+	return !deletedAt.After(time.Now().Add(-s.retention))
+}
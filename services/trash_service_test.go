@@ -0,0 +1,87 @@
+package services
+
+import (
+	"news/dto"
+	"news/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrashService_DeleteRestoreAndPurge kiểm tra xóa mềm, khôi phục trong thời gian lưu giữ và xóa hẳn khi quá hạn
+func TestTrashService_DeleteRestoreAndPurge(t *testing.T) {
+	service, repos := newTestArticleService()
+	commentService := newTestCommentService(repos)
+	trash := NewTrashService(repos.UnitOfWork, service, commentService, repos.Article, repos.Comment, time.Hour)
+	author, _ := repos.User.Create("author", "author@example.com", "hash")
+	other, _ := repos.User.Create("other", "other@example.com", "hash")
+
+	_, err := service.CreateArticle(author.ID, newCreateArticleRequest("Trashed", "go"))
+	require.NoError(t, err)
+	var commentReq dto.CreateCommentRequest
+	commentReq.Comment.Body = "nice"
+	comment, err := commentService.AddComment("trashed", other.ID, commentReq)
+	require.NoError(t, err)
+
+	// Comment đã xóa không còn trong danh sách nhưng nằm trong thùng rác của author comment
+	require.NoError(t, commentService.DeleteComment("trashed", comment.Comment.ID, other.ID))
+	comments, err := commentService.GetComments("trashed", dto.CommentListQuery{}, "", 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, comments.Comments)
+
+	otherTrash, err := trash.GetTrash(other.ID)
+	require.NoError(t, err)
+	require.Len(t, otherTrash.Comments, 1)
+	assert.Equal(t, "trashed", otherTrash.Comments[0].ArticleSlug)
+
+	// Article đã xóa biến mất khỏi get, list và tags
+	require.NoError(t, service.DeleteArticle("trashed", author.ID))
+	_, err = service.GetArticle("trashed", &author.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	list, err := service.ListArticles(dto.ArticleListQuery{}, "", 20, 0, nil)
+	require.NoError(t, err)
+	assert.Zero(t, list.ArticlesCount)
+	tags, err := repos.Tag.GetCounts(repositories.TagSortName, 0)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	// Comment thuộc article đang bị xóa không khôi phục được
+	otherTrash, err = trash.GetTrash(other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, otherTrash.Comments)
+	_, err = trash.RestoreComment(comment.Comment.ID, other.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+
+	// Chỉ author khôi phục được article
+	_, err = trash.RestoreArticle("trashed", other.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	authorTrash, err := trash.GetTrash(author.ID)
+	require.NoError(t, err)
+	require.Len(t, authorTrash.Articles, 1)
+
+	restored, err := trash.RestoreArticle("trashed", author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, restored.Article.TagList)
+
+	restoredComment, err := trash.RestoreComment(comment.Comment.ID, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "nice", restoredComment.Comment.Body)
+
+	// Chưa quá hạn thì không bị xóa hẳn
+	require.NoError(t, service.DeleteArticle("trashed", author.ID))
+	purged, err := trash.PurgeTrash(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	// Quá hạn: không khôi phục được nữa và bị xóa hẳn, slug được giải phóng
+	purged, err = trash.PurgeTrash(time.Now().Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	_, err = trash.RestoreArticle("trashed", author.ID)
+	assert.ErrorIs(t, err, ErrArticleNotFound)
+	exists, err := repos.Article.IsSlugExists("trashed")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"news/controllers"
-	"news/database"
+	"news/config"
 	"news/dto"
-	"news/middlewares"
+	"news/repositories"
+	"news/routes"
 	"strconv"
 	"testing"
 
@@ -18,60 +18,21 @@ import (
 )
 
 // setupTestRouter tạo router cho testing
+// Backend được chọn theo STORAGE_DRIVER: mặc định MySQL, đặt STORAGE_DRIVER=memory để chạy không cần database
 func setupTestRouter() *gin.Engine {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
-	// Init database
-	err := database.InitDB()
+	// Init repositories
+	cfg := config.LoadConfig()
+	repos, err := repositories.NewRepositories(cfg)
 	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
+		panic("Failed to initialize storage: " + err.Error())
 	}
 
 	// Setup router như trong main.go
 	router := gin.New()
-	router.Use(middlewares.ErrorHandler())
-	router.Use(middlewares.AuthMiddleware())
-
-	// Khởi tạo controllers
-	authController := controllers.NewAuthController()
-	profileController := controllers.NewProfileController()
-	articleController := controllers.NewArticleController()
-	commentController := controllers.NewCommentController()
-	tagController := controllers.NewTagController()
-
-	// API routes
-	api := router.Group("/api")
-	{
-		// Authentication routes
-		api.POST("/users", authController.Register)
-		api.POST("/users/login", authController.Login)
-		api.GET("/user", middlewares.RequireAuth(), authController.GetCurrentUser)
-		api.PUT("/user", middlewares.RequireAuth(), authController.UpdateCurrentUser)
-
-		// Profile routes
-		api.GET("/profiles/:username", profileController.GetProfile)
-		api.POST("/profiles/:username/follow", middlewares.RequireAuth(), profileController.FollowUser)
-		api.DELETE("/profiles/:username/follow", middlewares.RequireAuth(), profileController.UnfollowUser)
-
-		// Article routes
-		api.GET("/articles", articleController.ListArticles)
-		api.GET("/articles/feed", middlewares.RequireAuth(), articleController.FeedArticles)
-		api.GET("/articles/:slug", articleController.GetArticle)
-		api.POST("/articles", middlewares.RequireAuth(), articleController.CreateArticle)
-		api.PUT("/articles/:slug", middlewares.RequireAuth(), articleController.UpdateArticle)
-		api.DELETE("/articles/:slug", middlewares.RequireAuth(), articleController.DeleteArticle)
-		api.POST("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.FavoriteArticle)
-		api.DELETE("/articles/:slug/favorite", middlewares.RequireAuth(), articleController.UnfavoriteArticle)
-
-		// Comment routes
-		api.POST("/articles/:slug/comments", middlewares.RequireAuth(), commentController.AddComment)
-		api.GET("/articles/:slug/comments", commentController.GetComments)
-		api.DELETE("/articles/:slug/comments/:id", middlewares.RequireAuth(), commentController.DeleteComment)
-
-		// Tag routes
-		api.GET("/tags", tagController.GetTags)
-	}
+	routes.Setup(router, repos, cfg)
 
 	return router
 }
@@ -152,19 +113,11 @@ func TestArticleCRUD(t *testing.T) {
 	token := registerAndLogin(t, router, "articleuser", "article@example.com")
 
 	// 2. Create article
-	createReq := dto.CreateArticleRequest{
-		Article: struct {
-			Title       string   `json:"title" binding:"required"`
-			Description string   `json:"description" binding:"required"`
-			Body        string   `json:"body" binding:"required"`
-			TagList     []string `json:"tagList,omitempty"`
-		}{
-			Title:       "Test Article",
-			Description: "Test Description",
-			Body:        "Test Body Content",
-			TagList:     []string{"test", "go"},
-		},
-	}
+	var createReq dto.CreateArticleRequest
+	createReq.Article.Title = "Test Article"
+	createReq.Article.Description = "Test Description"
+	createReq.Article.Body = "Test Body Content"
+	createReq.Article.TagList = []string{"test", "go"}
 
 	createBody, _ := json.Marshal(createReq)
 	createReqHTTP := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(createBody))
@@ -193,15 +146,10 @@ func TestArticleCRUD(t *testing.T) {
 	assert.Equal(t, "Test Article", getResp.Article.Title)
 
 	// 4. Update article
-	updateReq := dto.UpdateArticleRequest{
-		Article: struct {
-			Title       *string `json:"title,omitempty"`
-			Description *string `json:"description,omitempty"`
-			Body        *string `json:"body,omitempty"`
-		}{
-			Title: stringPtr("Updated Test Article"),
-		},
-	}
+	var updateReq dto.UpdateArticleRequest
+	updateReq.Article.Title = stringPtr("Updated Test Article")
+	updateReq.Article.AddTags = []string{"api"}
+	updateReq.Article.RemoveTags = []string{"test"}
 
 	updateBody, _ := json.Marshal(updateReq)
 	updateReqHTTP := httptest.NewRequest("PUT", "/api/articles/"+articleSlug, bytes.NewBuffer(updateBody))
@@ -215,6 +163,7 @@ func TestArticleCRUD(t *testing.T) {
 	err = json.Unmarshal(updateW.Body.Bytes(), &updateResp)
 	require.NoError(t, err)
 	assert.Equal(t, "Updated Test Article", updateResp.Article.Title)
+	assert.Equal(t, []string{"api", "go"}, updateResp.Article.TagList)
 
 	// 5. Delete article
 	deleteReqHTTP := httptest.NewRequest("DELETE", "/api/articles/"+updateResp.Article.Slug, nil)
@@ -241,13 +190,8 @@ func TestCommentFlow(t *testing.T) {
 	articleSlug := createArticle(t, router, token, "Comment Test Article", "Test", "Body")
 
 	// 2. Add comment
-	commentReq := dto.CreateCommentRequest{
-		Comment: struct {
-			Body string `json:"body" binding:"required"`
-		}{
-			Body: "This is a test comment",
-		},
-	}
+	var commentReq dto.CreateCommentRequest
+	commentReq.Comment.Body = "This is a test comment"
 
 	commentBody, _ := json.Marshal(commentReq)
 	addCommentReqHTTP := httptest.NewRequest("POST", "/api/articles/"+articleSlug+"/comments", bytes.NewBuffer(commentBody))
@@ -379,6 +323,294 @@ func TestProfileFlow(t *testing.T) {
 	assert.False(t, unfollowResp.Profile.Following)
 }
 
+// TestSlugRedirect test slug cũ được redirect 301 sau khi đổi title
+func TestSlugRedirect(t *testing.T) {
+	router := setupTestRouter()
+
+	token := registerAndLogin(t, router, "sluguser", "slug@example.com")
+	oldSlug := createArticle(t, router, token, "Slug Before Rename", "Description", "Body")
+	require.NotEmpty(t, oldSlug)
+
+	// Đổi title để sinh slug mới
+	updateBody, _ := json.Marshal(map[string]interface{}{
+		"article": map[string]string{"title": "Slug After Rename " + oldSlug},
+	})
+	updateReqHTTP := httptest.NewRequest("PUT", "/api/articles/"+oldSlug, bytes.NewBuffer(updateBody))
+	updateReqHTTP.Header.Set("Content-Type", "application/json")
+	updateReqHTTP.Header.Set("Authorization", "Token "+token)
+	updateW := httptest.NewRecorder()
+	router.ServeHTTP(updateW, updateReqHTTP)
+
+	require.Equal(t, http.StatusOK, updateW.Code)
+	var updateResp dto.ArticleResponse
+	require.NoError(t, json.Unmarshal(updateW.Body.Bytes(), &updateResp))
+	newSlug := updateResp.Article.Slug
+	require.NotEqual(t, oldSlug, newSlug)
+
+	// GET bằng slug cũ trả về 301 kèm Location và canonicalSlug
+	getW := httptest.NewRecorder()
+	router.ServeHTTP(getW, httptest.NewRequest("GET", "/api/articles/"+oldSlug, nil))
+	assert.Equal(t, http.StatusMovedPermanently, getW.Code)
+	assert.Equal(t, "/api/articles/"+newSlug, getW.Header().Get("Location"))
+	assert.JSONEq(t, `{"canonicalSlug": "`+newSlug+`"}`, getW.Body.String())
+
+	commentsW := httptest.NewRecorder()
+	router.ServeHTTP(commentsW, httptest.NewRequest("GET", "/api/articles/"+oldSlug+"/comments", nil))
+	assert.Equal(t, http.StatusMovedPermanently, commentsW.Code)
+	assert.Equal(t, "/api/articles/"+newSlug+"/comments", commentsW.Header().Get("Location"))
+
+	// Request ghi bằng slug cũ không bị redirect mà áp dụng lên article hiện tại
+	favoriteReqHTTP := httptest.NewRequest("POST", "/api/articles/"+oldSlug+"/favorite", nil)
+	favoriteReqHTTP.Header.Set("Authorization", "Token "+token)
+	favoriteW := httptest.NewRecorder()
+	router.ServeHTTP(favoriteW, favoriteReqHTTP)
+	assert.Equal(t, http.StatusOK, favoriteW.Code)
+	var favoriteResp dto.ArticleResponse
+	require.NoError(t, json.Unmarshal(favoriteW.Body.Bytes(), &favoriteResp))
+	assert.Equal(t, newSlug, favoriteResp.Article.Slug)
+
+	// Slug mới trả về bình thường
+	newW := httptest.NewRecorder()
+	router.ServeHTTP(newW, httptest.NewRequest("GET", "/api/articles/"+newSlug, nil))
+	assert.Equal(t, http.StatusOK, newW.Code)
+}
+
+// TestSearchArticles test tìm kiếm articles qua GET /api/articles/search
+func TestSearchArticles(t *testing.T) {
+	router := setupTestRouter()
+
+	token := registerAndLogin(t, router, "searchuser", "search@example.com")
+	slug := createArticle(t, router, token, "Searchable xylophone article", "Description", "Body about a xylophone")
+	require.NotEmpty(t, slug)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/search?q=xylophone&author=searchuser", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ArticleSearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Articles)
+	found := false
+	for _, result := range resp.Articles {
+		if result.Article.Slug == slug {
+			found = true
+			assert.Contains(t, result.Snippet, "<mark>xylophone</mark>")
+		}
+	}
+	assert.True(t, found)
+
+	// Thiếu q trả về lỗi validation
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/search", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+// TestListArticlesFilters test filter nhiều giá trị và sort qua GET /api/articles
+func TestListArticlesFilters(t *testing.T) {
+	router := setupTestRouter()
+
+	firstToken := registerAndLogin(t, router, "filterfirst", "filterfirst@example.com")
+	secondToken := registerAndLogin(t, router, "filtersecond", "filtersecond@example.com")
+	firstSlug := createArticle(t, router, firstToken, "Filter first article", "Description", "Body")
+	secondSlug := createArticle(t, router, secondToken, "Filter second article", "Description", "Body")
+	require.NotEmpty(t, firstSlug)
+	require.NotEmpty(t, secondSlug)
+
+	// Cả dạng phân cách bằng dấu phẩy và dạng lặp lại đều nhận nhiều authors
+	for _, query := range []string{"author=filterfirst,filtersecond", "author=filterfirst&author=filtersecond"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles?sort=oldest&"+query, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp dto.ArticleListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var slugs []string
+		for _, article := range resp.Articles {
+			assert.Contains(t, []string{"filterfirst", "filtersecond"}, article.Article.Author.Username)
+			slugs = append(slugs, article.Article.Slug)
+		}
+		// Dữ liệu của lần chạy trước có thể còn trong database nên chỉ kiểm tra thứ tự tương đối
+		require.Contains(t, slugs, firstSlug, query)
+		require.Contains(t, slugs, secondSlug, query)
+		assert.Less(t, indexOf(slugs, firstSlug), indexOf(slugs, secondSlug))
+	}
+
+	// Sort không hợp lệ trả về lỗi validation
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles?sort=popular", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"sort":["is invalid"]`)
+}
+
+// TestDraftPublishFlow test draft chỉ author xem được và publish qua POST /api/articles/:slug/publish
+func TestDraftPublishFlow(t *testing.T) {
+	router := setupTestRouter()
+
+	token := registerAndLogin(t, router, "draftuser", "draft@example.com")
+
+	var createReq dto.CreateArticleRequest
+	createReq.Article.Title = "Draft flow article"
+	createReq.Article.Description = "Description"
+	createReq.Article.Body = "Body"
+	createReq.Article.Status = "draft"
+	createBody, _ := json.Marshal(createReq)
+	createReqHTTP := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(createBody))
+	createReqHTTP.Header.Set("Content-Type", "application/json")
+	createReqHTTP.Header.Set("Authorization", "Token "+token)
+	createW := httptest.NewRecorder()
+	router.ServeHTTP(createW, createReqHTTP)
+	require.Equal(t, http.StatusOK, createW.Code)
+
+	var createResp dto.ArticleResponse
+	require.NoError(t, json.Unmarshal(createW.Body.Bytes(), &createResp))
+	slug := createResp.Article.Slug
+	assert.Equal(t, "draft", createResp.Article.Status)
+	assert.Nil(t, createResp.Article.PublishedAt)
+
+	// Người khác không thấy draft
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Author publish
+	publishReq := httptest.NewRequest("POST", "/api/articles/"+slug+"/publish", nil)
+	publishReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, publishReq)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var publishResp dto.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &publishResp))
+	assert.Equal(t, "published", publishResp.Article.Status)
+	assert.NotNil(t, publishResp.Article.PublishedAt)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Status không hợp lệ trả về lỗi validation
+	createReq.Article.Status = "scheduled"
+	createBody, _ = json.Marshal(createReq)
+	invalidReq := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(createBody))
+	invalidReq.Header.Set("Content-Type", "application/json")
+	invalidReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, invalidReq)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":["is invalid"]`)
+}
+
+// TestArticleRevisions test lịch sử chỉnh sửa: list, diff và restore revision
+func TestArticleRevisions(t *testing.T) {
+	router := setupTestRouter()
+
+	token := registerAndLogin(t, router, "revisionuser", "revision@example.com")
+	otherToken := registerAndLogin(t, router, "revisionother", "revisionother@example.com")
+	slug := createArticle(t, router, token, "Revision article", "Description", "First body")
+
+	// Sửa body tạo revision 2
+	var updateReq dto.UpdateArticleRequest
+	updateReq.Article.Body = stringPtr("Second body")
+	updateBody, _ := json.Marshal(updateReq)
+	updateReqHTTP := httptest.NewRequest("PUT", "/api/articles/"+slug, bytes.NewBuffer(updateBody))
+	updateReqHTTP.Header.Set("Content-Type", "application/json")
+	updateReqHTTP.Header.Set("Authorization", "Token "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, updateReqHTTP)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug+"/revisions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var listResp dto.RevisionListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Equal(t, 2, listResp.RevisionsCount)
+	assert.Equal(t, 2, listResp.Revisions[0].Number)
+	assert.Equal(t, "revisionuser", listResp.Revisions[0].Editor.Username)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug+"/revisions/1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var revisionResp dto.RevisionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revisionResp))
+	assert.Equal(t, "First body", revisionResp.Revision.Body)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug+"/revisions/diff?from=1&to=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var diffResp dto.RevisionDiffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diffResp))
+	assert.Equal(t, []dto.DiffLine{{Op: "delete", Text: "First body"}, {Op: "insert", Text: "Second body"}}, diffResp.Diff.Body)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug+"/revisions/9", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Chỉ author được restore
+	restoreReq := httptest.NewRequest("POST", "/api/articles/"+slug+"/revisions/1/restore", nil)
+	restoreReq.Header.Set("Authorization", "Token "+otherToken)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, restoreReq)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	restoreReq = httptest.NewRequest("POST", "/api/articles/"+slug+"/revisions/1/restore", nil)
+	restoreReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, restoreReq)
+	require.Equal(t, http.StatusOK, w.Code)
+	var restoreResp dto.ArticleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restoreResp))
+	assert.Equal(t, "First body", restoreResp.Article.Body)
+}
+
+// TestTrashFlow test xóa mềm article, xem thùng rác và khôi phục
+func TestTrashFlow(t *testing.T) {
+	router := setupTestRouter()
+
+	token := registerAndLogin(t, router, "trashuser", "trash@example.com")
+	slug := createArticle(t, router, token, "Trash flow article", "Description", "Body")
+
+	deleteReq := httptest.NewRequest("DELETE", "/api/articles/"+slug, nil)
+	deleteReq.Header.Set("Authorization", "Token "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, deleteReq)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	trashReq := httptest.NewRequest("GET", "/api/user/trash", nil)
+	trashReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, trashReq)
+	require.Equal(t, http.StatusOK, w.Code)
+	var trashResp dto.TrashResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trashResp))
+	var slugs []string
+	for _, article := range trashResp.Articles {
+		slugs = append(slugs, article.Slug)
+	}
+	assert.Contains(t, slugs, slug)
+
+	restoreReq := httptest.NewRequest("POST", "/api/user/trash/articles/"+slug+"/restore", nil)
+	restoreReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, restoreReq)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/articles/"+slug, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Article không còn trong thùng rác
+	restoreReq = httptest.NewRequest("POST", "/api/user/trash/articles/"+slug+"/restore", nil)
+	restoreReq.Header.Set("Authorization", "Token "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, restoreReq)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // Helper functions
 
 // registerAndLogin helper để register và login, trả về token
@@ -432,18 +664,10 @@ func registerAndLogin(t *testing.T, router *gin.Engine, username, email string)
 
 // createArticle helper để tạo article, trả về slug
 func createArticle(t *testing.T, router *gin.Engine, token, title, description, body string) string {
-	createReq := dto.CreateArticleRequest{
-		Article: struct {
-			Title       string   `json:"title" binding:"required"`
-			Description string   `json:"description" binding:"required"`
-			Body        string   `json:"body" binding:"required"`
-			TagList     []string `json:"tagList,omitempty"`
-		}{
-			Title:       title,
-			Description: description,
-			Body:        body,
-		},
-	}
+	var createReq dto.CreateArticleRequest
+	createReq.Article.Title = title
+	createReq.Article.Description = description
+	createReq.Article.Body = body
 
 	createBody, _ := json.Marshal(createReq)
 	createReqHTTP := httptest.NewRequest("POST", "/api/articles", bytes.NewBuffer(createBody))
@@ -457,6 +681,16 @@ func createArticle(t *testing.T, router *gin.Engine, token, title, description,
 	return createResp.Article.Slug
 }
 
+// indexOf trả về vị trí của value trong values, -1 nếu không có
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
 // stringPtr helper để tạo pointer từ string
 func stringPtr(s string) *string {
 	return &s
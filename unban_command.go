@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"news/database"
+	"news/repositories"
+)
+
+// unbanUsage hướng dẫn sử dụng subcommand unban
+const unbanUsage = "usage: news unban <username>"
+
+// runUnbanCommand xử lý subcommand "unban <username>": bỏ cấm bình luận của user bị moderator ban
+// Luôn chạy trên MySQL, không phụ thuộc STORAGE_DRIVER.
+func runUnbanCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New(unbanUsage)
+	}
+	username := args[0]
+
+	if err := database.InitDB(); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.CloseDB()
+
+	userRepo := repositories.NewUserRepository(database.DB)
+	user, err := userRepo.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %q not found", username)
+	}
+	if err := userRepo.SetBanned(user.ID, nil); err != nil {
+		return err
+	}
+
+	log.Printf("%s is no longer banned", username)
+	return nil
+}
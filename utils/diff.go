@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// DiffOp là loại thay đổi của một dòng trong diff
+type DiffOp string
+
+const (
+	// DiffEqual: dòng có ở cả hai phiên bản
+	DiffEqual DiffOp = "equal"
+	// DiffInsert: dòng chỉ có ở phiên bản mới
+	DiffInsert DiffOp = "insert"
+	// DiffDelete: dòng chỉ có ở phiên bản cũ
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine là một dòng trong kết quả diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// maxDiffEdits là số dòng thêm/xóa tối đa mà DiffLines tìm edit script ngắn nhất
+// Hai phiên bản khác nhau nhiều hơn thì đoạn khác nhau (sau khi bỏ phần đầu và cuối giống nhau) được
+// coi là bị thay toàn bộ, để thời gian diff bị chặn trên O((N+M)·maxDiffEdits) với mọi input.
+const maxDiffEdits = 1000
+
+// DiffLines so sánh từng dòng giữa oldText và newText bằng thuật toán Myers (bản bộ nhớ tuyến tính)
+// Kết quả là chuỗi thao tác ngắn nhất biến oldText thành newText (hoặc thay toàn bộ đoạn khác nhau
+// khi vượt maxDiffEdits); trong một đoạn bị thay, các dòng delete đứng trước các dòng insert.
+func DiffLines(oldText, newText string) []DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	// Bỏ phần đầu và cuối giống nhau trước khi chạy Myers
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	d := &differ{a: a, b: b}
+	d.equal(0, prefix)
+	aLo, aHi, bLo, bHi := prefix, len(a)-suffix, prefix, len(b)-suffix
+	if !d.diff(aLo, aHi, bLo, bHi, maxDiffEdits) {
+		d.replace(aLo, aHi, bLo, bHi)
+	}
+	d.equal(len(a)-suffix, len(a))
+	return groupChanges(d.lines)
+}
+
+// splitLines tách text thành các dòng, chuẩn hóa "\r\n" thành "\n"; text rỗng không có dòng nào
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// differ dựng edit script giữa a và b theo kiểu chia để trị của Myers:
+// tìm "middle snake" của edit script ngắn nhất rồi diff đệ quy hai nửa trước và sau nó.
+// Bộ nhớ O(N+M): vf/vb được dùng lại giữa các lần gọi middleSnake.
+type differ struct {
+	a, b   []string
+	vf, vb []int
+	lines  []DiffLine
+}
+
+// diff thêm edit script ngắn nhất giữa a[aLo:aHi] và b[bLo:bHi] vào d.lines
+// Trả về false (không thêm gì) nếu edit script cần nhiều hơn maxEdits thao tác.
+func (d *differ) diff(aLo, aHi, bLo, bHi, maxEdits int) bool {
+	if aLo == aHi || bLo == bHi {
+		if (aHi-aLo)+(bHi-bLo) > maxEdits {
+			return false
+		}
+		d.replace(aLo, aHi, bLo, bHi)
+		return true
+	}
+
+	edits, x, y, u, v, ok := d.middleSnake(aLo, aHi, bLo, bHi, maxEdits)
+	if !ok {
+		return false
+	}
+	if edits <= 1 {
+		d.diffOne(aLo, aHi, bLo, bHi)
+		return true
+	}
+
+	// Mỗi nửa cần ít thao tác hơn cả đoạn nên không cần giới hạn nữa
+	unlimited := (aHi - aLo) + (bHi - bLo)
+	d.diff(aLo, x, bLo, y, unlimited)
+	d.equal(x, u)
+	d.diff(u, aHi, v, bHi, unlimited)
+	return true
+}
+
+// middleSnake chạy Myers đồng thời từ đầu và từ cuối a[aLo:aHi], b[bLo:bHi] tới khi hai phía gặp nhau
+// Trả về số thao tác của edit script ngắn nhất và snake (x, y) -> (u, v) ở giữa nó (tọa độ tuyệt đối);
+// ok = false nếu số thao tác vượt maxEdits.
+// vf[k] là x xa nhất từ đầu trên đường chéo k = x - y, vb[c] là số dòng đi được từ cuối
+// trên đường chéo c = (n - x) - (m - y) của phía ngược lại.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi, maxEdits int) (edits, x, y, u, v int, ok bool) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	if limit := maxEdits/2 + 1; maxD > limit {
+		maxD = limit
+	}
+
+	// Chỉ số k được dịch thêm offset để không âm
+	offset := maxD + 1
+	size := 2*maxD + 3
+	if cap(d.vf) < size {
+		d.vf = make([]int, size)
+		d.vb = make([]int, size)
+	}
+	vf, vb := d.vf[:size], d.vb[:size]
+	vf[offset+1], vb[offset+1] = 0, 0
+
+	for step := 0; step <= maxD; step++ {
+		// Đi từ đầu
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vf[offset+k-1] < vf[offset+k+1]) {
+				px = vf[offset+k+1] // đi xuống: insert
+			} else {
+				px = vf[offset+k-1] + 1 // sang phải: delete
+			}
+			py := px - k
+			ex, ey := px, py
+			for ex < n && ey < m && d.a[aLo+ex] == d.b[bLo+ey] {
+				ex++
+				ey++
+			}
+			vf[offset+k] = ex
+
+			// Phía từ cuối đã đi step-1 bước trên đường chéo tương ứng
+			if c := delta - k; odd && c >= -(step-1) && c <= step-1 && ex+vb[offset+c] >= n {
+				edits = 2*step - 1
+				if edits > maxEdits {
+					return 0, 0, 0, 0, 0, false
+				}
+				return edits, aLo + px, bLo + py, aLo + ex, bLo + ey, true
+			}
+		}
+
+		// Đi từ cuối
+		for c := -step; c <= step; c += 2 {
+			var px int
+			if c == -step || (c != step && vb[offset+c-1] < vb[offset+c+1]) {
+				px = vb[offset+c+1]
+			} else {
+				px = vb[offset+c-1] + 1
+			}
+			py := px - c
+			ex, ey := px, py
+			for ex < n && ey < m && d.a[aHi-1-ex] == d.b[bHi-1-ey] {
+				ex++
+				ey++
+			}
+			vb[offset+c] = ex
+
+			if k := delta - c; !odd && k >= -step && k <= step && ex+vf[offset+k] >= n {
+				edits = 2 * step
+				if edits > maxEdits {
+					return 0, 0, 0, 0, 0, false
+				}
+				return edits, aHi - ex, bHi - ey, aHi - px, bHi - py, true
+			}
+		}
+	}
+	return 0, 0, 0, 0, 0, false
+}
+
+// diffOne thêm edit script của hai đoạn khác nhau không quá một dòng thêm/xóa
+func (d *differ) diffOne(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.lines = append(d.lines, DiffLine{Op: DiffEqual, Text: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	if aHi-aLo > bHi-bLo {
+		d.lines = append(d.lines, DiffLine{Op: DiffDelete, Text: d.a[aLo]})
+		aLo++
+	} else if bHi-bLo > aHi-aLo {
+		d.lines = append(d.lines, DiffLine{Op: DiffInsert, Text: d.b[bLo]})
+		bLo++
+	}
+	d.equal(aLo, aHi)
+}
+
+// equal thêm các dòng a[lo:hi] như dòng giống nhau
+func (d *differ) equal(lo, hi int) {
+	for _, text := range d.a[lo:hi] {
+		d.lines = append(d.lines, DiffLine{Op: DiffEqual, Text: text})
+	}
+}
+
+// replace thêm a[aLo:aHi] như các dòng bị xóa rồi b[bLo:bHi] như các dòng được thêm
+func (d *differ) replace(aLo, aHi, bLo, bHi int) {
+	for _, text := range d.a[aLo:aHi] {
+		d.lines = append(d.lines, DiffLine{Op: DiffDelete, Text: text})
+	}
+	for _, text := range d.b[bLo:bHi] {
+		d.lines = append(d.lines, DiffLine{Op: DiffInsert, Text: text})
+	}
+}
+
+// groupChanges sắp lại mỗi đoạn thay đổi liên tiếp để các dòng delete đứng trước các dòng insert
+func groupChanges(lines []DiffLine) []DiffLine {
+	for start := 0; start < len(lines); {
+		if lines[start].Op == DiffEqual {
+			start++
+			continue
+		}
+		end := start
+		for end < len(lines) && lines[end].Op != DiffEqual {
+			end++
+		}
+		sort.SliceStable(lines[start:end], func(i, j int) bool {
+			return lines[start+i].Op == DiffDelete && lines[start+j].Op == DiffInsert
+		})
+		start = end
+	}
+	return lines
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffLines kiểm tra diff từng dòng giữa hai phiên bản text
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []DiffLine
+	}{
+		{
+			name:    "identical texts",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want:    []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name:    "both empty",
+			oldText: "",
+			newText: "",
+			want:    nil,
+		},
+		{
+			name:    "from empty",
+			oldText: "",
+			newText: "a\nb",
+			want:    []DiffLine{{DiffInsert, "a"}, {DiffInsert, "b"}},
+		},
+		{
+			name:    "to empty",
+			oldText: "a\nb",
+			newText: "",
+			want:    []DiffLine{{DiffDelete, "a"}, {DiffDelete, "b"}},
+		},
+		{
+			name:    "changed line in the middle",
+			oldText: "one\ntwo\nthree",
+			newText: "one\n2\nthree",
+			want:    []DiffLine{{DiffEqual, "one"}, {DiffDelete, "two"}, {DiffInsert, "2"}, {DiffEqual, "three"}},
+		},
+		{
+			name:    "insert and delete at both ends",
+			oldText: "head\nx\ny",
+			newText: "x\ny\ntail",
+			want:    []DiffLine{{DiffDelete, "head"}, {DiffEqual, "x"}, {DiffEqual, "y"}, {DiffInsert, "tail"}},
+		},
+		{
+			name:    "windows line endings",
+			oldText: "a\r\nb",
+			newText: "a\nc",
+			want:    []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffLines(tt.oldText, tt.newText))
+		})
+	}
+}
+
+// TestDiffLines_ReconstructsBothSides kiểm tra diff luôn dựng lại được cả hai phiên bản
+func TestDiffLines_ReconstructsBothSides(t *testing.T) {
+	oldText := "a\nb\nc\na\nb\nb\na"
+	newText := "c\nb\na\nb\na\nc"
+
+	lines := DiffLines(oldText, newText)
+
+	var oldLines, newLines []string
+	changes := 0
+	for _, line := range lines {
+		if line.Op != DiffInsert {
+			oldLines = append(oldLines, line.Text)
+		}
+		if line.Op != DiffDelete {
+			newLines = append(newLines, line.Text)
+		}
+		if line.Op != DiffEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, splitLines(oldText), oldLines)
+	assert.Equal(t, splitLines(newText), newLines)
+	// Ví dụ kinh điển của Myers: edit script ngắn nhất có 5 thao tác
+	assert.Equal(t, 5, changes)
+}
+
+// numberedLines tạo text gồm n dòng "<prefix> <i>"
+func numberedLines(prefix string, n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s %d", prefix, i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TestDiffLines_LargeDisjoint kiểm tra hai phiên bản lớn không có dòng chung được diff nhanh, bộ nhớ tuyến tính
+// và trả về dạng thay toàn bộ
+func TestDiffLines_LargeDisjoint(t *testing.T) {
+	oldText, newText := numberedLines("old", 10000), numberedLines("new", 10000)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines := DiffLines(oldText, newText)
+	runtime.ReadMemStats(&after)
+
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(16<<20))
+	assert.Len(t, lines, 20000)
+	assert.Equal(t, DiffLine{DiffDelete, "old 0"}, lines[0])
+	assert.Equal(t, DiffLine{DiffDelete, "old 9999"}, lines[9999])
+	assert.Equal(t, DiffLine{DiffInsert, "new 0"}, lines[10000])
+	assert.Equal(t, DiffLine{DiffInsert, "new 9999"}, lines[19999])
+}
+
+// TestDiffLines_LargeFewChanges kiểm tra phiên bản lớn ít thay đổi vẫn có edit script ngắn nhất
+func TestDiffLines_LargeFewChanges(t *testing.T) {
+	oldLines := strings.Split(numberedLines("line", 10000), "\n")
+	newLines := append([]string(nil), oldLines...)
+	newLines[10] = "changed"
+	newLines = append(newLines[:5000], newLines[5001:]...)
+	newLines = append(newLines[:8000], append([]string{"added"}, newLines[8000:]...)...)
+
+	changes := 0
+	for _, line := range DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")) {
+		if line.Op != DiffEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, 4, changes)
+}
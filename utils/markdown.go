@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// markdown render CommonMark kèm bảng (GFM tables), gạch ngang và tự nhận link (autolink)
+// Raw HTML trong Markdown không được render (goldmark mặc định thay bằng comment).
+// Headings có id sinh từ nội dung (xem headingIDs) để mục lục link tới được.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Table,
+		extension.Strikethrough,
+		extension.Linkify,
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// htmlPolicy chỉ giữ các thẻ và thuộc tính an toàn cho nội dung do user viết
+// Link được thêm rel="nofollow"; class của <code> chỉ nhận dạng language-xxx của fenced code.
+var htmlPolicy = func() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return policy
+}()
+
+// RenderMarkdown chuyển Markdown sang HTML đã sanitize, an toàn để nhúng thẳng vào trang
+// Ví dụ: "**Go** <script>x</script>" -> "<p><strong>Go</strong> x</p>\n"
+func RenderMarkdown(source string) string {
+	if source == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(newParserContext())); err != nil {
+		// Convert chỉ lỗi khi ghi vào buf, không xảy ra với bytes.Buffer; fallback về text đã escape
+		return htmlPolicy.Sanitize(source)
+	}
+	return htmlPolicy.Sanitize(buf.String())
+}
+
+// parseMarkdown parse source thành AST với cùng parser và cách sinh heading id như RenderMarkdown
+func parseMarkdown(source []byte) ast.Node {
+	return markdown.Parser().Parse(text.NewReader(source), parser.WithContext(newParserContext()))
+}
+
+// newParserContext tạo parser context mới cho mỗi lần parse, heading id chỉ unique trong một document
+func newParserContext() parser.Context {
+	return parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))
+}
+
+// headingIDs sinh id cho headings từ nội dung theo cách của GenerateSlug
+// Chỉ giữ chữ Latin không dấu, số và "-" vì sanitizer bỏ id có ký tự khác;
+// id trùng được thêm hậu tố "-1", "-2", ... Ví dụ: "Cài đặt" -> "cai-dat".
+type headingIDs struct {
+	used map[string]bool
+}
+
+// Generate implement parser.IDs
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	slug := slugify(string(value), SlugOptions{MaxLength: DefaultSlugMaxLength})
+	id := strings.Join(strings.FieldsFunc(slug, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	}), "-")
+	if id == "" {
+		id = "heading"
+	}
+
+	unique := id
+	for i := 1; h.used[unique]; i++ {
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	h.used[unique] = true
+	return []byte(unique)
+}
+
+// Put implement parser.IDs, đánh dấu id đặt thủ công là đã dùng
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
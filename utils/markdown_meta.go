@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// WordsPerMinute là tốc độ đọc trung bình dùng để ước tính thời gian đọc
+const WordsPerMinute = 200
+
+// ExcerptLength là độ dài tối đa (ký tự, chưa tính "…") của đoạn trích từ body
+const ExcerptLength = 200
+
+// Heading là một heading trong Markdown, dùng để dựng mục lục
+// ID trùng với thuộc tính id của heading trong HTML do RenderMarkdown sinh ra.
+type Heading struct {
+	Level int
+	Text  string
+	ID    string
+}
+
+// MarkdownMeta là các thông tin suy ra từ nội dung Markdown
+type MarkdownMeta struct {
+	WordCount   int
+	ReadingTime int // phút, làm tròn lên; 0 nếu không có từ nào
+	Excerpt     string
+	Headings    []Heading
+}
+
+// AnalyzeMarkdown đếm từ, ước tính thời gian đọc, trích đoạn văn bản thuần và lấy headings của source
+// Chỉ nội dung hiển thị được tính: raw HTML bị bỏ qua, cú pháp Markdown không được tính là từ.
+// Excerpt lấy từ các đoạn văn (không gồm headings, code, bảng), cắt tại ranh giới từ.
+// Ví dụ: "# Intro\n\nHello **Go** world" -> 4 từ, 1 phút, excerpt "Hello Go world", heading {1, "Intro", "intro"}
+func AnalyzeMarkdown(source string) MarkdownMeta {
+	meta := MarkdownMeta{}
+	if strings.TrimSpace(source) == "" {
+		return meta
+	}
+
+	src := []byte(source)
+	doc := parseMarkdown(src)
+
+	var excerpt strings.Builder
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Heading:
+			text := plainText(n, src)
+			meta.WordCount += len(strings.Fields(text))
+			id, _ := n.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			meta.Headings = append(meta.Headings, Heading{Level: n.Level, Text: text, ID: string(idBytes)})
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph:
+			text := plainText(n, src)
+			meta.WordCount += len(strings.Fields(text))
+			// Đoạn văn nằm trong list hay blockquote vẫn được dùng cho excerpt
+			if excerpt.Len() <= ExcerptLength && text != "" {
+				if excerpt.Len() > 0 {
+					excerpt.WriteByte(' ')
+				}
+				excerpt.WriteString(text)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.TextBlock:
+			// Nội dung của list item "tight" không được bọc trong Paragraph
+			meta.WordCount += len(strings.Fields(plainText(n, src)))
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				meta.WordCount += len(strings.Fields(string(segment.Value(src))))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		}
+
+		// Ô của bảng (extension) chứa inline nodes trực tiếp
+		if node.Type() == ast.TypeBlock && node.HasChildren() && node.FirstChild().Type() == ast.TypeInline {
+			meta.WordCount += len(strings.Fields(plainText(node, src)))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if meta.WordCount > 0 {
+		meta.ReadingTime = (meta.WordCount + WordsPerMinute - 1) / WordsPerMinute
+	}
+	meta.Excerpt = truncateText(excerpt.String(), ExcerptLength)
+	return meta
+}
+
+// plainText nối text của các inline nodes con của node, bỏ cú pháp Markdown và raw HTML
+// Xuống dòng trong đoạn văn được thay bằng khoảng trắng.
+func plainText(node ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.AutoLink:
+			b.Write(n.Label(source))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// truncateText cắt text còn tối đa maxLength ký tự tại ranh giới từ, thêm "…" nếu bị cắt
+// Nếu từ đầu tiên đã dài hơn maxLength thì cắt cứng giữa từ.
+func truncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	cut := maxLength
+	if !unicode.IsSpace(runes[cut]) {
+		for cut > 0 && !unicode.IsSpace(runes[cut-1]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxLength
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
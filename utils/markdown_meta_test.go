@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnalyzeMarkdown kiểm tra đếm từ, thời gian đọc, excerpt và headings
+func TestAnalyzeMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   MarkdownMeta
+	}{
+		{
+			name:   "empty",
+			source: "  \n",
+			want:   MarkdownMeta{},
+		},
+		{
+			name:   "markdown syntax is not counted",
+			source: "# Intro\n\nHello **Go** and [Gin](https://gin-gonic.com)\nworld <b>bold</b>",
+			want: MarkdownMeta{
+				WordCount:   7,
+				ReadingTime: 1,
+				Excerpt:     "Hello Go and Gin world bold",
+				Headings:    []Heading{{Level: 1, Text: "Intro", ID: "intro"}},
+			},
+		},
+		{
+			name:   "lists, code and tables count as words but only paragraphs make the excerpt",
+			source: "- one\n- two\n\n```go\nfmt.Println(x)\n```\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n> quoted text",
+			want: MarkdownMeta{
+				WordCount:   9,
+				ReadingTime: 1,
+				Excerpt:     "quoted text",
+			},
+		},
+		{
+			name:   "headings get the same ids as RenderMarkdown",
+			source: "## Cài đặt\n\ntext\n\n### Cài đặt\n\n## `go get`",
+			want: MarkdownMeta{
+				WordCount:   7,
+				ReadingTime: 1,
+				Excerpt:     "text",
+				Headings: []Heading{
+					{Level: 2, Text: "Cài đặt", ID: "cai-dat"},
+					{Level: 3, Text: "Cài đặt", ID: "cai-dat-1"},
+					{Level: 2, Text: "go get", ID: "go-get"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AnalyzeMarkdown(tt.source))
+		})
+	}
+}
+
+// TestAnalyzeMarkdown_LongBody kiểm tra thời gian đọc làm tròn lên và excerpt bị cắt tại ranh giới từ
+func TestAnalyzeMarkdown_LongBody(t *testing.T) {
+	source := strings.Repeat("word ", 401)
+
+	meta := AnalyzeMarkdown(source)
+
+	assert.Equal(t, 401, meta.WordCount)
+	assert.Equal(t, 3, meta.ReadingTime)
+	assert.Equal(t, strings.Repeat("word ", 39)+"word…", meta.Excerpt)
+	assert.Empty(t, meta.Headings)
+}
+
+// TestRenderMarkdown_HeadingIDs kiểm tra id của headings trong HTML khớp với mục lục
+func TestRenderMarkdown_HeadingIDs(t *testing.T) {
+	html := RenderMarkdown("# Hello\n\n## Hello\n\n## 中文")
+
+	assert.Equal(t, "<h1 id=\"hello\">Hello</h1>\n<h2 id=\"hello-1\">Hello</h2>\n<h2 id=\"heading\">中文</h2>\n", html)
+}
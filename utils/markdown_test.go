@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderMarkdown kiểm tra render CommonMark, các extension và sanitize HTML
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "empty",
+			source: "",
+			want:   "",
+		},
+		{
+			name:   "emphasis",
+			source: "Learn **Go** and _Gin_",
+			want:   "<p>Learn <strong>Go</strong> and <em>Gin</em></p>\n",
+		},
+		{
+			name:   "table",
+			source: "| a | b |\n|---|---|\n| 1 | 2 |",
+			want:   "<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n<td>2</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name:   "fenced code keeps language and escapes content",
+			source: "```go\nfmt.Println(\"<b>\")\n```",
+			want:   "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;b&gt;&#34;)\n</code></pre>\n",
+		},
+		{
+			name:   "autolink",
+			source: "see https://example.com now",
+			want:   "<p>see <a href=\"https://example.com\" rel=\"nofollow\">https://example.com</a> now</p>\n",
+		},
+		{
+			name:   "raw html is dropped",
+			source: "hi <script>alert(1)</script>",
+			want:   "<p>hi alert(1)</p>\n",
+		},
+		{
+			name:   "javascript links are removed",
+			source: "[click](javascript:alert(1))",
+			want:   "<p>click</p>\n",
+		},
+		{
+			name:   "event handler attributes are removed",
+			source: "<img src=x onerror=alert(1)>",
+			want:   "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RenderMarkdown(tt.source))
+		})
+	}
+}
@@ -1,28 +1,67 @@
 package utils
 
 import (
-	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
-// GenerateSlug tạo slug từ title
-// Ví dụ: "Hello World!" -> "hello-world"
-func GenerateSlug(title string) string {
-	// Chuyển về lowercase
-	slug := strings.ToLower(title)
+// DefaultSlugMaxLength là độ dài tối đa (tính theo ký tự) của slug sinh từ title
+// Chừa chỗ cho hậu tố "-N" của GenerateUniqueSlug trong cột slug VARCHAR(255).
+const DefaultSlugMaxLength = 100
 
-	// Loại bỏ các ký tự đặc biệt, chỉ giữ lại chữ cái, số, và khoảng trắng
-	reg := regexp.MustCompile(`[^a-z0-9\s-]`)
-	slug = reg.ReplaceAllString(slug, "")
+// SlugOptions cấu hình cách sinh slug
+type SlugOptions struct {
+	// MaxLength giới hạn số ký tự của slug, cắt tại ranh giới từ; <= 0 là không giới hạn
+	MaxLength int
+	// Transliterations là các bảng chuyển tự cho chữ không phải Latin (ví dụ CyrillicTransliteration)
+	// Bảng sau được ưu tiên hơn bảng trước nếu cùng một ký tự.
+	Transliterations []map[rune]string
+}
+
+// DefaultSlugOptions là cấu hình GenerateSlug dùng
+var DefaultSlugOptions = SlugOptions{
+	MaxLength: DefaultSlugMaxLength,
+}
+
+// latinFolds là các chữ Latin không tách được dấu bằng Unicode normalization
+// (đ không phải "d + dấu" nên NFD không tách được)
+var latinFolds = map[rune]string{
+	'đ': "d",
+	'ð': "d",
+	'ø': "o",
+	'ł': "l",
+	'æ': "ae",
+	'œ': "oe",
+	'ß': "ss",
+	'þ': "th",
+}
+
+// CyrillicTransliteration là bảng chuyển tự chữ Kirin sang Latin
+// Ví dụ: "Привет мир" -> "privet-mir"
+var CyrillicTransliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
 
-	// Thay thế khoảng trắng và dấu gạch ngang liên tiếp bằng một dấu gạch ngang
-	reg = regexp.MustCompile(`[\s-]+`)
-	slug = reg.ReplaceAllString(slug, "-")
+// GenerateSlug tạo slug từ title với DefaultSlugOptions
+// Ví dụ: "Hello World!" -> "hello-world", "Tin tức thể thao" -> "tin-tuc-the-thao"
+func GenerateSlug(title string) string {
+	return GenerateSlugWithOptions(title, DefaultSlugOptions)
+}
 
-	// Loại bỏ dấu gạch ngang ở đầu và cuối
-	slug = strings.Trim(slug, "-")
+// GenerateSlugWithOptions tạo slug từ title
+// Các bước: lowercase, chuyển tự theo bảng, bỏ dấu (NFD rồi loại combining marks),
+// giữ lại chữ cái và số Unicode, các ký tự còn lại thành dấu gạch ngang, rồi cắt theo MaxLength.
+// Chữ không có trong bảng chuyển tự (ví dụ chữ Hán) được giữ nguyên thay vì bị xóa.
+func GenerateSlugWithOptions(title string, opts SlugOptions) string {
+	slug := slugify(title, opts)
 
 	// Nếu slug rỗng, trả về "article"
 	if slug == "" {
@@ -32,6 +71,84 @@ func GenerateSlug(title string) string {
 	return slug
 }
 
+// slugify là phần chính của GenerateSlugWithOptions, trả về "" nếu title không có chữ hoặc số nào
+func slugify(title string, opts SlugOptions) string {
+	var b strings.Builder
+	pendingDash := false
+
+	writeRune := func(r rune) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+			return
+		}
+		// Dấu câu, khoảng trắng, ký hiệu đều là ranh giới từ
+		pendingDash = true
+	}
+
+	for _, r := range strings.ToLower(title) {
+		for _, fr := range foldRune(r, opts.Transliterations) {
+			writeRune(fr)
+		}
+	}
+
+	return truncateSlug(b.String(), opts.MaxLength)
+}
+
+// foldRune chuyển tự và bỏ dấu một ký tự đã lowercase
+// Ký tự có trong bảng chuyển tự được thay theo bảng; còn lại được tách thành ký tự gốc +
+// combining marks (NFD, ví dụ "ệ" -> "e" + U+0323 + U+0302) rồi bỏ các combining marks.
+func foldRune(r rune, tables []map[rune]string) string {
+	if replacement, ok := transliterate(r, tables); ok {
+		return replacement
+	}
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+
+	var b strings.Builder
+	for _, dr := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, dr) {
+			b.WriteRune(dr)
+		}
+	}
+	return b.String()
+}
+
+// transliterate tra ký tự trong các bảng chuyển tự (bảng sau ưu tiên) rồi tới latinFolds
+func transliterate(r rune, tables []map[rune]string) (string, bool) {
+	for i := len(tables) - 1; i >= 0; i-- {
+		if replacement, ok := tables[i][r]; ok {
+			return replacement, true
+		}
+	}
+	replacement, ok := latinFolds[r]
+	return replacement, ok
+}
+
+// truncateSlug cắt slug còn tối đa maxLength ký tự tại dấu gạch ngang gần nhất
+// Nếu từ đầu tiên đã dài hơn maxLength thì cắt cứng giữa từ.
+func truncateSlug(slug string, maxLength int) string {
+	runes := []rune(slug)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return slug
+	}
+
+	// Ký tự ngay sau điểm cắt là "-" nghĩa là cắt đúng ranh giới từ
+	if runes[maxLength] == '-' {
+		return string(runes[:maxLength])
+	}
+
+	cut := string(runes[:maxLength])
+	if i := strings.LastIndexByte(cut, '-'); i > 0 {
+		return cut[:i]
+	}
+	return cut
+}
+
 // GenerateUniqueSlug tạo slug unique bằng cách thêm số vào cuối nếu cần
 // Ví dụ: "hello-world" -> "hello-world-1" nếu "hello-world" đã tồn tại
 func GenerateUniqueSlug(baseSlug string, isSlugExists func(string) bool) string {
@@ -47,8 +164,3 @@ func GenerateUniqueSlug(baseSlug string, isSlugExists func(string) bool) string
 
 	return slug
 }
-
-// isLetterOrDigit kiểm tra xem ký tự có phải là chữ cái hoặc số không
-func isLetterOrDigit(r rune) bool {
-	return unicode.IsLetter(r) || unicode.IsDigit(r)
-}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,7 +52,7 @@ func TestGenerateSlug(t *testing.T) {
 		{
 			name:  "title with unicode",
 			title: "Xin chào Việt Nam",
-			want:  "xin-cho-vit-nam", // Unicode characters may be removed or transformed
+			want:  "xin-chao-viet-nam",
 		},
 		{
 			name:  "title with trailing spaces",
@@ -73,6 +74,72 @@ func TestGenerateSlug(t *testing.T) {
 	}
 }
 
+// TestGenerateSlug_Vietnamese kiểm tra bỏ dấu tiếng Việt, bao gồm đ -> d
+func TestGenerateSlug_Vietnamese(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Tin tức thể thao hôm nay", want: "tin-tuc-the-thao-hom-nay"},
+		{title: "Đường đến Đà Lạt", want: "duong-den-da-lat"},
+		{title: "Ăn uống ở Huế: 10 món ngon!", want: "an-uong-o-hue-10-mon-ngon"},
+		{title: "Trường ĐẠI HỌC Bách Khoa", want: "truong-dai-hoc-bach-khoa"},
+		// Dạng tổ hợp (NFD) cho kết quả giống dạng dựng sẵn (NFC)
+		{title: "Vie\u0302\u0323t Nam", want: "viet-nam"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, GenerateSlug(tt.title))
+		})
+	}
+}
+
+// TestGenerateSlug_NonLatin kiểm tra chữ không phải Latin được giữ lại hoặc chuyển tự theo bảng
+func TestGenerateSlug_NonLatin(t *testing.T) {
+	// Không có bảng chuyển tự: giữ nguyên chữ thay vì trả về "article"
+	assert.Equal(t, "東京-ニュース", GenerateSlug("東京 ニュース"))
+	assert.Equal(t, "привет-мир", GenerateSlug("Привет, мир!"))
+
+	opts := SlugOptions{Transliterations: []map[rune]string{CyrillicTransliteration}}
+	assert.Equal(t, "privet-mir", GenerateSlugWithOptions("Привет, мир!", opts))
+	assert.Equal(t, "shchi-i-borshch", GenerateSlugWithOptions("Щи и борщ", opts))
+
+	// Bảng sau được ưu tiên hơn bảng trước
+	custom := map[rune]string{'щ': "sch"}
+	opts.Transliterations = append(opts.Transliterations, custom)
+	assert.Equal(t, "schi", GenerateSlugWithOptions("Щи", opts))
+}
+
+// TestGenerateSlug_MaxLength kiểm tra cắt slug tại ranh giới từ
+func TestGenerateSlug_MaxLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		maxLength int
+		want      string
+	}{
+		{name: "shorter than limit", title: "Hello World", maxLength: 20, want: "hello-world"},
+		{name: "cut on word boundary", title: "Hello beautiful world", maxLength: 13, want: "hello"},
+		{name: "limit ends exactly at word", title: "Hello beautiful world", maxLength: 15, want: "hello-beautiful"},
+		{name: "single long word is hard cut", title: "Supercalifragilistic", maxLength: 5, want: "super"},
+		{name: "counts characters not bytes", title: "Đường đến Đà Lạt", maxLength: 10, want: "duong-den"},
+		{name: "no limit", title: "Hello beautiful world", maxLength: 0, want: "hello-beautiful-world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateSlugWithOptions(tt.title, SlugOptions{MaxLength: tt.maxLength})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Default giới hạn DefaultSlugMaxLength ký tự
+	long := GenerateSlug(strings.Repeat("word ", 100))
+	assert.LessOrEqual(t, len(long), DefaultSlugMaxLength)
+	assert.False(t, strings.HasSuffix(long, "-"))
+}
+
 // TestGenerateUniqueSlug kiểm tra generate unique slug
 func TestGenerateUniqueSlug(t *testing.T) {
 	tests := []struct {
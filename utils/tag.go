@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// TagMaxLength là độ dài tối đa (tính theo ký tự) của tên tag đã chuẩn hóa
+const TagMaxLength = 40
+
+// Lỗi trả về bởi NormalizeTag, message dùng trực tiếp cho lỗi validation
+var (
+	ErrTagBlank   = errors.New("can't be blank")
+	ErrTagTooLong = errors.New("is too long (maximum is 40 characters)")
+	ErrTagInvalid = errors.New("may only contain letters, digits, spaces and - _ . + #")
+)
+
+// NormalizeTag chuẩn hóa tên tag để các cách viết khác nhau thành cùng một tag
+// Các bước: chuẩn hóa Unicode (NFC), bỏ khoảng trắng hai đầu, lowercase, gộp khoảng trắng
+// ở giữa thành một dấu "-". Chỉ chấp nhận chữ cái, số và các ký tự - _ . + #
+// Ví dụ: " Go " -> "go", "Machine  Learning" -> "machine-learning", "C++" -> "c++"
+func NormalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(norm.NFC.String(name)))
+	if name == "" {
+		return "", ErrTagBlank
+	}
+
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			pendingDash = true
+			continue
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.Is(unicode.Mn, r), strings.ContainsRune("-_.+#", r):
+		default:
+			return "", ErrTagInvalid
+		}
+		if pendingDash {
+			b.WriteByte('-')
+			pendingDash = false
+		}
+		b.WriteRune(r)
+	}
+
+	tag := b.String()
+	if utf8.RuneCountInString(tag) > TagMaxLength {
+		return "", ErrTagTooLong
+	}
+	return tag, nil
+}
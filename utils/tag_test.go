@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeTag kiểm tra chuẩn hóa tên tag và các giới hạn
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "lowercase", input: "Go", want: "go"},
+		{name: "trim", input: "  golang ", want: "golang"},
+		{name: "inner spaces become dash", input: "Machine \t Learning", want: "machine-learning"},
+		{name: "allowed symbols", input: "C++", want: "c++"},
+		{name: "dots and hash", input: "Node.js #C#", want: "node.js-#c#"},
+		{name: "unicode letters", input: "Tin Tức", want: "tin-tức"},
+		{name: "blank", input: "   ", wantErr: ErrTagBlank},
+		{name: "invalid characters", input: "go/web", wantErr: ErrTagInvalid},
+		{name: "too long", input: strings.Repeat("a", TagMaxLength+1), wantErr: ErrTagTooLong},
+		{name: "max length", input: strings.Repeat("a", TagMaxLength), want: strings.Repeat("a", TagMaxLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTag(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// isWordRune kiểm tra ký tự có thuộc một từ không (chữ cái hoặc số Unicode)
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// foldWord lowercase và bỏ dấu một từ, ví dụ "Tức" -> "tuc"
+func foldWord(word string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(word) {
+		b.WriteString(foldRune(r, nil))
+	}
+	return b.String()
+}
+
+// Tokenize tách text thành các từ đã lowercase và bỏ dấu, dùng cho tìm kiếm
+// Ví dụ: "Tin tức thể thao!" -> ["tin", "tuc", "the", "thao"]
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) }) {
+		if token := foldWord(word); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// wordSpan là vị trí (tính theo rune) của một từ trong text
+type wordSpan struct {
+	start, end int
+	matched    bool
+}
+
+// Snippet trích đoạn text quanh từ khớp đầu tiên với terms, tối đa maxLength ký tự
+// terms là các token đã qua Tokenize. Kết quả đã được HTML escape, các từ khớp được bọc
+// trong <mark></mark>; đoạn bị cắt có thêm "…" ở đầu/cuối. Không có từ nào khớp thì
+// trả về đoạn đầu của text.
+func Snippet(text string, terms []string, maxLength int) string {
+	termSet := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		termSet[term] = true
+	}
+
+	runes := []rune(text)
+	var words []wordSpan
+	firstMatch := -1
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		matched := termSet[foldWord(string(runes[start:i]))]
+		if matched && firstMatch < 0 {
+			firstMatch = len(words)
+		}
+		words = append(words, wordSpan{start: start, end: i, matched: matched})
+	}
+
+	// Chọn cửa sổ quanh từ khớp đầu tiên, lùi lại khoảng 1/4 độ dài để có ngữ cảnh
+	from, to := 0, len(runes)
+	if maxLength > 0 && len(runes) > maxLength {
+		if firstMatch >= 0 {
+			from = words[firstMatch].start - maxLength/4
+			if from < 0 {
+				from = 0
+			}
+		}
+		to = from + maxLength
+		if to > len(runes) {
+			to = len(runes)
+			from = to - maxLength
+		}
+
+		// Không cắt giữa từ
+		for _, w := range words {
+			if w.start < from && w.end > from {
+				from = w.end
+			}
+			if w.start < to && w.end > to {
+				to = w.start
+			}
+		}
+	}
+
+	// Từ khớp dài hơn cả maxLength
+	if to < from {
+		to = from
+	}
+
+	// Bỏ khoảng trắng ở hai đầu cửa sổ
+	for from < to && unicode.IsSpace(runes[from]) {
+		from++
+	}
+	for to > from && unicode.IsSpace(runes[to-1]) {
+		to--
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, w := range words {
+		if !w.matched || w.start < from || w.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[w.start:w.end])))
+		b.WriteString("</mark>")
+		pos = w.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
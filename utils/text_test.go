@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTokenize kiểm tra tách từ, lowercase và bỏ dấu
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"tin", "tuc", "the", "thao", "2024"}, Tokenize("Tin tức: THỂ THAO 2024!"))
+	assert.Equal(t, []string{"duong", "den"}, Tokenize("Đường  đến"))
+	assert.Empty(t, Tokenize("  ...  "))
+}
+
+// TestSnippet kiểm tra trích đoạn và đánh dấu từ khớp
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		terms     []string
+		maxLength int
+		want      string
+	}{
+		{
+			name:      "short text is highlighted",
+			text:      "Learn Go with Gin",
+			terms:     []string{"go"},
+			maxLength: 100,
+			want:      "Learn <mark>Go</mark> with Gin",
+		},
+		{
+			name:      "diacritics are folded when matching",
+			text:      "Tin tức thể thao",
+			terms:     []string{"tuc"},
+			maxLength: 100,
+			want:      "Tin <mark>tức</mark> thể thao",
+		},
+		{
+			name:      "html is escaped",
+			text:      "<b>Go</b> & more",
+			terms:     []string{"go"},
+			maxLength: 100,
+			want:      "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; more",
+		},
+		{
+			name:      "window around first match",
+			text:      "one two three four five six seven eight nine ten",
+			terms:     []string{"seven"},
+			maxLength: 20,
+			want:      "…six <mark>seven</mark> eight…",
+		},
+		{
+			name:      "no match returns beginning",
+			text:      "one two three four five six",
+			terms:     []string{"zero"},
+			maxLength: 10,
+			want:      "one two…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Snippet(tt.text, tt.terms, tt.maxLength))
+		})
+	}
+
+	long := Snippet(strings.Repeat("word ", 200)+"needle", []string{"needle"}, 50)
+	assert.True(t, strings.HasSuffix(long, "<mark>needle</mark>"))
+	assert.True(t, strings.HasPrefix(long, "…"))
+}